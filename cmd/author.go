@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yareeh/goodreads-cli/internal"
+	"github.com/yareeh/goodreads-cli/internal/output"
+)
+
+var (
+	authorBooks bool
+	authorPage  int
+	authorLimit int
+)
+
+var authorCmd = &cobra.Command{
+	Use:   "author <author-id|name>",
+	Short: "Show an author's details and, with --books, their bibliography",
+	Long: `Show a Goodreads author: name, bio, birth and death dates, genres and
+average rating across their books.
+
+--books adds the author's books, most popular first, with ratings and
+publication years — every page by default, or one --page (30 books per
+page), or up to --limit books. --page and --limit imply --books.
+
+The author can be given as an ID, an id-slug such as 5236.Andre_Brink, a
+goodreads.com/author/show/… URL, or a name. A name is looked up through
+search; when several authors match and none exactly, they are listed so
+you can pick an ID.
+
+Examples:
+  goodreads author 5236
+  goodreads author "André Brink" --books
+  goodreads author 5236 --books --limit 50 --json`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if authorPage < 0 || authorLimit < 0 {
+			return internal.Errorf(internal.CodeInvalidInput, "--page and --limit must not be negative")
+		}
+		withBooks := authorBooks || cmd.Flags().Changed("page") || cmd.Flags().Changed("limit")
+
+		client, err := internal.NewClient()
+		if err != nil {
+			return fmt.Errorf("creating client: %w", err)
+		}
+		id, err := resolveAuthorArg(cmd, client, strings.Join(args, " "))
+		if err != nil {
+			return err
+		}
+
+		author, err := fetchAuthor(cmd, client, id, withBooks)
+		if err != nil {
+			return err
+		}
+
+		opts := outputOptions()
+		return output.Item(os.Stdout, opts, author, nil, func(w io.Writer) error {
+			fmt.Fprintf(w, "Name:      %s\n", author.Name)
+			switch {
+			case author.BornAt != "" && author.BornIn != "":
+				fmt.Fprintf(w, "Born:      %s in %s\n", author.BornAt, author.BornIn)
+			case author.BornAt != "" || author.BornIn != "":
+				fmt.Fprintf(w, "Born:      %s%s\n", author.BornAt, author.BornIn)
+			}
+			if author.DiedAt != "" {
+				fmt.Fprintf(w, "Died:      %s\n", author.DiedAt)
+			}
+			if author.Website != "" {
+				fmt.Fprintf(w, "Website:   %s\n", author.Website)
+			}
+			if len(author.Genres) > 0 {
+				fmt.Fprintf(w, "Genres:    %s\n", strings.Join(author.Genres, ", "))
+			}
+			if author.Rating != "" {
+				fmt.Fprintf(w, "Rating:    %s (%d ratings, %d reviews)\n", author.Rating, author.RatingsCount, author.ReviewsCount)
+			}
+			if author.WorksCount > 0 {
+				fmt.Fprintf(w, "Works:     %d\n", author.WorksCount)
+			}
+			fmt.Fprintf(w, "URL:       %s\n", author.URL)
+			if author.Bio != "" {
+				fmt.Fprintf(w, "\n%s\n", author.Bio)
+			}
+
+			if withBooks {
+				fmt.Fprintln(w)
+				return output.List(w, opts, author.Books, authorBookColumns)
+			}
+			return nil
+		})
+	},
+}
+
+// resolveAuthorArg turns the author argument into an ID, searching by name
+// when it isn't an ID or URL.
+func resolveAuthorArg(cmd *cobra.Command, client *internal.Client, input string) (string, error) {
+	if id, ok := internal.AuthorIDFromInput(input); ok {
+		return id, nil
+	}
+	authors, err := client.SearchAuthors(input)
+	if err != nil {
+		return "", fmt.Errorf("searching for author %q: %w", input, err)
+	}
+	switch {
+	case len(authors) == 0:
+		return "", internal.Errorf(internal.CodeNotFound, "no author found matching %q", input)
+	case len(authors) == 1 || strings.EqualFold(authors[0].Name, strings.TrimSpace(input)):
+		fmt.Fprintf(cmd.ErrOrStderr(), "Using author %s (%s)\n", authors[0].Name, authors[0].ID)
+		return authors[0].ID, nil
+	}
+	var sb strings.Builder
+	for _, a := range authors {
+		fmt.Fprintf(&sb, "\n  %-10s %s", a.ID, a.Name)
+	}
+	return "", internal.Errorf(internal.CodeInvalidInput, "several authors match %q — pass one of these IDs:%s", input, sb.String())
+}
+
+// fetchAuthor loads the author page, and their books when withBooks is
+// set, over plain HTTP, falling back to the browser when the WAF
+// challenge blocks it.
+func fetchAuthor(cmd *cobra.Command, client *internal.Client, id string, withBooks bool) (internal.Author, error) {
+	opts := internal.AuthorBooksOptions{Page: authorPage, Limit: authorLimit}
+	author, err := client.FetchAuthor(id)
+	if err == nil && withBooks {
+		author.Books, err = client.AuthorBooks(id, opts)
+	}
+	if !errors.Is(err, internal.ErrAWSWAFChallenge) {
+		return author, err
+	}
+
+	fmt.Fprintln(cmd.ErrOrStderr(), "Launching browser (needed to clear AWS WAF challenge on author pages)…")
+	browser, err := internal.NewBrowser(browserOptions())
+	if err != nil {
+		return internal.Author{}, fmt.Errorf("launching browser: %w", err)
+	}
+	defer browser.Close()
+	author, err = browser.FetchAuthor(id)
+	if err == nil && withBooks {
+		author.Books, err = browser.AuthorBooks(id, opts)
+	}
+	return author, err
+}
+
+func init() {
+	addJSONFlag(authorCmd, "author")
+	authorCmd.Flags().BoolVar(&authorBooks, "books", false, "include the author's books")
+	authorCmd.Flags().IntVar(&authorPage, "page", 0, "fetch only this page of the author's books (30 per page)")
+	authorCmd.Flags().IntVar(&authorLimit, "limit", 0, "return up to this many of the author's books")
+	rootCmd.AddCommand(authorCmd)
+}
@@ -0,0 +1,181 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"github.com/yareeh/goodreads-cli/internal"
+	"github.com/yareeh/goodreads-cli/internal/output"
+)
+
+// Commands that take books (shelf, new, finished, book) accept several
+// book arguments, "-" for a list on stdin, or --from-file. Given more
+// than one book they run as a batch: one browser session for all of
+// them, progress on stderr, and one JSON line per book on stdout (or
+// whatever --output asks for).
+
+var batchFromFile string
+
+// batchResult is one line of a batch command's JSON Lines output.
+type batchResult struct {
+	Input string         `json:"input"`
+	ID    string         `json:"id,omitempty"`
+	OK    bool           `json:"ok"`
+	Error string         `json:"error,omitempty"`
+	Code  string         `json:"code,omitempty"` // the error's code, as in --error-format json
+	Book  *internal.Book `json:"book,omitempty"`
+}
+
+// fail records err as the result.
+func (r *batchResult) fail(err error) {
+	r.Error, r.Code = err.Error(), string(internal.CodeOf(err))
+}
+
+var batchColumns = []output.Column[batchResult]{
+	{Name: "input", Value: func(r batchResult) string { return r.Input }},
+	{Name: "id", Value: func(r batchResult) string { return r.ID }},
+	{Name: "ok", Value: func(r batchResult) string { return strconv.FormatBool(r.OK) }},
+	{Name: "error", Value: func(r batchResult) string { return r.Error }},
+}
+
+// batchOutput writes batch results, as JSON Lines unless --output asks
+// for another format. The line-oriented formats are streamed a line per
+// book as results come in; the others need every result and are written
+// by close.
+type batchOutput struct {
+	stream *output.Stream[batchResult]
+}
+
+func newBatchOutput(w io.Writer) *batchOutput {
+	opts := outputOptions()
+	if opts.Format == "" {
+		opts.Format = output.JSONL
+	}
+	return &batchOutput{output.NewStream(w, opts, batchColumns)}
+}
+
+func (o *batchOutput) add(r batchResult) error {
+	return o.stream.Write([]batchResult{r})
+}
+
+func (o *batchOutput) close() error {
+	return o.stream.Close()
+}
+
+// addBatchFlags registers --from-file on a command that takes books.
+func addBatchFlags(c *cobra.Command) {
+	c.Flags().StringVar(&batchFromFile, "from-file", "", "read books (IDs, ISBNs or URLs) from this file, one per line; '-' reads stdin")
+}
+
+// bookArgs accepts any number of book arguments, as long as there is at
+// least one or a --from-file list.
+func bookArgs(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 && batchFromFile == "" {
+		return internal.Errorf(internal.CodeInvalidInput, "requires at least one book, or --from-file")
+	}
+	return nil
+}
+
+// isBatch reports whether a command was given more than a single book.
+func isBatch(args []string) bool {
+	return len(args) != 1 || args[0] == "-" || batchFromFile != ""
+}
+
+// bookInputs returns every book a command was given, in order: its
+// arguments, with "-" replaced by the list on stdin, then --from-file.
+func bookInputs(cmd *cobra.Command, args []string) ([]string, error) {
+	var inputs []string
+	stdinRead := false
+	readList := func(path string) error {
+		var r io.Reader
+		if path == "-" {
+			if stdinRead {
+				return nil
+			}
+			stdinRead = true
+			r = cmd.InOrStdin()
+		} else {
+			f, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			r = f
+		}
+		refs, err := internal.ReadBookList(r)
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", path, err)
+		}
+		inputs = append(inputs, refs...)
+		return nil
+	}
+
+	for _, a := range args {
+		if a == "-" {
+			if err := readList("-"); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		inputs = append(inputs, a)
+	}
+	if batchFromFile != "" {
+		if err := readList(batchFromFile); err != nil {
+			return nil, err
+		}
+	}
+	if len(inputs) == 0 {
+		return nil, internal.Errorf(internal.CodeInvalidInput, "no books given")
+	}
+	return inputs, nil
+}
+
+// runBatch applies fn to every book in one logged-in browser session,
+// printing a result per book. A book that fails doesn't stop the
+// batch; the command fails at the end if any did.
+func runBatch(cmd *cobra.Command, inputs []string, fn func(*internal.Browser, string) error) error {
+	stderr := cmd.ErrOrStderr()
+	fmt.Fprintln(stderr, "Launching browser...")
+	browser, err := internal.NewBrowser(browserOptions())
+	if err != nil {
+		return fmt.Errorf("launching browser: %w", err)
+	}
+	defer browser.Close()
+
+	if err := ensureLoggedIn(browser); err != nil {
+		return err
+	}
+
+	out := newBatchOutput(cmd.OutOrStdout())
+	failed := 0
+	for i, input := range inputs {
+		res := batchResult{Input: input}
+		id, err := resolveBookArg(cmd, input)
+		if err == nil {
+			res.ID = id
+			fmt.Fprintf(stderr, "[%d/%d] %s\n", i+1, len(inputs), id)
+			err = fn(browser, id)
+		}
+		if err != nil {
+			res.fail(err)
+			failed++
+			fmt.Fprintf(stderr, "[%d/%d] %s failed: %v\n", i+1, len(inputs), input, err)
+		} else {
+			res.OK = true
+		}
+		if err := out.add(res); err != nil {
+			return err
+		}
+	}
+	if err := out.close(); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d books failed", failed, len(inputs))
+	}
+	fmt.Fprintln(stderr, "Done!")
+	return nil
+}
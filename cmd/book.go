@@ -1,38 +1,80 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/yareeh/goodreads-cli/internal"
+	"github.com/yareeh/goodreads-cli/internal/output"
 )
 
-var bookJSONFlag bool
+var (
+	bookFormat      string
+	bookConcurrency int
+)
 
 var bookCmd = &cobra.Command{
-	Use:   "book <id>",
+	Use:   "book <id>...",
 	Short: "Fetch full bibliographic details for a Goodreads book (ISBN, publisher, year, original title, …)",
 	Long: `Fetch the full bibliographic record for a Goodreads book by its legacy ID.
 
 Goodreads pages embed structured data (JSON-LD and a __NEXT_DATA__ Apollo state)
 that carries ISBN-10/13, publisher, edition publication date, original title,
-language, page count, and format. This command parses that data instead of
-relying on LLM-driven scraping.
+language, page count, and format, plus the work's series and position, genres,
+rating statistics, awards, characters and settings. This command parses that
+data instead of relying on LLM-driven scraping.
+
+--format writes the book as a bibliography record instead: bibtex,
+csl-json, ris or marcxml, ready for Zotero or a LaTeX bibliography.
+
+The book can also be given as an ISBN, ASIN or goodreads.com URL — see
+'goodreads resolve'.
+
+Several books can be given as arguments, with --from-file (one per line)
+or as "-" for stdin. They are fetched in up to --concurrency browser tabs
+at once and printed as JSON Lines in the order given, one
+{"input":…,"id":…,"ok":true,"book":{…}} or {"input":…,"ok":false,"error":…}
+per book; with --format, one bibliography of the books that were found.
+The command exits non-zero if any book failed.
 
 Example:
   goodreads book 18690730 --json
-  goodreads book 18690730`,
-	Args: cobra.ExactArgs(1),
+  goodreads book 18690730
+  goodreads book 18690730 --format bibtex >> thesis.bib
+  goodreads book 978-951-0-42066-6
+  goodreads book 18690730 2 55145261 --concurrency 3
+  goodreads book --from-file sources.txt --format bibtex > sources.bib`,
+	Args: bookArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		id := args[0]
+		if err := checkCitationFormat(bookFormat); err != nil {
+			return err
+		}
+		if bookConcurrency < 1 {
+			return internal.Errorf(internal.CodeInvalidInput, "--concurrency must be at least 1")
+		}
+		if isBatch(args) {
+			inputs, err := bookInputs(cmd, args)
+			if err != nil {
+				return err
+			}
+			return fetchBooksBatch(cmd, inputs)
+		}
+		id, err := resolveBookArg(cmd, args[0])
+		if err != nil {
+			return err
+		}
 
 		// The /book/show/<id> endpoint has been walled behind AWS WAF
 		// since July 2026 — the plain HTTP client sees a 202 JS
 		// challenge. Route through rod, which executes the challenge
 		// and lands us on the real page.
 		fmt.Fprintln(cmd.ErrOrStderr(), "Launching browser (needed to clear AWS WAF challenge on book pages)…")
-		browser, err := internal.NewBrowser(!noHeadless)
+		browser, err := internal.NewBrowser(browserOptions())
 		if err != nil {
 			return fmt.Errorf("launching browser: %w", err)
 		}
@@ -43,53 +85,200 @@ Example:
 			return fmt.Errorf("fetching book details: %w", err)
 		}
 
-		if bookJSONFlag {
-			data, err := json.MarshalIndent(book, "", "  ")
-			if err != nil {
-				return err
+		if bookFormat != "" {
+			return internal.WriteCitations(os.Stdout, bookFormat, []internal.Book{book})
+		}
+
+		return output.Item(os.Stdout, outputOptions(), book, bookDetailColumns, func(w io.Writer) error {
+			fmt.Fprintf(w, "Title:          %s\n", book.Title)
+			fmt.Fprintf(w, "Author:         %s\n", book.Author)
+			if book.OriginalTitle != "" && book.OriginalTitle != book.Title {
+				fmt.Fprintf(w, "Original title: %s\n", book.OriginalTitle)
+			}
+			if book.Year != "" {
+				if book.Month != "" {
+					fmt.Fprintf(w, "Published:      %s %s\n", book.Month, book.Year)
+				} else {
+					fmt.Fprintf(w, "Published:      %s\n", book.Year)
+				}
+			}
+			if book.Publisher != "" {
+				fmt.Fprintf(w, "Publisher:      %s\n", book.Publisher)
+			}
+			if book.ISBN13 != "" {
+				fmt.Fprintf(w, "ISBN-13:        %s\n", book.ISBN13)
+			}
+			if book.ISBN != "" && book.ISBN != book.ISBN13 {
+				fmt.Fprintf(w, "ISBN-10:        %s\n", book.ISBN)
+			}
+			if book.Pages > 0 {
+				fmt.Fprintf(w, "Pages:          %d\n", book.Pages)
+			}
+			if book.Format != "" {
+				fmt.Fprintf(w, "Format:         %s\n", book.Format)
+			}
+			if book.Language != "" {
+				fmt.Fprintf(w, "Language:       %s\n", book.Language)
+			}
+			for _, se := range book.Series {
+				if se.Position != "" {
+					fmt.Fprintf(w, "Series:         %s #%s\n", se.Name, se.Position)
+				} else {
+					fmt.Fprintf(w, "Series:         %s\n", se.Name)
+				}
+			}
+			if len(book.Genres) > 0 {
+				fmt.Fprintf(w, "Genres:         %s\n", strings.Join(book.Genres, ", "))
+			}
+			if book.Rating != "" {
+				fmt.Fprintf(w, "Rating:         %s (%d ratings, %d reviews)\n", book.Rating, book.RatingsCount, book.ReviewsCount)
+			}
+			if len(book.RatingDist) == 5 {
+				for stars := 5; stars >= 1; stars-- {
+					fmt.Fprintf(w, "  %d stars:      %d\n", stars, book.RatingDist[stars-1])
+				}
+			}
+			for _, a := range book.Awards {
+				line := a.Name
+				if a.Category != "" {
+					line += " for " + a.Category
+				}
+				if a.Year > 0 {
+					line += fmt.Sprintf(" (%d)", a.Year)
+				}
+				if a.Designation != "" {
+					line += " — " + strings.ToLower(a.Designation)
+				}
+				fmt.Fprintf(w, "Award:          %s\n", line)
+			}
+			if len(book.Characters) > 0 {
+				fmt.Fprintf(w, "Characters:     %s\n", strings.Join(book.Characters, "; "))
+			}
+			for _, p := range book.Places {
+				if p.Country != "" && p.Country != p.Name {
+					fmt.Fprintf(w, "Setting:        %s, %s\n", p.Name, p.Country)
+				} else {
+					fmt.Fprintf(w, "Setting:        %s\n", p.Name)
+				}
+			}
+			if book.URL != "" {
+				fmt.Fprintf(w, "URL:            %s\n", book.URL)
 			}
-			fmt.Println(string(data))
 			return nil
-		}
+		})
+	},
+}
 
-		fmt.Printf("Title:          %s\n", book.Title)
-		fmt.Printf("Author:         %s\n", book.Author)
-		if book.OriginalTitle != "" && book.OriginalTitle != book.Title {
-			fmt.Printf("Original title: %s\n", book.OriginalTitle)
+// bookDetailColumns are the CSV and TSV columns of a single book.
+var bookDetailColumns = []output.Column[internal.Book]{
+	bookColumns[0], bookColumns[1], bookColumns[2],
+	{Name: "isbn13", Value: func(b internal.Book) string { return b.ISBN13 }},
+	{Name: "publisher", Value: func(b internal.Book) string { return b.Publisher }},
+	{Name: "year", Value: func(b internal.Book) string { return b.Year }},
+	{Name: "pages", Value: func(b internal.Book) string { return strconv.Itoa(b.Pages) }},
+	{Name: "rating", Value: func(b internal.Book) string { return b.Rating }},
+	{Name: "url", Value: func(b internal.Book) string { return b.URL }},
+}
+
+// fetchBooksBatch fetches many books in one browser, in parallel tabs,
+// printing each result in input order as soon as it and those before it
+// are in.
+func fetchBooksBatch(cmd *cobra.Command, inputs []string) error {
+	stderr := cmd.ErrOrStderr()
+	results := make([]batchResult, len(inputs))
+	var ids []string
+	var at []int // index in inputs of each of ids
+	for i, input := range inputs {
+		results[i].Input = input
+		id, err := resolveBookArg(cmd, input)
+		if err != nil {
+			results[i].fail(err)
+			continue
 		}
-		if book.Year != "" {
-			if book.Month != "" {
-				fmt.Printf("Published:      %s %s\n", book.Month, book.Year)
-			} else {
-				fmt.Printf("Published:      %s\n", book.Year)
+		results[i].ID = id
+		ids = append(ids, id)
+		at = append(at, i)
+	}
+
+	var books []internal.Book
+	out := newBatchOutput(os.Stdout)
+	failed, printed := 0, 0
+	printUpTo := func(n int) error {
+		for ; printed < n; printed++ {
+			r := results[printed]
+			if !r.OK {
+				failed++
+				fmt.Fprintf(stderr, "%s failed: %s\n", r.Input, r.Error)
+			}
+			if bookFormat != "" {
+				if r.OK {
+					books = append(books, *r.Book)
+				}
+				continue
+			}
+			if err := out.add(r); err != nil {
+				return err
 			}
 		}
-		if book.Publisher != "" {
-			fmt.Printf("Publisher:      %s\n", book.Publisher)
-		}
-		if book.ISBN13 != "" {
-			fmt.Printf("ISBN-13:        %s\n", book.ISBN13)
-		}
-		if book.ISBN != "" && book.ISBN != book.ISBN13 {
-			fmt.Printf("ISBN-10:        %s\n", book.ISBN)
-		}
-		if book.Pages > 0 {
-			fmt.Printf("Pages:          %d\n", book.Pages)
-		}
-		if book.Format != "" {
-			fmt.Printf("Format:         %s\n", book.Format)
+		return nil
+	}
+
+	if len(ids) > 0 {
+		fmt.Fprintln(stderr, "Launching browser (needed to clear AWS WAF challenge on book pages)…")
+		browser, err := internal.NewBrowser(browserOptions())
+		if err != nil {
+			return fmt.Errorf("launching browser: %w", err)
 		}
-		if book.Language != "" {
-			fmt.Printf("Language:       %s\n", book.Language)
+		defer browser.Close()
+
+		var printErr error
+		browser.FetchBooks(ids, bookConcurrency, func(j int, book internal.Book, err error) {
+			i := at[j]
+			if err != nil {
+				results[i].fail(err)
+			} else {
+				results[i].OK, results[i].Book = true, &book
+			}
+			fmt.Fprintf(stderr, "Fetched %d of %d books…\n", j+1, len(ids))
+			if printErr == nil {
+				printErr = printUpTo(i + 1)
+			}
+		})
+		if printErr != nil {
+			return printErr
 		}
-		if book.URL != "" {
-			fmt.Printf("URL:            %s\n", book.URL)
+	}
+	if err := printUpTo(len(results)); err != nil {
+		return err
+	}
+
+	if bookFormat != "" {
+		if err := internal.WriteCitations(os.Stdout, bookFormat, books); err != nil {
+			return err
 		}
+	} else if err := out.close(); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d books failed", failed, len(inputs))
+	}
+	return nil
+}
+
+// checkCitationFormat rejects an unknown --format before any browser is
+// launched; "" means no format was asked for.
+func checkCitationFormat(format string) error {
+	if format == "" || slices.Contains(internal.CitationFormats, format) {
 		return nil
-	},
+	}
+	return internal.Errorf(internal.CodeInvalidInput, "unknown format %q — use %s", format, strings.Join(internal.CitationFormats, ", "))
 }
 
 func init() {
 	rootCmd.AddCommand(bookCmd)
-	bookCmd.Flags().BoolVar(&bookJSONFlag, "json", false, "Output the book as JSON")
+	addJSONFlag(bookCmd, "book")
+	bookCmd.Flags().StringVar(&bookFormat, "format", "", "output a bibliography record: bibtex, csl-json, ris or marcxml")
+	bookCmd.MarkFlagsMutuallyExclusive("json", "format")
+	bookCmd.Flags().IntVar(&bookConcurrency, "concurrency", 4, "with several books, fetch up to this many at once")
+	addBatchFlags(bookCmd)
 }
@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yareeh/goodreads-cli/internal"
+	"github.com/yareeh/goodreads-cli/internal/output"
+)
+
+var daemonCmd = &cobra.Command{
+	Use:   "daemon",
+	Short: "Keep one logged-in browser running for faster commands",
+	Long: `Run a background browser daemon so commands don't launch Chromium.
+
+Without the daemon every command launches Chromium, loads goodreads.com,
+restores the saved session and clears the AWS WAF challenge, which takes
+several seconds. While the daemon runs, commands instead open a tab in
+its browser, which is already logged in and past the challenge.
+
+Commands use the daemon automatically whenever it is running. Set
+GOODREADS_NO_DAEMON=1 to make a command launch its own browser anyway;
+--no-headless does too when the daemon's browser is headless, and so
+does --browser-url. The --browser-* flags given to 'daemon start' choose
+the daemon's own browser. The daemon listens on
+~/.goodreads-cli-daemon.sock and logs to ~/.goodreads-cli-daemon.log.
+
+Examples:
+  goodreads daemon start
+  goodreads daemon status
+  goodreads daemon stop`,
+}
+
+var daemonStartCmd = &cobra.Command{
+	Use:   "start",
+	Short: "Start the browser daemon in the background",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		socket := internal.DaemonSocketPath()
+		if resp, err := internal.DaemonCall(socket, internal.DaemonOpStatus); err == nil && resp.Status != nil {
+			fmt.Printf("Browser daemon already running (pid %d).\n", resp.Status.PID)
+			return nil
+		}
+
+		fmt.Println("Starting browser daemon...")
+		// The environment is inherited; only the flags need passing on.
+		runArgs := []string{"daemon", "run"}
+		if noHeadless {
+			runArgs = append(runArgs, "--no-headless")
+		}
+		if browserURL != "" {
+			runArgs = append(runArgs, "--browser-url", browserURL)
+		}
+		if browserBin != "" {
+			runArgs = append(runArgs, "--browser-bin", browserBin)
+		}
+		for _, f := range browserFlags {
+			runArgs = append(runArgs, "--browser-flag", f)
+		}
+		st, err := internal.SpawnDaemon(socket, runArgs)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Browser daemon running (pid %d).\n", st.PID)
+		if !st.LoggedIn {
+			fmt.Println("Not logged in — run 'goodreads login' to log in through the daemon.")
+		}
+		return nil
+	},
+}
+
+var daemonStopCmd = &cobra.Command{
+	Use:   "stop",
+	Short: "Stop the browser daemon",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		_, err := internal.DaemonCall(internal.DaemonSocketPath(), internal.DaemonOpStop)
+		if errors.Is(err, internal.ErrDaemonNotRunning) {
+			fmt.Println("Browser daemon is not running.")
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Println("Browser daemon stopped.")
+		return nil
+	},
+}
+
+var daemonStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show whether the browser daemon is running and logged in",
+	Long: `Show whether the browser daemon is running and logged in.
+
+Exits non-zero when the daemon is not running, so scripts can check
+for it.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		resp, err := internal.DaemonCall(internal.DaemonSocketPath(), internal.DaemonOpStatus)
+		if err != nil {
+			return err
+		}
+		st := resp.Status
+
+		return output.Item(os.Stdout, outputOptions(), st, nil, func(w io.Writer) error {
+			fmt.Fprintf(w, "Running:    pid %d, since %s (%s)\n", st.PID,
+				st.StartedAt.Local().Format("2006-01-02 15:04"), time.Since(st.StartedAt).Round(time.Second))
+			fmt.Fprintf(w, "Headless:   %v\n", st.Headless)
+			fmt.Fprintf(w, "Logged in:  %v\n", st.LoggedIn)
+			fmt.Fprintf(w, "Commands:   %d served\n", st.Connections)
+			return nil
+		})
+	},
+}
+
+// daemonRunCmd is what 'daemon start' runs in the background; it can
+// also be run directly to keep the daemon in the foreground.
+var daemonRunCmd = &cobra.Command{
+	Use:    "run",
+	Short:  "Run the browser daemon in the foreground",
+	Hidden: true,
+	Args:   cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return internal.RunDaemon(browserOptions(), internal.DaemonSocketPath())
+	},
+}
+
+func init() {
+	addJSONFlag(daemonStatusCmd, "status")
+
+	daemonCmd.AddCommand(daemonStartCmd, daemonStopCmd, daemonStatusCmd, daemonRunCmd)
+	rootCmd.AddCommand(daemonCmd)
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/yareeh/goodreads-cli/internal"
+	"github.com/yareeh/goodreads-cli/internal/output"
+)
+
+var (
+	editionsLanguage string
+	editionsFormat   string
+)
+
+var editionsCmd = &cobra.Command{
+	Use:   "editions <book-id>",
+	Short: "List every edition of a book's work",
+	Long: `List every edition of the work a book belongs to: ID, format, language,
+publisher, year and ISBNs, most popular first.
+
+--language takes Goodreads' three-letter language code (eng, fin, ger, …)
+and --format a format name such as Paperback, Hardcover, ebook or
+"Kindle Edition"; Goodreads does the filtering.
+
+Examples:
+  goodreads editions 18690730
+  goodreads editions 18690730 --language fin --format ebook
+  goodreads editions 978-951-0-42066-6 --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := resolveBookArg(cmd, args[0])
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintln(cmd.ErrOrStderr(), "Launching browser...")
+		browser, err := internal.NewBrowser(browserOptions())
+		if err != nil {
+			return fmt.Errorf("launching browser: %w", err)
+		}
+		defer browser.Close()
+
+		book, err := browser.FetchBookDetails(id)
+		if err != nil {
+			return fmt.Errorf("fetching book details: %w", err)
+		}
+		if book.WorkID == "" {
+			return internal.Errorf(internal.CodeSelectorDrift, "could not find the work of book %s", id)
+		}
+		editions, err := browser.ListEditions(book.WorkID, internal.EditionOptions{
+			Language: editionsLanguage,
+			Format:   editionsFormat,
+		})
+		if err != nil {
+			return fmt.Errorf("listing editions: %w", err)
+		}
+
+		if len(editions) == 0 && outputOptions().IsTable() {
+			fmt.Println("No editions found.")
+			return nil
+		}
+		return output.List(os.Stdout, outputOptions(), editions, editionColumns)
+	},
+}
+
+func init() {
+	addJSONFlag(editionsCmd, "editions")
+	editionsCmd.Flags().StringVar(&editionsLanguage, "language", "", "only editions in this language (code such as eng or fin)")
+	editionsCmd.Flags().StringVar(&editionsFormat, "format", "", "only editions in this format (Paperback, Hardcover, ebook, …)")
+	rootCmd.AddCommand(editionsCmd)
+}
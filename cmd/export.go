@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/yareeh/goodreads-cli/internal"
+)
+
+var (
+	exportFormat     string
+	exportFile       string
+	exportPublishers bool
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export your whole library as a Goodreads-format CSV",
+	Long: `Export every book on your shelves to a CSV file with the same columns
+as Goodreads' own "Export Library" file (goodreads_library_export.csv),
+so tools that import that file — StoryGraph, Calibre plugins and the
+like — accept it unchanged.
+
+The library is read from your shelf pages, 100 books at a time, instead
+of waiting for Goodreads' export job. Shelf positions and private notes
+are not on those pages and are left empty. Neither is the publisher:
+--publishers fetches every book's own page to fill that column in, which
+takes a few seconds per book.
+
+The CSV goes to stdout unless --file is given.
+
+Examples:
+  goodreads export --format goodreads-csv > goodreads_library_export.csv
+  goodreads export --file goodreads_library_export.csv
+  goodreads export --publishers --file goodreads_library_export.csv`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if exportFormat != "goodreads-csv" {
+			return internal.Errorf(internal.CodeInvalidInput, "unknown export format %q — use goodreads-csv", exportFormat)
+		}
+
+		fmt.Fprintln(cmd.ErrOrStderr(), "Launching browser (needed to clear AWS WAF challenge on shelf pages)…")
+		browser, err := internal.NewBrowser(browserOptions())
+		if err != nil {
+			return fmt.Errorf("launching browser: %w", err)
+		}
+		defer browser.Close()
+
+		if err := ensureLoggedIn(browser); err != nil {
+			return err
+		}
+
+		var out io.Writer = os.Stdout
+		if exportFile != "" {
+			f, err := os.Create(exportFile)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			out = f
+		}
+
+		if err := exportLibrary(cmd, browser, out); err != nil {
+			if exportFile != "" {
+				os.Remove(exportFile)
+			}
+			return err
+		}
+		return nil
+	},
+}
+
+// exportLibrary writes every shelved book to out, page by page, with
+// publishers from the book pages if --publishers is set.
+func exportLibrary(cmd *cobra.Command, browser *internal.Browser, out io.Writer) error {
+	w, err := internal.NewGoodreadsCSVWriter(out)
+	if err != nil {
+		return err
+	}
+	count := 0
+	err = browser.ListShelfPages("#ALL#", internal.ShelfListOptions{}, func(page []internal.ShelfEntry) error {
+		if exportPublishers {
+			for i, e := range page {
+				fmt.Fprintf(cmd.ErrOrStderr(), "[%d] Fetching %s (%s)…\n", count+i+1, e.Title, e.ID)
+				details, err := browser.FetchBookDetails(e.ID)
+				if err != nil {
+					fmt.Fprintf(cmd.ErrOrStderr(), "Warning: %v — leaving the publisher empty\n", err)
+					continue
+				}
+				page[i].Publisher = details.Publisher
+			}
+		}
+		count += len(page)
+		fmt.Fprintf(cmd.ErrOrStderr(), "Exported %d books…\n", count)
+		return w.Write(page)
+	})
+	if err != nil {
+		return fmt.Errorf("exporting library: %w", err)
+	}
+	return w.Flush()
+}
+
+func init() {
+	exportCmd.Flags().StringVar(&exportFormat, "format", "goodreads-csv", "export format (goodreads-csv)")
+	exportCmd.Flags().StringVarP(&exportFile, "file", "f", "", "write to this file instead of stdout")
+	exportCmd.Flags().BoolVar(&exportPublishers, "publishers", false, "fetch each book's page to fill in the Publisher column (slow)")
+	rootCmd.AddCommand(exportCmd)
+}
@@ -2,34 +2,80 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/spf13/cobra"
 	"github.com/yareeh/goodreads-cli/internal"
 )
 
+var (
+	finishedStarted  string
+	finishedFinished string
+	finishedReread   bool
+)
+
 var finishedCmd = &cobra.Command{
-	Use:   "finished <book-id>",
+	Use:   "finished <book-id>...",
 	Short: "Mark a book as finished",
-	Long:  "Mark a book as read/finished on Goodreads",
-	Args:  cobra.ExactArgs(1),
+	Long: `Mark a book as read/finished on Goodreads.
+
+--finished records the date you finished (YYYY-MM-DD) and --started the
+date you began; without them Goodreads' own defaults apply. --reread
+records a new read-through of a book that is already on "read" instead of
+overwriting the dates of the last one.
+
+Several books can be given as arguments, with --from-file or as "-" for
+stdin; they are handled in one browser session with a JSON line per
+book, as for 'goodreads shelf'.
+
+Examples:
+  goodreads finished 55145261
+  goodreads finished 55145261 --finished 2026-10-17
+  goodreads finished 55145261 --started 2026-09-01 --finished 2026-10-17
+  goodreads finished 55145261 --reread --started 2026-10-01 --finished 2026-10-17
+  goodreads finished --from-file finished-this-month.txt --finished 2026-10-31`,
+	Args: bookArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		bookID := args[0]
+		dates := internal.ReadingDates{Started: finishedStarted, Finished: finishedFinished, Reread: finishedReread}
+		if err := dates.Validate(); err != nil {
+			return err
+		}
+		if isBatch(args) {
+			inputs, err := bookInputs(cmd, args)
+			if err != nil {
+				return err
+			}
+			return runBatch(cmd, inputs, func(browser *internal.Browser, bookID string) error {
+				if err := internal.MarkRead(browser, bookID); err != nil {
+					return err
+				}
+				return setReadingDates(cmd.ErrOrStderr(), browser, bookID, dates)
+			})
+		}
+
+		bookID, err := resolveBookArg(cmd, args[0])
+		if err != nil {
+			return err
+		}
 
 		fmt.Println("Launching browser...")
-		browser, err := internal.NewBrowser(!noHeadless)
+		browser, err := internal.NewBrowser(browserOptions())
 		if err != nil {
 			return fmt.Errorf("launching browser: %w", err)
 		}
 		defer browser.Close()
 
-		if !browser.IsLoggedIn() {
-			return fmt.Errorf("not logged in — run 'goodreads login' first")
+		if err := ensureLoggedIn(browser); err != nil {
+			return err
 		}
 
 		fmt.Printf("Marking book %s as read...\n", bookID)
 		if err := internal.MarkRead(browser, bookID); err != nil {
 			return err
 		}
+		if err := setReadingDates(os.Stdout, browser, bookID, dates); err != nil {
+			return err
+		}
 
 		fmt.Println("Done!")
 		return nil
@@ -37,5 +83,9 @@ var finishedCmd = &cobra.Command{
 }
 
 func init() {
+	finishedCmd.Flags().StringVar(&finishedStarted, "started", "", "date you started reading (YYYY-MM-DD)")
+	finishedCmd.Flags().StringVar(&finishedFinished, "finished", "", "date you finished reading (YYYY-MM-DD)")
+	finishedCmd.Flags().BoolVar(&finishedReread, "reread", false, "add a new read-through instead of changing the last one")
+	addBatchFlags(finishedCmd)
 	rootCmd.AddCommand(finishedCmd)
 }
@@ -0,0 +1,214 @@
+package cmd
+
+import (
+	"cmp"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"github.com/yareeh/goodreads-cli/internal"
+	"github.com/yareeh/goodreads-cli/internal/output"
+)
+
+var (
+	importSource     string
+	importDryRun     bool
+	importCheckpoint string
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import <file.csv>",
+	Short: "Shelve the books of a Goodreads, StoryGraph or LibraryThing export",
+	Long: `Import a CSV export into your Goodreads shelves.
+
+Each row is matched to a Goodreads book by ISBN, or failing that by
+searching for its title and author; a search hit, and a book found by
+ISBN through search, must have the same title and author surname, so
+books that can't be matched are reported instead of shelved as
+something else. Matched books are put on the
+row's shelf (and any custom shelves or tags), then given its rating and
+reading dates.
+
+--source says which export the file is: goodreads (the library export,
+or 'goodreads export'), storygraph or librarything.
+
+Progress is saved to a checkpoint file after every row — by default
+<file.csv>.checkpoint.json, or --checkpoint. Rerunning the same command
+skips rows already imported or found unmatchable and retries failed
+ones, so an interrupted import picks up where it stopped.
+
+--dry-run only matches the rows and prints what would be done, without
+logging in or changing anything.
+
+Examples:
+  goodreads import goodreads_library_export.csv --source goodreads --dry-run
+  goodreads import storygraph.csv --source storygraph
+  goodreads import librarything.csv --source librarything --checkpoint lt.json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		f, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+		records, err := internal.ParseImportCSV(f, importSource)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", args[0], err)
+		}
+		if len(records) == 0 {
+			fmt.Println("No books found in the file.")
+			return nil
+		}
+
+		client, err := internal.NewClient()
+		if err != nil {
+			return fmt.Errorf("creating client: %w", err)
+		}
+		if importDryRun {
+			return dryRunImport(client, records)
+		}
+
+		path := importCheckpoint
+		if path == "" {
+			path = args[0] + ".checkpoint.json"
+		}
+		cp, err := internal.LoadImportCheckpoint(path, importSource)
+		if err != nil {
+			return err
+		}
+
+		fmt.Println("Launching browser...")
+		browser, err := internal.NewBrowser(browserOptions())
+		if err != nil {
+			return fmt.Errorf("launching browser: %w", err)
+		}
+		defer browser.Close()
+
+		if err := ensureLoggedIn(browser); err != nil {
+			return err
+		}
+
+		counts := map[string]int{}
+		for i, rec := range records {
+			if cp.Done(rec.Line) {
+				counts["skipped"]++
+				continue
+			}
+			res := importRecord(client, browser, rec)
+			counts[res.Status]++
+			fmt.Printf("[%d/%d] line %d: %s — %s\n", i+1, len(records), rec.Line, rec.Title, describeImportResult(res))
+			if err := cp.Record(res); err != nil {
+				return fmt.Errorf("saving checkpoint %s: %w", path, err)
+			}
+		}
+
+		fmt.Printf("Imported %d, unmatched %d, failed %d, already done %d.\n",
+			counts[internal.ImportStatusImported], counts[internal.ImportStatusUnmatched],
+			counts[internal.ImportStatusFailed], counts["skipped"])
+		if n := counts[internal.ImportStatusFailed]; n > 0 {
+			return fmt.Errorf("%d rows failed — rerun the same command to retry them (progress is in %s)", n, path)
+		}
+		fmt.Println("Done!")
+		return nil
+	},
+}
+
+// importRecord matches and shelves one record.
+func importRecord(client *internal.Client, browser *internal.Browser, rec internal.ImportRecord) internal.ImportResult {
+	res := internal.ImportResult{Line: rec.Line, Title: rec.Title}
+	id, _, err := client.MatchImportRecord(rec)
+	if err != nil {
+		// Only a lookup that found nothing is final; a network error or a
+		// WAF challenge is retried on the next run.
+		res.Status = internal.ImportStatusFailed
+		if internal.CodeOf(err) == internal.CodeNotFound {
+			res.Status = internal.ImportStatusUnmatched
+		}
+		res.Error = err.Error()
+		return res
+	}
+	res.BookID = id
+	if err := internal.ApplyImportRecord(browser, id, rec); err != nil {
+		res.Status = internal.ImportStatusFailed
+		res.Error = err.Error()
+		return res
+	}
+	res.Status = internal.ImportStatusImported
+	return res
+}
+
+func describeImportResult(res internal.ImportResult) string {
+	switch res.Status {
+	case internal.ImportStatusImported:
+		return "imported as " + res.BookID
+	case internal.ImportStatusUnmatched:
+		return "no match: " + res.Error
+	default:
+		return "failed: " + res.Error
+	}
+}
+
+// importPlanRow is what an import would do with one record.
+type importPlanRow struct {
+	Line     int    `json:"line"`
+	BookID   string `json:"book_id,omitempty"`
+	Match    string `json:"match"` // how the book was matched, "none", or "error" if the lookup failed
+	Shelf    string `json:"shelf"`
+	Rating   int    `json:"rating,omitempty"`
+	DateRead string `json:"date_read,omitempty"`
+	Title    string `json:"title"`
+}
+
+var importPlanColumns = []output.Column[importPlanRow]{
+	{Name: "line", Value: func(r importPlanRow) string { return strconv.Itoa(r.Line) }},
+	{Name: "book_id", Header: "BOOK", Value: func(r importPlanRow) string { return cmp.Or(r.BookID, "-") }},
+	{Name: "match", Value: func(r importPlanRow) string { return r.Match }},
+	{Name: "shelf", Value: func(r importPlanRow) string { return r.Shelf }},
+	{Name: "rating", Value: func(r importPlanRow) string {
+		if r.Rating == 0 {
+			return "-"
+		}
+		return strconv.Itoa(r.Rating)
+	}},
+	{Name: "date_read", Header: "READ", Value: func(r importPlanRow) string { return r.DateRead }},
+	{Name: "title", Value: func(r importPlanRow) string { return r.Title }},
+}
+
+// dryRunImport matches every record and prints what an import would do.
+func dryRunImport(client *internal.Client, records []internal.ImportRecord) error {
+	rows := make([]importPlanRow, 0, len(records))
+	unmatched, failed := 0, 0
+	for _, rec := range records {
+		row := importPlanRow{Line: rec.Line, Shelf: rec.Shelf, Rating: rec.Rating, DateRead: rec.DateRead, Title: rec.Title}
+		id, how, err := client.MatchImportRecord(rec)
+		switch {
+		case err == nil:
+		case internal.CodeOf(err) == internal.CodeNotFound:
+			id, how = "", "none"
+			unmatched++
+		default:
+			id, how = "", "error"
+			failed++
+			fmt.Fprintf(os.Stderr, "line %d: %v\n", rec.Line, err)
+		}
+		row.BookID, row.Match = id, how
+		rows = append(rows, row)
+	}
+	if err := output.List(os.Stdout, outputOptions(), rows, importPlanColumns); err != nil {
+		return err
+	}
+	printNote("\n%d of %d rows matched; nothing was changed (dry run).", len(records)-unmatched-failed, len(records))
+	if failed > 0 {
+		return fmt.Errorf("%d rows could not be looked up — rerun to try them again", failed)
+	}
+	return nil
+}
+
+func init() {
+	importCmd.Flags().StringVar(&importSource, "source", "", "export the file comes from: goodreads, storygraph or librarything")
+	importCmd.Flags().BoolVar(&importDryRun, "dry-run", false, "match rows and report, without changing anything")
+	importCmd.Flags().StringVar(&importCheckpoint, "checkpoint", "", "checkpoint file (default <file.csv>.checkpoint.json)")
+	_ = importCmd.MarkFlagRequired("source")
+	rootCmd.AddCommand(importCmd)
+}
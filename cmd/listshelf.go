@@ -1,14 +1,21 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
+	"os"
 
 	"github.com/spf13/cobra"
 	"github.com/yareeh/goodreads-cli/internal"
+	"github.com/yareeh/goodreads-cli/internal/output"
 )
 
-var listShelfJSONFlag bool
+var (
+	listShelfLimit   int
+	listShelfPage    int
+	listShelfAll     bool
+	listShelfFormat  string
+	listShelfOffline bool
+)
 
 var listShelfCmd = &cobra.Command{
 	Use:   "list-shelf <shelf-name>",
@@ -19,64 +26,159 @@ Goodreads renders each shelf at /review/list/<user_id>?shelf=<name>. This
 command logs in (via the saved session cookies), discovers the user ID from
 the signed-in home page, fetches the shelf, and prints the books on it.
 
+Goodreads serves at most 100 books per page. By default every page is
+fetched, with progress on stderr; the command fails if it ends up with
+fewer books than the shelf's total. --limit stops after that many books.
+--page fetches a single page, or with --limit starts there and carries
+on until the limit. --all fetches every page even when
+--page or --limit is also given, e.g. by a shell alias.
+
+JSON Lines, CSV, TSV and template output is printed page by page as
+the pages arrive; a table, JSON, YAML and bibliographies are printed
+once the whole shelf is in.
+
+--json includes everything on the shelf row, not just the table columns:
+your rating, the average rating, dates started/read/added (YYYY-MM-DD),
+every reading session, read count, shelves, review text, page count,
+ISBN and format.
+
+--format writes the shelf as a bibliography instead — bibtex, csl-json,
+ris or marcxml — for importing into Zotero or other reference managers.
+
+--offline reads the shelf from the local library that 'goodreads sync'
+keeps, without launching a browser; it is only as fresh as the last sync.
+
 Examples:
   goodreads list-shelf currently-reading
   goodreads list-shelf currently-reading --json
   goodreads list-shelf want-to-read
-  goodreads list-shelf read`,
+  goodreads list-shelf read --limit 20
+  goodreads list-shelf read --page 3
+  goodreads list-shelf thesis-sources --format bibtex > sources.bib
+  goodreads list-shelf read --offline --json`,
 	Aliases: []string{"shelf-list", "shelved"},
 	Args:    cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		shelfName := args[0]
+		if listShelfLimit < 0 || listShelfPage < 0 {
+			return internal.Errorf(internal.CodeInvalidInput, "--limit and --page must not be negative")
+		}
+		if err := checkCitationFormat(listShelfFormat); err != nil {
+			return err
+		}
+
+		opts := internal.ShelfListOptions{Page: listShelfPage, Limit: listShelfLimit}
+		if listShelfAll {
+			opts = internal.ShelfListOptions{}
+		}
+		out := newShelfOutput(shelfName)
+		if listShelfOffline {
+			books, err := offlineShelf(shelfName, opts)
+			if err != nil {
+				return err
+			}
+			if err := out.add(books); err != nil {
+				return err
+			}
+			return out.close()
+		}
 
 		// The /review/list/<user>?shelf=… endpoint has been walled
 		// behind AWS WAF since July 2026 — the plain HTTP client sees
 		// a 202 JS challenge. Route through rod, which executes the
 		// challenge and returns the real page.
 		fmt.Fprintln(cmd.ErrOrStderr(), "Launching browser (needed to clear AWS WAF challenge on shelf pages)…")
-		browser, err := internal.NewBrowser(!noHeadless)
+		browser, err := internal.NewBrowser(browserOptions())
 		if err != nil {
 			return fmt.Errorf("launching browser: %w", err)
 		}
 		defer browser.Close()
 
-		if !browser.IsLoggedIn() {
-			return fmt.Errorf("not logged in — run 'goodreads login' first")
-		}
-
-		books, err := browser.ListShelf(shelfName)
-		if err != nil {
-			return fmt.Errorf("listing shelf %q: %w", shelfName, err)
+		if err := ensureLoggedIn(browser); err != nil {
+			return err
 		}
 
-		if listShelfJSONFlag {
-			data, err := json.MarshalIndent(books, "", "  ")
-			if err != nil {
+		err = browser.ListShelfPages(shelfName, opts, func(page []internal.ShelfEntry) error {
+			if err := out.add(page); err != nil {
 				return err
 			}
-			fmt.Println(string(data))
+			fmt.Fprintf(cmd.ErrOrStderr(), "Fetched %d books…\n", out.count)
 			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("listing shelf %q: %w", shelfName, err)
 		}
+		return out.close()
+	},
+}
 
-		if len(books) == 0 {
-			fmt.Printf("No books on shelf %q.\n", shelfName)
-			return nil
-		}
+// shelfOutput prints a shelf in the format the flags ask for as its
+// pages come in: the line-oriented formats stream, and the rest —
+// including --format bibliographies — are written by close.
+type shelfOutput struct {
+	name      string
+	stream    *output.Stream[internal.ShelfEntry]
+	citations []internal.Book
+	count     int
+}
 
-		fmt.Printf("%-12s %-60s %s\n", "ID", "TITLE", "AUTHOR")
-		fmt.Printf("%-12s %-60s %s\n", "---", "-----", "------")
-		for _, b := range books {
-			title := b.Title
-			if len(title) > 58 {
-				title = title[:55] + "..."
-			}
-			fmt.Printf("%-12s %-60s %s\n", b.ID, title, b.Author)
-		}
+func newShelfOutput(name string) *shelfOutput {
+	return &shelfOutput{name: name, stream: output.NewStream(os.Stdout, outputOptions(), shelfEntryColumns)}
+}
+
+func (o *shelfOutput) add(page []internal.ShelfEntry) error {
+	o.count += len(page)
+	if listShelfFormat == "" {
+		return o.stream.Write(page)
+	}
+	for _, e := range page {
+		o.citations = append(o.citations, e.Book)
+	}
+	return nil
+}
+
+func (o *shelfOutput) close() error {
+	if listShelfFormat != "" {
+		return internal.WriteCitations(os.Stdout, listShelfFormat, o.citations)
+	}
+	if o.count == 0 && outputOptions().IsTable() {
+		fmt.Printf("No books on shelf %q.\n", o.name)
 		return nil
-	},
+	}
+	return o.stream.Close()
+}
+
+// offlineShelf reads a shelf from the local library, applying --page and
+// --limit the way the live listing does.
+func offlineShelf(shelfName string, opts internal.ShelfListOptions) ([]internal.ShelfEntry, error) {
+	lib, err := openLibrary()
+	if err != nil {
+		return nil, err
+	}
+	defer lib.Close()
+	books, err := lib.Shelf(shelfName)
+	if err != nil {
+		return nil, fmt.Errorf("reading shelf %q from the library: %w", shelfName, err)
+	}
+	if opts.Page > 0 {
+		books = books[min((opts.Page-1)*100, len(books)):]
+		if opts.Limit == 0 {
+			books = books[:min(100, len(books))]
+		}
+	}
+	if opts.Limit > 0 && len(books) > opts.Limit {
+		books = books[:opts.Limit]
+	}
+	return books, nil
 }
 
 func init() {
 	rootCmd.AddCommand(listShelfCmd)
-	listShelfCmd.Flags().BoolVar(&listShelfJSONFlag, "json", false, "Output the shelf as JSON")
+	addJSONFlag(listShelfCmd, "shelf")
+	listShelfCmd.Flags().IntVar(&listShelfLimit, "limit", 0, "stop after this many books")
+	listShelfCmd.Flags().IntVar(&listShelfPage, "page", 0, "fetch only this page (100 books per page), or with --limit start there")
+	listShelfCmd.Flags().BoolVar(&listShelfAll, "all", false, "fetch every page, overriding --page and --limit (the default without them)")
+	listShelfCmd.Flags().StringVar(&listShelfFormat, "format", "", "output a bibliography: bibtex, csl-json, ris or marcxml")
+	listShelfCmd.MarkFlagsMutuallyExclusive("json", "format")
+	listShelfCmd.Flags().BoolVar(&listShelfOffline, "offline", false, "read the shelf from the local library ('goodreads sync') instead of Goodreads")
 }
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/spf13/cobra"
 	"github.com/yareeh/goodreads-cli/internal"
@@ -18,7 +19,7 @@ var loginCmd = &cobra.Command{
 		}
 
 		fmt.Println("Launching browser...")
-		browser, err := internal.NewBrowser(!noHeadless)
+		browser, err := internal.NewBrowser(browserOptions())
 		if err != nil {
 			return fmt.Errorf("launching browser: %w", err)
 		}
@@ -33,6 +34,10 @@ var loginCmd = &cobra.Command{
 		if err := internal.Login(browser, cfg); err != nil {
 			return err
 		}
+		// A fresh session; let auto-login try again straight away next time.
+		if err := internal.ClearLoginState(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v — automatic login may wait out its cooldown\n", err)
+		}
 
 		fmt.Println("Login successful! Session saved.")
 		return nil
@@ -16,6 +16,10 @@ var logoutCmd = &cobra.Command{
 			return err
 		}
 		fmt.Println("Logged out. Session removed.")
+		// The daemon's browser still holds the session cookies.
+		if _, err := internal.DaemonCall(internal.DaemonSocketPath(), internal.DaemonOpStop); err == nil {
+			fmt.Println("Browser daemon stopped.")
+		}
 		return nil
 	},
 }
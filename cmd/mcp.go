@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/yareeh/goodreads-cli/internal"
+	"github.com/yareeh/goodreads-cli/internal/mcp"
+	"github.com/yareeh/goodreads-cli/internal/version"
+)
+
+var mcpCmd = &cobra.Command{
+	Use:   "mcp",
+	Short: "Run a Model Context Protocol server on stdin/stdout",
+	Long: `Serve goodreads-cli's operations as MCP tools over stdio, for AI agents
+and MCP clients (Claude Desktop, editors, …) to call directly.
+
+Tools: search, book_details, list_shelf, shelve, update_progress and
+post_reply. Each takes and returns typed JSON with a schema; books are
+returned in the same shape as 'book --json'.
+
+One browser is launched on the first tool call that needs it and kept
+for the whole session, so later calls skip the startup. Log in with
+'goodreads login' first. The server exits when stdin closes.
+
+Example client configuration:
+  {"mcpServers": {"goodreads": {"command": "goodreads", "args": ["mcp"]}}}`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		session, err := internal.NewSession(browserOptions())
+		if err != nil {
+			return err
+		}
+		defer session.Close()
+		session.AutoLogin = autoLogin
+
+		server := &mcp.Server{Name: "goodreads-cli", Version: version.Current(), Tools: mcp.Tools(session), Reset: session.Close}
+		if err := server.Serve(cmd.InOrStdin(), cmd.OutOrStdout()); err != nil {
+			return fmt.Errorf("serving MCP: %w", err)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(mcpCmd)
+}
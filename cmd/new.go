@@ -2,40 +2,103 @@ package cmd
 
 import (
 	"fmt"
+	"io"
+	"os"
 
 	"github.com/spf13/cobra"
 	"github.com/yareeh/goodreads-cli/internal"
 )
 
+var (
+	newStarted string
+	newReread  bool
+)
+
 var newCmd = &cobra.Command{
-	Use:   "new <book-id>",
+	Use:   "new <book-id>...",
 	Short: "Start reading a new book",
-	Long:  "Mark a book as currently reading on Goodreads",
-	Args:  cobra.ExactArgs(1),
+	Long: `Mark a book as currently reading on Goodreads.
+
+--started records the start date (YYYY-MM-DD) instead of leaving it blank.
+--reread starts a new read-through of a book you've read before, keeping
+the dates of the earlier reads. There is no --finished: a finish date
+would put the book on "read", so use 'goodreads finished' with --started
+and --finished to record a read that is already over.
+
+Several books can be given as arguments, with --from-file or as "-" for
+stdin; they are handled in one browser session with a JSON line per
+book, as for 'goodreads shelf'.
+
+Examples:
+  goodreads new 55145261
+  goodreads new 55145261 --started 2026-10-01
+  goodreads new 55145261 --reread --started 2026-10-15
+  goodreads new 55145261 18690730 --started 2026-10-01`,
+	Args: bookArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		bookID := args[0]
+		dates := internal.ReadingDates{Started: newStarted, Reread: newReread}
+		if err := dates.Validate(); err != nil {
+			return err
+		}
+		if isBatch(args) {
+			inputs, err := bookInputs(cmd, args)
+			if err != nil {
+				return err
+			}
+			return runBatch(cmd, inputs, func(browser *internal.Browser, bookID string) error {
+				if err := internal.MarkCurrentlyReading(browser, bookID); err != nil {
+					return err
+				}
+				return setReadingDates(cmd.ErrOrStderr(), browser, bookID, dates)
+			})
+		}
+
+		bookID, err := resolveBookArg(cmd, args[0])
+		if err != nil {
+			return err
+		}
 
 		fmt.Println("Launching browser...")
-		browser, err := internal.NewBrowser(!noHeadless)
+		browser, err := internal.NewBrowser(browserOptions())
 		if err != nil {
 			return fmt.Errorf("launching browser: %w", err)
 		}
 		defer browser.Close()
 
-		if !browser.IsLoggedIn() {
-			return fmt.Errorf("not logged in — run 'goodreads login' first")
+		if err := ensureLoggedIn(browser); err != nil {
+			return err
 		}
 
 		fmt.Printf("Marking book %s as currently reading...\n", bookID)
 		if err := internal.MarkCurrentlyReading(browser, bookID); err != nil {
 			return err
 		}
+		if err := setReadingDates(os.Stdout, browser, bookID, dates); err != nil {
+			return err
+		}
 
 		fmt.Println("Done!")
 		return nil
 	},
 }
 
+// setReadingDates records dates after a shelf change, printing what it
+// does to w; a no-op when no date flag was given.
+func setReadingDates(w io.Writer, browser *internal.Browser, bookID string, dates internal.ReadingDates) error {
+	if dates == (internal.ReadingDates{}) {
+		return nil
+	}
+	if dates.Reread {
+		fmt.Fprintln(w, "Adding a new read-through...")
+	} else {
+		fmt.Fprintln(w, "Setting reading dates...")
+	}
+	return internal.SetReadingDates(browser, bookID, dates)
+}
+
 func init() {
+	newCmd.Flags().StringVar(&newStarted, "started", "", "date you started reading (YYYY-MM-DD)")
+	newCmd.Flags().BoolVar(&newReread, "reread", false, "add a new read-through instead of changing the last one")
+	addBatchFlags(newCmd)
 	rootCmd.AddCommand(newCmd)
 }
@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yareeh/goodreads-cli/internal"
+)
+
+var (
+	notesDir     string
+	notesShelf   string
+	notesRefresh bool
+)
+
+var notesCmd = &cobra.Command{
+	Use:   "notes",
+	Short: "Keep Markdown notes (e.g. an Obsidian vault) in sync with your shelves",
+	Long: `Generate and update one Markdown note per shelved book.
+
+Examples:
+  goodreads notes sync --dir ~/vault/Books
+  goodreads notes sync --dir ~/vault/Books --shelf read --refresh`,
+}
+
+var notesSyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Write or update a Markdown note for every shelved book",
+	Long: `Write one Markdown file per shelved book into --dir, with YAML
+frontmatter Obsidian (Dataview, Properties) can query:
+
+  ---
+  id: "18690730"
+  title: Tuokio tuulessa
+  author: André Brink
+  isbn13: "9789510085660"
+  pages: 350
+  shelves:
+    - read
+    - favorites
+  rating: 5
+  date_read: "2026-02-28"
+  cover: https://images-na.ssl-images-amazon.com/...
+  ---
+
+New notes are named after the book's title and get a title heading as
+their body. On later runs only these frontmatter keys are rewritten:
+the note's body, any frontmatter keys you added and the file name are
+kept, and notes are found by their id even after renaming. Files whose
+frontmatter hasn't changed are not touched.
+
+Shelves, rating and read date come from your shelf pages. Title,
+author, ISBN-13, pages and cover come from each book's page, which is
+only fetched for new notes (or ones missing an ISBN-13 or cover) — use
+--refresh to fetch them again for every book.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if notesDir == "" {
+			return internal.Errorf(internal.CodeInvalidInput, "--dir is required")
+		}
+		dir, err := expandHome(notesDir)
+		if err != nil {
+			return err
+		}
+		idx, err := internal.LoadNoteIndex(dir)
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", dir, err)
+		}
+
+		return withLoggedInBrowser(func(browser *internal.Browser) error {
+			var entries []internal.ShelfEntry
+			err := browser.ListShelfPages(notesShelf, internal.ShelfListOptions{}, func(page []internal.ShelfEntry) error {
+				entries = append(entries, page...)
+				fmt.Printf("Read %d books from shelf %q…\n", len(entries), notesShelf)
+				return nil
+			})
+			if err != nil {
+				return fmt.Errorf("listing shelf %q: %w", notesShelf, err)
+			}
+
+			var res internal.NotesSyncResult
+			for i, e := range entries {
+				details, fetched := noteDetails(idx, e.ID)
+				if !fetched {
+					fmt.Printf("[%d/%d] Fetching %s (%s)…\n", i+1, len(entries), e.Title, e.ID)
+					if details, err = browser.FetchBookDetails(e.ID); err != nil {
+						fmt.Fprintf(os.Stderr, "Warning: %v — using shelf data only\n", err)
+						details = internal.Book{}
+					}
+				}
+				if err := idx.SyncNote(internal.NewNoteFrontmatter(e, details), &res); err != nil {
+					return err
+				}
+			}
+			fmt.Printf("Created %d, updated %d, unchanged %d notes in %s.\n", res.Created, res.Updated, res.Unchanged, dir)
+			return nil
+		})
+	},
+}
+
+// noteDetails returns the book details already in an existing note, so
+// its page needn't be fetched again. ok is false when the page should be
+// fetched: no note yet, one missing an ISBN-13 or cover, or --refresh.
+func noteDetails(idx *internal.NoteIndex, bookID string) (internal.Book, bool) {
+	if notesRefresh {
+		return internal.Book{}, false
+	}
+	fm, ok := idx.Read(bookID)
+	if !ok || fm.ISBN13 == "" || fm.Cover == "" {
+		return internal.Book{}, false
+	}
+	return internal.Book{Title: fm.Title, Author: fm.Author, ISBN13: fm.ISBN13, Pages: fm.Pages, ImageURL: fm.Cover}, true
+}
+
+// expandHome replaces a leading "~/" with the user's home directory, for
+// paths passed as --dir=~/... where the shell doesn't expand it.
+func expandHome(path string) (string, error) {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~")), nil
+}
+
+func init() {
+	notesSyncCmd.Flags().StringVar(&notesDir, "dir", "", "directory to write the notes to (e.g. ~/vault/Books)")
+	notesSyncCmd.Flags().StringVar(&notesShelf, "shelf", "#ALL#", "only sync books on this shelf")
+	notesSyncCmd.Flags().BoolVar(&notesRefresh, "refresh", false, "fetch every book's page again, not just new ones")
+
+	notesCmd.AddCommand(notesSyncCmd)
+	rootCmd.AddCommand(notesCmd)
+}
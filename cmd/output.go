@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yareeh/goodreads-cli/internal"
+	"github.com/yareeh/goodreads-cli/internal/output"
+)
+
+// Table columns of the things commands list. Names are the JSON field
+// names, so --columns reads the same whatever the format.
+
+var bookColumns = []output.Column[internal.Book]{
+	{Name: "id", Value: func(b internal.Book) string { return b.ID }},
+	{Name: "title", Value: func(b internal.Book) string { return b.Title }},
+	{Name: "author", Value: func(b internal.Book) string { return b.Author }},
+}
+
+var fullSearchColumns = []output.Column[internal.Book]{
+	bookColumns[0], bookColumns[1], bookColumns[2],
+	{Name: "rating", Value: func(b internal.Book) string { return b.Rating }, Right: true},
+	{Name: "ratings_count", Header: "RATINGS", Value: func(b internal.Book) string { return strconv.Itoa(b.RatingsCount) }, Right: true},
+	{Name: "year", Value: func(b internal.Book) string { return b.Year }},
+}
+
+var authorBookColumns = []output.Column[internal.Book]{
+	bookColumns[0], bookColumns[1], fullSearchColumns[3], fullSearchColumns[4], fullSearchColumns[5],
+}
+
+var editionColumns = []output.Column[internal.Book]{
+	bookColumns[0],
+	{Name: "format", Value: func(b internal.Book) string { return b.Format }},
+	{Name: "language", Value: func(b internal.Book) string { return b.Language }},
+	{Name: "publisher", Value: func(b internal.Book) string { return b.Publisher }},
+	{Name: "year", Value: func(b internal.Book) string { return b.Year }},
+	{Name: "isbn13", Header: "ISBN-13", Value: func(b internal.Book) string { return b.ISBN13 }},
+	{Name: "isbn", Header: "ISBN/ASIN", Value: func(b internal.Book) string {
+		if b.ISBN != "" {
+			return b.ISBN
+		}
+		return b.ASIN
+	}},
+}
+
+var shelfEntryColumns = []output.Column[internal.ShelfEntry]{
+	{Name: "id", Value: func(e internal.ShelfEntry) string { return e.ID }},
+	{Name: "title", Value: func(e internal.ShelfEntry) string { return e.Title }},
+	{Name: "author", Value: func(e internal.ShelfEntry) string { return e.Author }},
+}
+
+var localSearchColumns = append(shelfEntryColumns[:3:3], output.Column[internal.ShelfEntry]{
+	Name: "shelves", Value: func(e internal.ShelfEntry) string { return strings.Join(e.Shelves, ", ") },
+})
+
+var shelfColumns = []output.Column[internal.Shelf]{
+	{Name: "name", Header: "SHELF", Value: func(s internal.Shelf) string { return s.Name }},
+	{Name: "book_count", Header: "BOOKS", Value: func(s internal.Shelf) string { return strconv.Itoa(s.BookCount) }, Right: true},
+	{Name: "exclusive", Value: func(s internal.Shelf) string {
+		if s.Exclusive {
+			return "yes"
+		}
+		return ""
+	}},
+}
+
+// printNote prints a message that goes with the output, such as "No
+// results found.": on stdout under a table, on stderr otherwise so that
+// JSON, CSV and the like stay parseable.
+func printNote(format string, args ...any) {
+	w := os.Stderr
+	if outputOptions().IsTable() {
+		w = os.Stdout
+	}
+	fmt.Fprintf(w, format+"\n", args...)
+}
+
+// addJSONFlag gives a command --json, short for --output json.
+func addJSONFlag(c *cobra.Command, what string) {
+	c.Flags().Bool("json", false, "output the "+what+" as JSON (same as --output json)")
+}
@@ -20,20 +20,24 @@ var postReplyCmd = &cobra.Command{
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		topicID := args[0]
+		bookID, err := resolveOptionalBookArg(cmd, replyBookID)
+		if err != nil {
+			return err
+		}
 
 		fmt.Println("Launching browser...")
-		browser, err := internal.NewBrowser(!noHeadless)
+		browser, err := internal.NewBrowser(browserOptions())
 		if err != nil {
 			return fmt.Errorf("launching browser: %w", err)
 		}
 		defer browser.Close()
 
-		if !browser.IsLoggedIn() {
-			return fmt.Errorf("not logged in — run 'goodreads login' first")
+		if err := ensureLoggedIn(browser); err != nil {
+			return err
 		}
 
 		fmt.Printf("Posting reply to topic %s...\n", topicID)
-		if err := internal.PostReply(browser, topicID, replyMessage, replyBookID, replyAuthorID); err != nil {
+		if err := internal.PostReply(browser, topicID, replyMessage, bookID, replyAuthorID); err != nil {
 			return err
 		}
 
@@ -58,19 +62,24 @@ var postTopicCmd = &cobra.Command{
 The --url flag should be the full new-topic URL from Goodreads, e.g.:
   https://www.goodreads.com/topic/new?context_id=220-goodreads-librarians-group&context_type=Group&topic[folder_id]=120471`,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		bookID, err := resolveOptionalBookArg(cmd, topicBookID)
+		if err != nil {
+			return err
+		}
+
 		fmt.Println("Launching browser...")
-		browser, err := internal.NewBrowser(!noHeadless)
+		browser, err := internal.NewBrowser(browserOptions())
 		if err != nil {
 			return fmt.Errorf("launching browser: %w", err)
 		}
 		defer browser.Close()
 
-		if !browser.IsLoggedIn() {
-			return fmt.Errorf("not logged in — run 'goodreads login' first")
+		if err := ensureLoggedIn(browser); err != nil {
+			return err
 		}
 
 		fmt.Println("Creating new topic...")
-		if err := internal.PostNewTopic(browser, topicURL, topicSubject, topicMessage, topicBookID, topicAuthorID); err != nil {
+		if err := internal.PostNewTopic(browser, topicURL, topicSubject, topicMessage, bookID, topicAuthorID); err != nil {
 			return err
 		}
 
@@ -81,7 +90,7 @@ The --url flag should be the full new-topic URL from Goodreads, e.g.:
 
 func init() {
 	postReplyCmd.Flags().StringVar(&replyMessage, "message", "", "message to post")
-	postReplyCmd.Flags().StringVar(&replyBookID, "book", "", "book ID to reference")
+	postReplyCmd.Flags().StringVar(&replyBookID, "book", "", "book to reference (ID, ISBN, ASIN or URL)")
 	postReplyCmd.Flags().StringVar(&replyAuthorID, "author", "", "author ID to reference")
 	_ = postReplyCmd.MarkFlagRequired("message")
 	rootCmd.AddCommand(postReplyCmd)
@@ -89,7 +98,7 @@ func init() {
 	postTopicCmd.Flags().StringVar(&topicURL, "url", "", "full new-topic URL from Goodreads")
 	postTopicCmd.Flags().StringVar(&topicSubject, "subject", "", "topic subject/title")
 	postTopicCmd.Flags().StringVar(&topicMessage, "message", "", "topic body message")
-	postTopicCmd.Flags().StringVar(&topicBookID, "book", "", "book ID to reference")
+	postTopicCmd.Flags().StringVar(&topicBookID, "book", "", "book to reference (ID, ISBN, ASIN or URL)")
 	postTopicCmd.Flags().StringVar(&topicAuthorID, "author", "", "author ID to reference")
 	_ = postTopicCmd.MarkFlagRequired("url")
 	_ = postTopicCmd.MarkFlagRequired("subject")
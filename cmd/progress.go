@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/yareeh/goodreads-cli/internal"
+	"github.com/yareeh/goodreads-cli/internal/output"
+)
+
+var (
+	progressPage    int
+	progressPercent int
+	progressComment string
+)
+
+var progressCmd = &cobra.Command{
+	Use:   "progress <book-id>",
+	Short: "Update or show your reading progress on a book",
+	Long: `Post a reading-progress update for a book you're currently reading,
+or show the latest one.
+
+With --page or --percent (and optionally --comment) a status update is
+posted, just like "Update progress" on the Goodreads home page. With
+neither, the most recent update for the book is printed.
+
+Examples:
+  goodreads progress 55145261 --page 123
+  goodreads progress 55145261 --percent 40 --comment "Loving the essay on Canada geese"
+  goodreads progress 55145261
+  goodreads progress 55145261 --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		bookID, err := resolveBookArg(cmd, args[0])
+		if err != nil {
+			return err
+		}
+		posting := cmd.Flags().Changed("page") || cmd.Flags().Changed("percent")
+		update := internal.ProgressUpdate{Page: progressPage, Percent: progressPercent, Comment: progressComment}
+		if posting {
+			if err := update.Validate(); err != nil {
+				return err
+			}
+		} else if progressComment != "" {
+			return internal.Errorf(internal.CodeInvalidInput, "--comment needs --page or --percent")
+		}
+
+		if posting {
+			return withLoggedInBrowser(func(browser *internal.Browser) error {
+				fmt.Printf("Updating progress on book %s...\n", bookID)
+				return internal.UpdateProgress(browser, bookID, update)
+			})
+		}
+
+		// Showing progress keeps stdout for the result so JSON output
+		// can be piped.
+		fmt.Fprintln(cmd.ErrOrStderr(), "Launching browser...")
+		browser, err := internal.NewBrowser(browserOptions())
+		if err != nil {
+			return fmt.Errorf("launching browser: %w", err)
+		}
+		defer browser.Close()
+
+		if err := ensureLoggedIn(browser); err != nil {
+			return err
+		}
+
+		p, ok, err := internal.GetProgress(browser, bookID)
+		if err != nil {
+			return err
+		}
+		var latest *internal.ReadingProgress // null in JSON when there is none
+		if ok {
+			latest = &p
+		}
+		return output.Item(os.Stdout, outputOptions(), latest, nil, func(w io.Writer) error {
+			switch {
+			case !ok:
+				fmt.Fprintf(w, "No progress updates for book %s.\n", bookID)
+			case p.CurrentPage > 0 && p.TotalPages > 0:
+				fmt.Fprintf(w, "Page %d of %d (%d%%)\n", p.CurrentPage, p.TotalPages, p.Percent)
+			case p.CurrentPage > 0:
+				fmt.Fprintf(w, "Page %d\n", p.CurrentPage)
+			default:
+				fmt.Fprintf(w, "%d%% done\n", p.Percent)
+			}
+			return nil
+		})
+	},
+}
+
+func init() {
+	progressCmd.Flags().IntVar(&progressPage, "page", 0, "page you're on")
+	progressCmd.Flags().IntVar(&progressPercent, "percent", 0, "percentage read (0-100)")
+	progressCmd.Flags().StringVar(&progressComment, "comment", "", "comment to post with the update")
+	addJSONFlag(progressCmd, "latest progress")
+	progressCmd.MarkFlagsMutuallyExclusive("page", "percent")
+	rootCmd.AddCommand(progressCmd)
+}
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/yareeh/goodreads-cli/internal"
+	"github.com/yareeh/goodreads-cli/internal/output"
+)
+
+var resolveCmd = &cobra.Command{
+	Use:   "resolve <isbn|asin|url|id>",
+	Short: "Print the Goodreads book ID for an ISBN, ASIN or book URL",
+	Long: `Resolve a book reference to its Goodreads legacy ID.
+
+Every command that takes a <book-id> also accepts an ISBN-10 or ISBN-13
+(hyphens allowed), a Kindle ASIN, a goodreads.com/book/show/… URL or an
+id-slug such as 55145261-the-anthropocene-reviewed. This command prints
+the mapping without doing anything else. Works without login.
+
+Examples:
+  goodreads resolve 9780525555216
+  goodreads resolve https://www.goodreads.com/book/show/55145261-the-anthropocene-reviewed
+  goodreads resolve B08KHGDQ4M --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := internal.NewClient()
+		if err != nil {
+			return fmt.Errorf("creating client: %w", err)
+		}
+		ref, err := client.ResolveBookID(args[0])
+		if err != nil {
+			return err
+		}
+
+		return output.Item(os.Stdout, outputOptions(), ref, bookRefColumns, func(w io.Writer) error {
+			_, err := fmt.Fprintf(w, "%s\t%s\t%s\n", ref.Input, ref.Kind, ref.ID)
+			return err
+		})
+	},
+}
+
+var bookRefColumns = []output.Column[internal.BookRef]{
+	{Name: "input", Value: func(r internal.BookRef) string { return r.Input }},
+	{Name: "kind", Value: func(r internal.BookRef) string { return r.Kind }},
+	{Name: "id", Value: func(r internal.BookRef) string { return r.ID }},
+}
+
+// resolveBookArg turns a command's book argument into a legacy ID,
+// accepting everything 'goodreads resolve' does. Plain IDs and URLs
+// resolve without a network round trip; when an ISBN or ASIN is looked
+// up, the mapping is reported on stderr.
+func resolveBookArg(cmd *cobra.Command, input string) (string, error) {
+	kind, value, err := internal.ClassifyBookInput(input)
+	if err != nil {
+		return "", err
+	}
+	if kind == internal.BookRefID || kind == internal.BookRefURL {
+		return value, nil
+	}
+
+	client, err := internal.NewClient()
+	if err != nil {
+		return "", fmt.Errorf("creating client: %w", err)
+	}
+	ref, err := client.ResolveBookID(input)
+	if err != nil {
+		return "", err
+	}
+	fmt.Fprintf(cmd.ErrOrStderr(), "Resolved %s %s to book %s\n", ref.Kind, ref.Value, ref.ID)
+	return ref.ID, nil
+}
+
+// resolveOptionalBookArg is resolveBookArg for optional --book flags,
+// passing an empty value through.
+func resolveOptionalBookArg(cmd *cobra.Command, input string) (string, error) {
+	if input == "" {
+		return "", nil
+	}
+	return resolveBookArg(cmd, input)
+}
+
+func init() {
+	addJSONFlag(resolveCmd, "mapping")
+	rootCmd.AddCommand(resolveCmd)
+}
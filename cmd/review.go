@@ -0,0 +1,211 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yareeh/goodreads-cli/internal"
+)
+
+var (
+	reviewRating   int
+	reviewBodyFile string
+	reviewSpoiler  bool
+	reviewShelf    string
+)
+
+var reviewCmd = &cobra.Command{
+	Use:   "review <book-id>",
+	Short: "Rate a book and write its review",
+	Long: `Rate a book and write or replace its review.
+
+The review body comes from --body-file (use - for stdin). Without it,
+$VISUAL or $EDITOR opens on the current review text — unless --rating or
+--spoiler is given, in which case only those fields change. Anything you
+don't pass keeps its current value.
+
+--shelf puts the book on a shelf first; Goodreads files reviewed books that
+aren't on any shelf under "read".
+
+Examples:
+  goodreads review 55145261 --rating 4 --body-file review.md
+  goodreads review 55145261 --rating 5 --shelf read
+  goodreads review 55145261 --body-file review.md --spoiler
+  goodreads review 55145261`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runReview(cmd, args[0], false)
+	},
+}
+
+var reviewEditCmd = &cobra.Command{
+	Use:   "edit <book-id>",
+	Short: "Edit an existing review",
+	Long: `Edit your existing review of a book.
+
+Without --body-file, $VISUAL or $EDITOR opens on the current review text
+— unless --rating or --spoiler is given, in which case only those fields
+change. Anything not passed is kept.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runReview(cmd, args[0], true)
+	},
+}
+
+var reviewClearCmd = &cobra.Command{
+	Use:     "clear <book-id>",
+	Aliases: []string{"delete"},
+	Short:   "Clear your rating and review of a book",
+	Long: `Clear your rating and review text for a book, by saving the review
+form with no rating and an empty text.
+
+Goodreads keeps a review for every shelved book, so this empties it
+rather than deleting it: the book stays on its shelves with its reading
+dates. Use 'goodreads unshelve <book-id> --all' to remove it from your
+library.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		bookID, err := resolveBookArg(cmd, args[0])
+		if err != nil {
+			return err
+		}
+		return withLoggedInBrowser(func(browser *internal.Browser) error {
+			fmt.Printf("Clearing review of book %s...\n", bookID)
+			return internal.SaveReview(browser, internal.Review{BookID: bookID})
+		})
+	},
+}
+
+// runReview merges the flags into the current review of the book and saves
+// it. mustExist makes it an error for the book to have no review yet.
+func runReview(cmd *cobra.Command, input string, mustExist bool) error {
+	flags := cmd.Flags()
+	bookID, err := resolveBookArg(cmd, input)
+	if err != nil {
+		return err
+	}
+	if reviewRating < 0 || reviewRating > 5 {
+		return internal.Errorf(internal.CodeInvalidInput, "--rating must be between 1 and 5 (or 0 to clear it)")
+	}
+	var body *string
+	if reviewBodyFile != "" {
+		text, err := readBodyFile(reviewBodyFile)
+		if err != nil {
+			return err
+		}
+		body = &text
+	}
+
+	return withLoggedInBrowser(func(browser *internal.Browser) error {
+		if reviewShelf != "" {
+			fmt.Printf("Adding book %s to shelf '%s'...\n", bookID, reviewShelf)
+			if err := internal.AddToShelf(browser, bookID, reviewShelf); err != nil {
+				return err
+			}
+		}
+
+		r, err := internal.GetReview(browser, bookID)
+		if err != nil {
+			return err
+		}
+		if mustExist && r.Rating == 0 && r.Body == "" {
+			return internal.Errorf(internal.CodeNotFound, "book %s has no review yet — use 'goodreads review %s'", bookID, bookID)
+		}
+
+		onlyFields := flags.Changed("rating") || flags.Changed("spoiler")
+		if body == nil && !onlyFields {
+			text, err := editText(r.Body)
+			if err != nil {
+				return err
+			}
+			body = &text
+		}
+		if body != nil {
+			r.Body = *body
+		}
+		if flags.Changed("rating") {
+			r.Rating = reviewRating
+		}
+		if flags.Changed("spoiler") {
+			r.Spoiler = reviewSpoiler
+		}
+
+		fmt.Printf("Saving review of book %s...\n", bookID)
+		return internal.SaveReview(browser, r)
+	})
+}
+
+// readBodyFile reads a review body from path, or from stdin for "-".
+func readBodyFile(path string) (string, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return "", fmt.Errorf("reading review body: %w", err)
+	}
+	return string(data), nil
+}
+
+// editText opens $VISUAL or $EDITOR (falling back to vi) on a temporary
+// file holding initial and returns what the user saved. An empty result
+// aborts, as with git commit.
+func editText(initial string) (string, error) {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+
+	f, err := os.CreateTemp("", "goodreads-review-*.md")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.WriteString(initial); err != nil {
+		f.Close()
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		return "", err
+	}
+
+	// $EDITOR may carry arguments, e.g. "code --wait".
+	parts := strings.Fields(editor)
+	c := exec.Command(parts[0], append(parts[1:], f.Name())...)
+	c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := c.Run(); err != nil {
+		return "", fmt.Errorf("running editor %q: %w", editor, err)
+	}
+
+	data, err := os.ReadFile(f.Name())
+	if err != nil {
+		return "", err
+	}
+	text := strings.TrimSpace(string(data))
+	if text == "" {
+		return "", internal.Errorf(internal.CodeInvalidInput, "empty review, aborting — use 'goodreads review clear' to remove a review")
+	}
+	return text, nil
+}
+
+func init() {
+	for _, c := range []*cobra.Command{reviewCmd, reviewEditCmd} {
+		c.Flags().IntVar(&reviewRating, "rating", 0, "star rating, 1-5 (0 clears it)")
+		c.Flags().StringVar(&reviewBodyFile, "body-file", "", "read the review text from this file (- for stdin)")
+		c.Flags().BoolVar(&reviewSpoiler, "spoiler", false, "mark the review as containing spoilers")
+	}
+	reviewCmd.Flags().StringVar(&reviewShelf, "shelf", "", "put the book on this shelf before reviewing it")
+
+	reviewCmd.AddCommand(reviewEditCmd, reviewClearCmd)
+	rootCmd.AddCommand(reviewCmd)
+}
@@ -1,30 +1,163 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 
+	"github.com/yareeh/goodreads-cli/internal"
+	"github.com/yareeh/goodreads-cli/internal/output"
 	"github.com/yareeh/goodreads-cli/internal/version"
 )
 
-var noHeadless bool
+var (
+	noHeadless   bool
+	browserURL   string
+	browserBin   string
+	browserFlags []string
+
+	outputFormat   string
+	outputTemplate string
+	outputColumns  []string
+
+	errorFormat string
+	autoLogin   bool
+)
 
 var rootCmd = &cobra.Command{
 	Use:     "goodreads",
 	Short:   "A CLI for interacting with Goodreads",
 	Long:    "goodreads-cli lets you search books, manage shelves, track reading progress, and post to discussions — all from the command line.",
 	Version: version.Current(),
+	// Execute reports errors itself, in the --error-format asked for.
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if errorFormat != "text" && errorFormat != "json" {
+			return internal.Errorf(internal.CodeInvalidInput, "unknown error format %q (want text or json)", errorFormat)
+		}
+		// A command's own --json flag is shorthand for --output json, and
+		// --template alone implies --output template.
+		if f := cmd.Flags().Lookup("json"); f != nil && f.Value.String() == "true" {
+			if outputFormat != "" && outputFormat != output.JSON {
+				return internal.Errorf(internal.CodeInvalidInput, "--json conflicts with --output %s", outputFormat)
+			}
+			outputFormat = output.JSON
+		}
+		if outputTemplate != "" && outputFormat == "" {
+			outputFormat = output.Template
+		}
+		return outputOptions().Validate()
+	},
 }
 
+// commandRan is set once a command's RunE starts; an error before then
+// is cobra rejecting the arguments or flags.
+var commandRan bool
+
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+	markRun(rootCmd)
+	cmd, stack, err := execute()
+	if err == nil {
+		return
+	}
+	if stack == nil && !commandRan && internal.CodeOf(err) == internal.CodeError {
+		err = internal.WithCode(internal.CodeInvalidInput, err)
+	}
+	if !rootCmd.PersistentFlags().Changed("error-format") {
+		errorFormat = errorFormatArg(os.Args[1:])
+	}
+	report := internal.Report(err)
+	if errorFormat == "json" {
+		json.NewEncoder(os.Stderr).Encode(report)
+	} else {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		if stack != nil {
+			os.Stderr.Write(stack)
+		} else if !commandRan {
+			fmt.Fprintf(os.Stderr, "Run '%s --help' for usage.\n", cmd.CommandPath())
+		}
+	}
+	os.Exit(report.ExitCode)
+}
+
+// execute runs the command line. A panic is returned as an error, with
+// the stack for text output, so it is reported like any other failure.
+func execute() (*cobra.Command, []byte, error) {
+	cmd, err := internal.Guard(rootCmd.ExecuteC, nil)
+	if cmd == nil {
+		cmd = rootCmd
+	}
+	return cmd, internal.PanicStack(err), err
+}
+
+// errorFormatArg finds --error-format in args, for errors such as an
+// unknown command that cobra reports before parsing flags.
+func errorFormatArg(args []string) string {
+	for i, arg := range args {
+		if v, ok := strings.CutPrefix(arg, "--error-format="); ok {
+			return v
+		}
+		if arg == "--error-format" && i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+	return "text"
+}
+
+// markRun wraps the RunE of c and its subcommands to set commandRan.
+func markRun(c *cobra.Command) {
+	if run := c.RunE; run != nil {
+		c.RunE = func(cmd *cobra.Command, args []string) error {
+			commandRan = true
+			return run(cmd, args)
+		}
+	}
+	for _, sub := range c.Commands() {
+		markRun(sub)
+	}
+}
+
+// browserOptions is the browser the global flags ask for, with
+// GOODREADS_BROWSER_* environment variables filling in the rest.
+func browserOptions() internal.BrowserOptions {
+	return internal.BrowserOptions{
+		Headless: !noHeadless,
+		URL:      browserURL,
+		Bin:      browserBin,
+		Flags:    browserFlags,
+	}.WithEnv()
+}
+
+// ensureLoggedIn fails with internal.ErrNotLoggedIn unless browser has a
+// Goodreads session, logging in again first with --auto-login or
+// auto_login: true in the config.
+func ensureLoggedIn(browser *internal.Browser) error {
+	return internal.EnsureLoggedIn(browser, autoLogin, os.Stderr)
+}
+
+// outputOptions is the output the global flags ask for, sized to the
+// terminal stdout is.
+func outputOptions() output.Options {
+	return output.Options{
+		Format:   outputFormat,
+		Template: outputTemplate,
+		Columns:  outputColumns,
+		Width:    output.TerminalWidth(os.Stdout),
 	}
 }
 
 func init() {
 	rootCmd.PersistentFlags().BoolVar(&noHeadless, "no-headless", false, "show the browser window for debugging")
+	rootCmd.PersistentFlags().StringVar(&browserURL, "browser-url", "", "connect to a running Chrome at this DevTools URL (ws://… or host:port) instead of launching one [$GOODREADS_BROWSER_URL]")
+	rootCmd.PersistentFlags().StringVar(&browserBin, "browser-bin", "", "Chrome/Chromium binary to launch [$GOODREADS_BROWSER_BIN]")
+	rootCmd.PersistentFlags().StringArrayVar(&browserFlags, "browser-flag", nil, "extra Chrome flag to launch with, e.g. proxy-server=http://proxy:3128 (repeatable) [$GOODREADS_BROWSER_FLAGS]")
+	rootCmd.PersistentFlags().BoolVar(&autoLogin, "auto-login", false, "log in again with the configured credentials when the saved session has expired, at most every 15 minutes (also auto_login: true in the config)")
+	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "", "output format: table (default), json, jsonl, yaml, csv, tsv or template")
+	rootCmd.PersistentFlags().StringVar(&outputTemplate, "template", "", "Go template applied to each result, e.g. '{{.Title}} by {{.Author}}' (implies --output template)")
+	rootCmd.PersistentFlags().StringVar(&errorFormat, "error-format", "text", `how to report errors on stderr: text, or json for {"code":…,"message":…,"exit_code":…}`)
+	rootCmd.PersistentFlags().StringSliceVar(&outputColumns, "columns", nil, "comma-separated columns for table, csv and tsv output; any JSON field name works")
 }
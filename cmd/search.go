@@ -1,62 +1,128 @@
 package cmd
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
+	"os"
 	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/yareeh/goodreads-cli/internal"
+	"github.com/yareeh/goodreads-cli/internal/output"
 )
 
-var searchJSONFlag bool
+var (
+	searchFull  bool
+	searchPage  int
+	searchLimit int
+	searchField string
+	searchLocal bool
+)
 
 var searchCmd = &cobra.Command{
 	Use:   "search <query>",
 	Short: "Search for books on Goodreads",
-	Args:  cobra.MinimumNArgs(1),
+	Long: `Search for books on Goodreads.
+
+By default the query goes to Goodreads' autocomplete, which is fast but
+returns only a handful of matches and no ratings. --full uses the full
+search results page instead: every match, 20 per page, with the average
+rating, number of ratings and publication year. --page, --limit and
+--field imply --full.
+
+Examples:
+  goodreads search "project hail mary"
+  goodreads search --full "project hail mary"
+  goodreads search --field author "andy weir" --limit 50
+  goodreads search --field title dune --page 2
+  goodreads search --local herbert`,
+	Args: cobra.MinimumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		query := strings.Join(args, " ")
+		if searchPage < 0 || searchLimit < 0 {
+			return internal.Errorf(internal.CodeInvalidInput, "--page and --limit must not be negative")
+		}
+		if searchLocal {
+			return localSearch(query)
+		}
+		full := searchFull || cmd.Flags().Changed("page") || cmd.Flags().Changed("limit") || cmd.Flags().Changed("field")
 
 		client, err := internal.NewClient()
 		if err != nil {
 			return fmt.Errorf("creating client: %w", err)
 		}
 
-		books, err := client.Search(query)
+		var books []internal.Book
+		if full {
+			books, err = fullSearch(cmd, client, query)
+		} else {
+			books, err = client.Search(query)
+		}
 		if err != nil {
 			return fmt.Errorf("search failed: %w", err)
 		}
 
-		if len(books) == 0 {
+		if len(books) == 0 && outputOptions().IsTable() {
 			fmt.Println("No results found.")
 			return nil
 		}
-
-		if searchJSONFlag {
-			data, err := json.MarshalIndent(books, "", "  ")
-			if err != nil {
-				return err
-			}
-			fmt.Println(string(data))
-			return nil
+		cols := bookColumns
+		if full {
+			cols = fullSearchColumns
 		}
+		return output.List(os.Stdout, outputOptions(), books, cols)
+	},
+}
 
-		fmt.Printf("%-12s %-50s %s\n", "ID", "TITLE", "AUTHOR")
-		fmt.Printf("%-12s %-50s %s\n", "---", "-----", "------")
-		for _, b := range books {
-			title := b.Title
-			if len(title) > 48 {
-				title = title[:45] + "..."
-			}
-			fmt.Printf("%-12s %-50s %s\n", b.ID, title, b.Author)
-		}
+// fullSearch queries the /search results page over plain HTTP, falling
+// back to the browser when the WAF challenge blocks it.
+func fullSearch(cmd *cobra.Command, client *internal.Client, query string) ([]internal.Book, error) {
+	opts := internal.SearchOptions{Field: searchField, Page: searchPage, Limit: searchLimit}
+	books, err := client.SearchFull(query, opts)
+	if !errors.Is(err, internal.ErrAWSWAFChallenge) {
+		return books, err
+	}
 
+	fmt.Fprintln(cmd.ErrOrStderr(), "Launching browser (needed to clear AWS WAF challenge on search pages)…")
+	browser, err := internal.NewBrowser(browserOptions())
+	if err != nil {
+		return nil, fmt.Errorf("launching browser: %w", err)
+	}
+	defer browser.Close()
+	return browser.SearchFull(query, opts)
+}
+
+// localSearch searches the local library.
+func localSearch(query string) error {
+	lib, err := openLibrary()
+	if err != nil {
+		return err
+	}
+	defer lib.Close()
+	books, err := lib.Search(query)
+	if err != nil {
+		return fmt.Errorf("searching the library: %w", err)
+	}
+	if searchLimit > 0 && len(books) > searchLimit {
+		books = books[:searchLimit]
+	}
+
+	if len(books) == 0 && outputOptions().IsTable() {
+		fmt.Println("No results found.")
 		return nil
-	},
+	}
+	return output.List(os.Stdout, outputOptions(), books, localSearchColumns)
 }
 
 func init() {
 	rootCmd.AddCommand(searchCmd)
-	searchCmd.Flags().BoolVar(&searchJSONFlag, "json", false, "Output results as JSON")
+	addJSONFlag(searchCmd, "results")
+	searchCmd.Flags().BoolVar(&searchFull, "full", false, "use the full search results page (ratings, years, paging)")
+	searchCmd.Flags().IntVar(&searchPage, "page", 0, "fetch this page of full search results (20 per page)")
+	searchCmd.Flags().IntVar(&searchLimit, "limit", 0, "return up to this many full search results, across pages")
+	searchCmd.Flags().StringVar(&searchField, "field", "all", "field to search: title, author or all")
+	searchCmd.Flags().BoolVar(&searchLocal, "local", false, "search your own books in the local library ('goodreads sync')")
+	searchCmd.MarkFlagsMutuallyExclusive("local", "full")
+	searchCmd.MarkFlagsMutuallyExclusive("local", "page")
+	searchCmd.MarkFlagsMutuallyExclusive("local", "field")
 }
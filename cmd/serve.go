@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yareeh/goodreads-cli/internal"
+	"github.com/yareeh/goodreads-cli/internal/api"
+	"github.com/yareeh/goodreads-cli/internal/version"
+)
+
+var (
+	serveAddr  string
+	serveToken string
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve a local REST/JSON API",
+	Long: `Run an HTTP server answering JSON requests, for dashboards, bots and
+scripts:
+
+  GET  /books/{id}            book details (ID, ISBN, ASIN or URL)
+  GET  /search?q=…            search; add full=true, field=, limit= for full results
+  GET  /shelves/{name}        books on a shelf; limit=, page=
+  POST /shelves/{name}/books  add {"book": "…"} to a shelf
+  GET  /openapi.json          OpenAPI 3.1 description
+
+Responses are the same JSON as the --json output of the matching
+commands; errors are {"error": "…", "code": "…"} with status 400 for
+bad requests, 404 and 429 passed on from Goodreads, and 502 for other
+Goodreads or browser failures. One browser is kept for the whole server
+and requests are handled one at a time.
+
+With --token (or GOODREADS_API_TOKEN) every request except
+/openapi.json must send "Authorization: Bearer <token>". A token is
+required to listen on anything other than a loopback address.
+
+Requests must name localhost, a loopback address or the --addr host, and
+POST bodies must be sent as Content-Type: application/json, so web pages
+open in your browser can't use the API behind your back.
+
+Examples:
+  goodreads serve
+  goodreads serve --addr :8080 --token s3cret
+  curl 'localhost:8080/search?q=dune'`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		token := serveToken
+		if token == "" {
+			token = os.Getenv("GOODREADS_API_TOKEN")
+		}
+		if err := api.CheckAddr(serveAddr, token); err != nil {
+			return err
+		}
+
+		session, err := internal.NewSession(browserOptions())
+		if err != nil {
+			return err
+		}
+		defer session.Close()
+		session.AutoLogin = autoLogin
+
+		ln, err := net.Listen("tcp", serveAddr)
+		if err != nil {
+			return err
+		}
+		srv := &http.Server{
+			Handler:           (&api.Server{Backend: session, Token: token, Version: version.Current(), Addr: serveAddr, Reset: session.Close}).Handler(),
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+		fmt.Printf("Serving the Goodreads API on http://%s (token required: %v)\n", ln.Addr(), token != "")
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		go func() {
+			<-ctx.Done()
+			shutdown, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+			srv.Shutdown(shutdown)
+		}()
+		if err := srv.Serve(ln); !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		fmt.Println("Stopped.")
+		return nil
+	},
+}
+
+func init() {
+	serveCmd.Flags().StringVar(&serveAddr, "addr", "127.0.0.1:8080", "address to listen on")
+	serveCmd.Flags().StringVar(&serveToken, "token", "", "require this bearer token on every request [$GOODREADS_API_TOKEN]")
+	rootCmd.AddCommand(serveCmd)
+}
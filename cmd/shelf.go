@@ -10,22 +10,48 @@ import (
 var shelfName string
 
 var shelfCmd = &cobra.Command{
-	Use:   "shelf <book-id>",
-	Short: "Add a book to a shelf",
-	Long:  "Add a book to a Goodreads shelf (currently-reading, want-to-read, read)",
-	Args:  cobra.ExactArgs(1),
+	Use:   "shelf <book-id>...",
+	Short: "Add books to a shelf",
+	Long: `Add a book to a Goodreads shelf (currently-reading, want-to-read, read,
+or any custom shelf).
+
+Several books can be given as arguments, with --from-file (one per line),
+or as "-" to read them from stdin. They are shelved in one browser
+session, printing a JSON line per book — {"input":…,"id":…,"ok":true} or
+{"input":…,"ok":false,"error":…} — and the command exits non-zero if
+any book failed.
+
+Examples:
+  goodreads shelf 55145261 --shelf read
+  goodreads shelf 55145261 2 18690730 --shelf book-club
+  goodreads shelf --from-file to-read.txt
+  cut -f1 ids.tsv | goodreads shelf - --shelf sci-fi`,
+	Args: bookArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		bookID := args[0]
+		if isBatch(args) {
+			inputs, err := bookInputs(cmd, args)
+			if err != nil {
+				return err
+			}
+			return runBatch(cmd, inputs, func(browser *internal.Browser, bookID string) error {
+				return internal.AddToShelf(browser, bookID, shelfName)
+			})
+		}
+
+		bookID, err := resolveBookArg(cmd, args[0])
+		if err != nil {
+			return err
+		}
 
 		fmt.Println("Launching browser...")
-		browser, err := internal.NewBrowser(!noHeadless)
+		browser, err := internal.NewBrowser(browserOptions())
 		if err != nil {
 			return fmt.Errorf("launching browser: %w", err)
 		}
 		defer browser.Close()
 
-		if !browser.IsLoggedIn() {
-			return fmt.Errorf("not logged in — run 'goodreads login' first")
+		if err := ensureLoggedIn(browser); err != nil {
+			return err
 		}
 
 		fmt.Printf("Adding book %s to shelf '%s'...\n", bookID, shelfName)
@@ -39,6 +65,7 @@ var shelfCmd = &cobra.Command{
 }
 
 func init() {
-	shelfCmd.Flags().StringVar(&shelfName, "shelf", "want-to-read", "shelf name (currently-reading, want-to-read, read)")
+	shelfCmd.Flags().StringVar(&shelfName, "shelf", "want-to-read", "shelf name (currently-reading, want-to-read, read, or a custom shelf)")
+	addBatchFlags(shelfCmd)
 	rootCmd.AddCommand(shelfCmd)
 }
@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/yareeh/goodreads-cli/internal"
+	"github.com/yareeh/goodreads-cli/internal/output"
+)
+
+var shelvesExclusiveFlag bool
+
+var shelvesCmd = &cobra.Command{
+	Use:   "shelves",
+	Short: "Manage your shelves (list, create, rename, delete)",
+	Long: `Manage the logged-in user's shelves.
+
+The three built-in shelves (want-to-read, currently-reading, read) are
+exclusive: a book is always on exactly one of them. Custom shelves are
+non-exclusive by default, so a book can be on "read" and also on "sci-fi"
+and "book-club-2026" — add books to them with 'goodreads shelf <id> --shelf
+<name>'.
+
+Examples:
+  goodreads shelves list
+  goodreads shelves create book-club-2026
+  goodreads shelves create did-not-finish --exclusive
+  goodreads shelves rename scifi sci-fi
+  goodreads shelves delete book-club-2025`,
+}
+
+var shelvesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List your shelves with book counts",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fmt.Fprintln(cmd.ErrOrStderr(), "Launching browser (needed to clear AWS WAF challenge on shelf pages)…")
+		browser, err := internal.NewBrowser(browserOptions())
+		if err != nil {
+			return fmt.Errorf("launching browser: %w", err)
+		}
+		defer browser.Close()
+
+		if err := ensureLoggedIn(browser); err != nil {
+			return err
+		}
+
+		shelves, err := browser.ListShelves()
+		if err != nil {
+			return fmt.Errorf("listing shelves: %w", err)
+		}
+
+		return output.List(os.Stdout, outputOptions(), shelves, shelfColumns)
+	},
+}
+
+var shelvesCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create a custom shelf",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return withLoggedInBrowser(func(browser *internal.Browser) error {
+			fmt.Printf("Creating shelf '%s'...\n", args[0])
+			return internal.CreateShelf(browser, args[0], shelvesExclusiveFlag)
+		})
+	},
+}
+
+var shelvesRenameCmd = &cobra.Command{
+	Use:   "rename <old-name> <new-name>",
+	Short: "Rename a custom shelf",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return withLoggedInBrowser(func(browser *internal.Browser) error {
+			fmt.Printf("Renaming shelf '%s' to '%s'...\n", args[0], args[1])
+			return internal.RenameShelf(browser, args[0], args[1])
+		})
+	},
+}
+
+var shelvesDeleteCmd = &cobra.Command{
+	Use:   "delete <name>",
+	Short: "Delete a custom shelf (its books stay in your library)",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return withLoggedInBrowser(func(browser *internal.Browser) error {
+			fmt.Printf("Deleting shelf '%s'...\n", args[0])
+			return internal.DeleteShelf(browser, args[0])
+		})
+	},
+}
+
+// withLoggedInBrowser runs fn against a fresh logged-in browser session and
+// prints "Done!" on success — the shape shared by the shelf-mutating
+// subcommands.
+func withLoggedInBrowser(fn func(*internal.Browser) error) error {
+	fmt.Println("Launching browser...")
+	browser, err := internal.NewBrowser(browserOptions())
+	if err != nil {
+		return fmt.Errorf("launching browser: %w", err)
+	}
+	defer browser.Close()
+
+	if err := ensureLoggedIn(browser); err != nil {
+		return err
+	}
+
+	if err := fn(browser); err != nil {
+		return err
+	}
+
+	fmt.Println("Done!")
+	return nil
+}
+
+func init() {
+	addJSONFlag(shelvesListCmd, "shelves")
+	shelvesCreateCmd.Flags().BoolVar(&shelvesExclusiveFlag, "exclusive", false, "make the shelf exclusive, like read/currently-reading")
+
+	shelvesCmd.AddCommand(shelvesListCmd, shelvesCreateCmd, shelvesRenameCmd, shelvesDeleteCmd)
+	rootCmd.AddCommand(shelvesCmd)
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"github.com/yareeh/goodreads-cli/internal"
+	"github.com/yareeh/goodreads-cli/internal/output"
+)
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show statistics about your library (from the local database)",
+	Long: `Show how many books are on each shelf, your average rating, and the
+books and pages you finished per year.
+
+The numbers come from the local library database, so run 'goodreads
+sync' first; no browser is launched.
+
+Examples:
+  goodreads stats
+  goodreads stats --json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		lib, err := openLibrary()
+		if err != nil {
+			return err
+		}
+		defer lib.Close()
+
+		st, err := lib.Stats()
+		if err != nil {
+			return fmt.Errorf("reading library: %w", err)
+		}
+
+		opts := outputOptions()
+		return output.Item(os.Stdout, opts, st, nil, func(w io.Writer) error {
+			tables := output.Options{Width: opts.Width}
+			fmt.Fprintf(w, "Books:        %d\n", st.Books)
+			if st.Rated > 0 {
+				fmt.Fprintf(w, "Your rating:  %.2f average over %d rated books\n", st.AverageRating, st.Rated)
+			}
+			fmt.Fprintf(w, "Last synced:  %s\n", st.LastSync.Local().Format("2006-01-02 15:04"))
+
+			fmt.Fprintln(w)
+			if err := output.List(w, tables, st.Shelves, shelfCountColumns); err != nil {
+				return err
+			}
+			if len(st.ReadPerYear) > 0 {
+				fmt.Fprintln(w)
+				return output.List(w, tables, st.ReadPerYear, yearCountColumns)
+			}
+			return nil
+		})
+	},
+}
+
+var shelfCountColumns = []output.Column[internal.ShelfCount]{
+	{Name: "shelf", Value: func(c internal.ShelfCount) string { return c.Shelf }},
+	{Name: "books", Value: func(c internal.ShelfCount) string { return strconv.Itoa(c.Books) }, Right: true},
+}
+
+var yearCountColumns = []output.Column[internal.YearCount]{
+	{Name: "year", Value: func(c internal.YearCount) string { return c.Year }},
+	{Name: "books", Value: func(c internal.YearCount) string { return strconv.Itoa(c.Books) }, Right: true},
+	{Name: "pages", Value: func(c internal.YearCount) string { return strconv.Itoa(c.Pages) }, Right: true},
+}
+
+func init() {
+	addJSONFlag(statsCmd, "statistics")
+	rootCmd.AddCommand(statsCmd)
+}
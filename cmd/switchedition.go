@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/yareeh/goodreads-cli/internal"
+)
+
+var switchEditionCmd = &cobra.Command{
+	Use:   "switch-edition <shelved-id> <edition-id>",
+	Short: "Move a shelved book to a different edition of the same work",
+	Long: `Point your shelving of a book at another edition of the same work.
+
+Shelves, rating, review and reading dates move with it, exactly as with
+Goodreads' own "Switch to this edition" link. Use 'goodreads editions' to
+find the edition ID.
+
+Example:
+  goodreads switch-edition 2041274 18690730`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		shelvedID, err := resolveBookArg(cmd, args[0])
+		if err != nil {
+			return err
+		}
+		editionID, err := resolveBookArg(cmd, args[1])
+		if err != nil {
+			return err
+		}
+
+		return withLoggedInBrowser(func(browser *internal.Browser) error {
+			fmt.Printf("Switching book %s to edition %s...\n", shelvedID, editionID)
+			return internal.SwitchEdition(browser, shelvedID, editionID)
+		})
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(switchEditionCmd)
+}
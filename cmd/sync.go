@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/yareeh/goodreads-cli/internal"
+)
+
+var syncFull bool
+
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Update the local library database from your shelves",
+	Long: `Mirror your shelves into a local SQLite database (~/.goodreads-cli-library.db)
+so that 'list-shelf --offline', 'search --local' and 'stats' can answer
+without a browser — in milliseconds, and without a network.
+
+Every shelved book is stored with its shelves, your rating, review and
+reading dates. The first sync reads the whole library. Later syncs read
+the most recently updated books first and stop at the first page of 100
+on which nothing changed; --full reads everything again, which is also
+the only way books you removed from all shelves are dropped.
+
+Examples:
+  goodreads sync
+  goodreads sync --full`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		lib, err := internal.OpenLibrary(internal.LibraryPath())
+		if err != nil {
+			return err
+		}
+		defer lib.Close()
+
+		return withLoggedInBrowser(func(browser *internal.Browser) error {
+			count := 0
+			walk := func(opts internal.ShelfListOptions, fn func([]internal.ShelfEntry) error) error {
+				return browser.ListShelfPages("#ALL#", opts, func(page []internal.ShelfEntry) error {
+					count += len(page)
+					fmt.Printf("Read %d books…\n", count)
+					return fn(page)
+				})
+			}
+			res, err := lib.Sync(walk, syncFull)
+			if err != nil {
+				return fmt.Errorf("syncing library: %w", err)
+			}
+			fmt.Printf("Added %d, updated %d, unchanged %d, removed %d.\n", res.Added, res.Updated, res.Unchanged, res.Removed)
+			return nil
+		})
+	},
+}
+
+// openLibrary opens the local library for reading, failing if it has
+// never been synced rather than answering from an empty database.
+func openLibrary() (*internal.Library, error) {
+	lib, err := internal.OpenLibrary(internal.LibraryPath())
+	if err != nil {
+		return nil, err
+	}
+	if _, ok, err := lib.LastSync(); err != nil || !ok {
+		lib.Close()
+		if err == nil {
+			err = fmt.Errorf("no local library yet — run 'goodreads sync' first")
+		}
+		return nil, err
+	}
+	return lib, nil
+}
+
+func init() {
+	syncCmd.Flags().BoolVar(&syncFull, "full", false, "read every book again and drop books no longer shelved")
+	rootCmd.AddCommand(syncCmd)
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/yareeh/goodreads-cli/internal"
+)
+
+var (
+	unshelveShelf string
+	unshelveAll   bool
+)
+
+var unshelveCmd = &cobra.Command{
+	Use:   "unshelve <book-id>",
+	Short: "Remove a book from a shelf, or from your library entirely",
+	Long: `Remove a book from a Goodreads shelf.
+
+Goodreads keeps every shelved book on exactly one of the exclusive shelves
+(want-to-read, currently-reading, read), so taking a book off one of them
+removes it from your library — along with its rating, review and reading
+dates. --shelf checks the book really is on that shelf before removing it;
+--all removes it whatever shelf it is on. Removing a book from a custom
+shelf only takes it off that shelf.
+
+Examples:
+  goodreads unshelve 55145261 --shelf want-to-read
+  goodreads unshelve 55145261 --shelf book-club-2026
+  goodreads unshelve 55145261 --all`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		bookID, err := resolveBookArg(cmd, args[0])
+		if err != nil {
+			return err
+		}
+
+		fmt.Println("Launching browser...")
+		browser, err := internal.NewBrowser(browserOptions())
+		if err != nil {
+			return fmt.Errorf("launching browser: %w", err)
+		}
+		defer browser.Close()
+
+		if err := ensureLoggedIn(browser); err != nil {
+			return err
+		}
+
+		shelf := unshelveShelf
+		if unshelveAll {
+			shelf = ""
+			fmt.Printf("Removing book %s from your library...\n", bookID)
+		} else {
+			fmt.Printf("Removing book %s from shelf '%s'...\n", bookID, shelf)
+		}
+		if err := internal.RemoveFromShelf(browser, bookID, shelf); err != nil {
+			return err
+		}
+
+		fmt.Println("Done!")
+		return nil
+	},
+}
+
+func init() {
+	unshelveCmd.Flags().StringVar(&unshelveShelf, "shelf", "", "shelf to remove the book from (currently-reading, want-to-read, read, or a custom shelf)")
+	unshelveCmd.Flags().BoolVar(&unshelveAll, "all", false, "remove the book from every shelf")
+	unshelveCmd.MarkFlagsMutuallyExclusive("shelf", "all")
+	unshelveCmd.MarkFlagsOneRequired("shelf", "all")
+	rootCmd.AddCommand(unshelveCmd)
+}
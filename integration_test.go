@@ -264,7 +264,7 @@ func setupBrowserWithLogin(t *testing.T) *internal.Browser {
 		t.Skip("GOODREADS_EMAIL or GOODREADS_SESSION_COOKIES not set, skipping browser test")
 	}
 
-	browser, err := internal.NewBrowser(true) // headless
+	browser, err := internal.NewBrowser(internal.BrowserOptions{Headless: true})
 	if err != nil {
 		t.Fatalf("NewBrowser: %v", err)
 	}
@@ -332,7 +332,7 @@ func TestIntegrationLogin(t *testing.T) {
 		t.Fatalf("clear cached session: %v", err)
 	}
 
-	browser, err := internal.NewBrowser(true)
+	browser, err := internal.NewBrowser(internal.BrowserOptions{Headless: true})
 	if err != nil {
 		t.Fatalf("NewBrowser: %v", err)
 	}
@@ -370,6 +370,20 @@ func TestIntegrationShelfAddAndRemove(t *testing.T) {
 		t.Fatalf("AddToShelf(read): %v", err)
 	}
 	t.Log("Moved book to read shelf")
+
+	time.Sleep(2 * time.Second)
+
+	// Removing from the wrong exclusive shelf must refuse rather than
+	// delete the book.
+	if err := internal.RemoveFromShelf(browser, testShelfBookID, "want-to-read"); err == nil {
+		t.Fatal("RemoveFromShelf(want-to-read) succeeded for a book on read")
+	}
+
+	err = internal.RemoveFromShelf(browser, testShelfBookID, "read")
+	if err != nil {
+		t.Fatalf("RemoveFromShelf(read): %v", err)
+	}
+	t.Log("Removed book from read shelf")
 }
 
 func TestIntegrationMarkCurrentlyReading(t *testing.T) {
@@ -0,0 +1,103 @@
+package api
+
+import (
+	"reflect"
+
+	"github.com/yareeh/goodreads-cli/internal"
+	"github.com/yareeh/goodreads-cli/internal/jsonschema"
+)
+
+// OpenAPI returns the OpenAPI 3.1 description of the API, with the
+// response schemas derived from the types the handlers encode.
+func OpenAPI(version string) map[string]any {
+	ref := func(name string) map[string]any {
+		return map[string]any{"$ref": "#/components/schemas/" + name}
+	}
+	jsonBody := func(schema map[string]any) map[string]any {
+		return map[string]any{"application/json": map[string]any{"schema": schema}}
+	}
+	ok := func(description, schema string) map[string]any {
+		return map[string]any{
+			"200": map[string]any{"description": description, "content": jsonBody(ref(schema))},
+			"400": map[string]any{"description": "Invalid request", "content": jsonBody(ref("Error"))},
+			"401": map[string]any{"description": "Missing or invalid bearer token", "content": jsonBody(ref("Error"))},
+			"404": map[string]any{"description": "No such book or shelf on Goodreads", "content": jsonBody(ref("Error"))},
+			"429": map[string]any{"description": "Rate limited by Goodreads", "content": jsonBody(ref("Error"))},
+			"502": map[string]any{"description": "Goodreads or the browser failed", "content": jsonBody(ref("Error"))},
+		}
+	}
+	param := func(name, in, typ, description string, required bool) map[string]any {
+		return map[string]any{
+			"name": name, "in": in, "required": required, "description": description,
+			"schema": map[string]any{"type": typ},
+		}
+	}
+	shelfParam := param("name", "path", "string", "shelf name: want-to-read, currently-reading, read, a custom shelf, or #ALL# (as %23ALL%23)", true)
+
+	return map[string]any{
+		"openapi": "3.1.0",
+		"info": map[string]any{
+			"title":       "goodreads-cli",
+			"version":     version,
+			"description": "Local REST API over goodreads-cli. Requests are handled one at a time on a single browser session.",
+		},
+		"security": []any{map[string]any{"bearer": []any{}}},
+		"paths": map[string]any{
+			"/books/{id}": map[string]any{
+				"get": map[string]any{
+					"operationId": "getBook",
+					"summary":     "Book details",
+					"parameters":  []any{param("id", "path", "string", "Goodreads book ID, ISBN-10/13, ASIN or URL-encoded book URL", true)},
+					"responses":   ok("The book's full record", "Book"),
+				},
+			},
+			"/search": map[string]any{
+				"get": map[string]any{
+					"operationId": "search",
+					"summary":     "Search for books",
+					"parameters": []any{
+						param("q", "query", "string", "title, author or ISBN", true),
+						param("full", "query", "boolean", "use the full results page instead of quick autocomplete matches", false),
+						param("field", "query", "string", "with full: title, author or all (default)", false),
+						param("limit", "query", "integer", "with full: return up to this many results, across pages", false),
+					},
+					"responses": ok("Matching books", "Books"),
+				},
+			},
+			"/shelves/{name}": map[string]any{
+				"get": map[string]any{
+					"operationId": "listShelf",
+					"summary":     "List the books on a shelf",
+					"parameters": []any{
+						shelfParam,
+						param("limit", "query", "integer", "stop after this many books", false),
+						param("page", "query", "integer", "start at this page of 100 books; without limit, fetch only that page", false),
+					},
+					"responses": ok("The shelf's books", "ShelfEntries"),
+				},
+			},
+			"/shelves/{name}/books": map[string]any{
+				"post": map[string]any{
+					"operationId": "shelveBook",
+					"summary":     "Add a book to a shelf",
+					"parameters":  []any{shelfParam},
+					"requestBody": map[string]any{"required": true, "content": jsonBody(ref("ShelveRequest"))},
+					"responses":   ok("The book was shelved", "ShelveResponse"),
+				},
+			},
+		},
+		"components": map[string]any{
+			"securitySchemes": map[string]any{
+				"bearer": map[string]any{"type": "http", "scheme": "bearer"},
+			},
+			"schemas": map[string]any{
+				"Book":           jsonschema.For(reflect.TypeFor[internal.Book]()),
+				"Books":          jsonschema.For(reflect.TypeFor[BooksResponse[internal.Book]]()),
+				"ShelfEntries":   jsonschema.For(reflect.TypeFor[BooksResponse[internal.ShelfEntry]]()),
+				"ShelveRequest":  jsonschema.For(reflect.TypeFor[ShelveRequest]()),
+				"ShelveResponse": jsonschema.For(reflect.TypeFor[ShelveResponse]()),
+				"Error":          jsonschema.For(reflect.TypeFor[ErrorResponse]()),
+			},
+		},
+	}
+}
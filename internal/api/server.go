@@ -0,0 +1,298 @@
+// Package api serves goodreads-cli's operations as a local REST/JSON API
+// for dashboards, bots and scripts that would rather speak HTTP than run
+// the CLI:
+//
+//	GET  /books/{id}           book details; id is an ID, ISBN, ASIN or URL
+//	GET  /search?q=            search, with optional full, field and limit
+//	GET  /shelves/{name}       books on a shelf, with optional limit and page
+//	POST /shelves/{name}/books add {"book": "…"} to a shelf
+//	GET  /openapi.json         the OpenAPI description of the above
+//
+// Every request runs on one Backend, normally an internal.Session with a
+// single browser, so requests are handled one at a time.
+//
+// The account is reachable by anything that can reach the port, including
+// web pages in the user's browser. Requests naming another host are
+// refused, so a page can't rebind its own name to 127.0.0.1 and read the
+// API, and POST bodies must be application/json, which a page can only
+// send cross-site after a CORS preflight this server never answers.
+package api
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/yareeh/goodreads-cli/internal"
+)
+
+// Backend is what the API serves; *internal.Session implements it.
+type Backend interface {
+	BookDetails(input string) (internal.Book, error)
+	Search(query string, full bool, opts internal.SearchOptions) ([]internal.Book, error)
+	ListShelf(shelf string, opts internal.ShelfListOptions) ([]internal.ShelfEntry, error)
+	Shelve(input, shelf string) (string, error)
+}
+
+var _ Backend = (*internal.Session)(nil)
+
+// Server is the HTTP API over a Backend. With a Token, every request but
+// /openapi.json must carry it as "Authorization: Bearer <token>". Addr is
+// the address it listens on, whose host requests may name besides
+// localhost and loopback addresses.
+type Server struct {
+	Backend Backend
+	Token   string
+	Version string
+	Addr    string
+	// Reset, if set, is called after a request panics, to drop state such
+	// as a browser left on a half-loaded page.
+	Reset func()
+
+	mu sync.Mutex // serializes Backend calls
+}
+
+// BooksResponse is the body of search and shelf listings.
+type BooksResponse[T any] struct {
+	Books []T `json:"books"`
+}
+
+// ShelveRequest is the body of POST /shelves/{name}/books.
+type ShelveRequest struct {
+	Book string `json:"book" desc:"Goodreads book ID, ISBN-10/13, ASIN or goodreads.com book URL"`
+}
+
+// ShelveResponse is the reply to POST /shelves/{name}/books.
+type ShelveResponse struct {
+	BookID string `json:"book_id"`
+	Shelf  string `json:"shelf"`
+}
+
+// ErrorResponse is the body of every error reply. Code is the error's
+// internal.ErrorCode, as --error-format json reports it.
+type ErrorResponse struct {
+	Error string `json:"error"`
+	Code  string `json:"code,omitempty" desc:"invalid_input, not_found, rate_limited, not_logged_in, waf_blocked, selector_drift or error"`
+}
+
+// CheckAddr refuses to serve the account beyond this machine without a
+// token: addr must be a loopback address or "localhost" unless token is
+// set. An empty host, as in ":8080", listens on every interface.
+func CheckAddr(addr, token string) error {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return internal.Errorf(internal.CodeInvalidInput, "invalid --addr %q: %v", addr, err)
+	}
+	if token != "" || host == "localhost" {
+		return nil
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		return nil
+	}
+	return internal.Errorf(internal.CodeInvalidInput, "--addr %s is reachable from other machines — set --token (or GOODREADS_API_TOKEN), or listen on 127.0.0.1", addr)
+}
+
+// badRequest is an error in the request itself.
+func badRequest(format string, args ...any) error {
+	return internal.Errorf(internal.CodeInvalidInput, "bad request: %s", fmt.Sprintf(format, args...))
+}
+
+// errorStatus is the HTTP status for an error's code: 400 for invalid
+// input, 404 and 429 passed on from Goodreads, and 502 for anything else
+// that went wrong with Goodreads or the browser.
+func errorStatus(code internal.ErrorCode) int {
+	switch code {
+	case internal.CodeInvalidInput:
+		return http.StatusBadRequest
+	case internal.CodeNotFound:
+		return http.StatusNotFound
+	case internal.CodeRateLimited:
+		return http.StatusTooManyRequests
+	}
+	return http.StatusBadGateway
+}
+
+// allowedHost reports whether a request's Host header names this server:
+// localhost, a loopback address or the host of s.Addr. With a token and an
+// address on every interface, any name is fine; the token is the guard.
+func (s *Server) allowedHost(host string) bool {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.Trim(host, "[]")
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		return true
+	}
+	listen, _, _ := net.SplitHostPort(s.Addr)
+	if listen == "" || listen == "0.0.0.0" || listen == "::" {
+		return s.Token != ""
+	}
+	return strings.EqualFold(host, listen)
+}
+
+// Handler returns the API's routes.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /openapi.json", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, OpenAPI(s.Version))
+	})
+	mux.Handle("GET /books/{id}", s.endpoint(s.book))
+	mux.Handle("GET /search", s.endpoint(s.search))
+	mux.Handle("GET /shelves/{name}", s.endpoint(s.listShelf))
+	mux.Handle("POST /shelves/{name}/books", s.endpoint(s.shelve))
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !s.allowedHost(r.Host) {
+			writeJSON(w, http.StatusForbidden, ErrorResponse{
+				Error: fmt.Sprintf("host %q is not this server", r.Host), Code: string(internal.CodeInvalidInput),
+			})
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// endpoint wraps a handler with the token check, serialization and JSON
+// encoding. Errors get the status errorStatus gives their code.
+func (s *Server) endpoint(fn func(r *http.Request) (any, error)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !s.authorized(r) {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="goodreads-cli"`)
+			writeJSON(w, http.StatusUnauthorized, ErrorResponse{Error: "missing or invalid bearer token"})
+			return
+		}
+		if r.Method == http.MethodPost {
+			if mt, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mt != "application/json" {
+				writeJSON(w, http.StatusUnsupportedMediaType, ErrorResponse{
+					Error: "the body must be sent as Content-Type: application/json", Code: string(internal.CodeInvalidInput),
+				})
+				return
+			}
+		}
+		out, err := s.call(fn, r)
+		if err != nil {
+			code := internal.CodeOf(err)
+			writeJSON(w, errorStatus(code), ErrorResponse{Error: err.Error(), Code: string(code)})
+			return
+		}
+		writeJSON(w, http.StatusOK, out)
+	})
+}
+
+// call runs fn with the Backend to itself. A panic becomes an error
+// rather than leaving the mutex locked for every later request, and Reset
+// gives the next request a clean start.
+func (s *Server) call(fn func(r *http.Request) (any, error), r *http.Request) (any, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return internal.Guard(func() (any, error) { return fn(r) }, s.Reset)
+}
+
+func (s *Server) authorized(r *http.Request) bool {
+	if s.Token == "" {
+		return true
+	}
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && subtle.ConstantTimeCompare([]byte(token), []byte(s.Token)) == 1
+}
+
+func (s *Server) book(r *http.Request) (any, error) {
+	return s.Backend.BookDetails(r.PathValue("id"))
+}
+
+func (s *Server) search(r *http.Request) (any, error) {
+	q := r.URL.Query()
+	if q.Get("q") == "" {
+		return nil, badRequest("missing query parameter q")
+	}
+	full, err := boolParam(q.Get("full"), "full")
+	if err != nil {
+		return nil, err
+	}
+	limit, err := intParam(q.Get("limit"), "limit")
+	if err != nil {
+		return nil, err
+	}
+	books, err := s.Backend.Search(q.Get("q"), full, internal.SearchOptions{Field: q.Get("field"), Limit: limit})
+	if err != nil {
+		return nil, err
+	}
+	return BooksResponse[internal.Book]{nonNil(books)}, nil
+}
+
+func (s *Server) listShelf(r *http.Request) (any, error) {
+	q := r.URL.Query()
+	limit, err := intParam(q.Get("limit"), "limit")
+	if err != nil {
+		return nil, err
+	}
+	page, err := intParam(q.Get("page"), "page")
+	if err != nil {
+		return nil, err
+	}
+	entries, err := s.Backend.ListShelf(r.PathValue("name"), internal.ShelfListOptions{Limit: limit, Page: page})
+	if err != nil {
+		return nil, err
+	}
+	return BooksResponse[internal.ShelfEntry]{nonNil(entries)}, nil
+}
+
+func (s *Server) shelve(r *http.Request) (any, error) {
+	var req ShelveRequest
+	if err := json.NewDecoder(http.MaxBytesReader(nil, r.Body, 1<<20)).Decode(&req); err != nil {
+		return nil, badRequest("invalid JSON body: %v", err)
+	}
+	if req.Book == "" {
+		return nil, badRequest(`missing "book" in body`)
+	}
+	shelf := r.PathValue("name")
+	id, err := s.Backend.Shelve(req.Book, shelf)
+	if err != nil {
+		return nil, err
+	}
+	return ShelveResponse{BookID: id, Shelf: shelf}, nil
+}
+
+func intParam(v, name string) (int, error) {
+	if v == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		return 0, badRequest("%s must be a non-negative integer, got %q", name, v)
+	}
+	return n, nil
+}
+
+func boolParam(v, name string) (bool, error) {
+	if v == "" {
+		return false, nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, badRequest("%s must be true or false, got %q", name, v)
+	}
+	return b, nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// nonNil keeps an empty listing an empty JSON array rather than null.
+func nonNil[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
@@ -0,0 +1,310 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/yareeh/goodreads-cli/internal"
+)
+
+type fakeBackend struct {
+	mu      sync.Mutex
+	calls   []string
+	running int
+	overlap bool
+	resets  int
+}
+
+func (f *fakeBackend) enter(call string) func() {
+	f.mu.Lock()
+	f.calls = append(f.calls, call)
+	f.running++
+	if f.running > 1 {
+		f.overlap = true
+	}
+	f.mu.Unlock()
+	time.Sleep(time.Millisecond)
+	return func() {
+		f.mu.Lock()
+		f.running--
+		f.mu.Unlock()
+	}
+}
+
+func (f *fakeBackend) BookDetails(input string) (internal.Book, error) {
+	defer f.enter("book " + input)()
+	if input == "panic" {
+		panic("context deadline exceeded")
+	}
+	if input == "404" {
+		return internal.Book{}, internal.Errorf(internal.CodeNotFound, "book not found")
+	}
+	return internal.Book{ID: input, Title: "Dune", Pages: 412}, nil
+}
+
+func (f *fakeBackend) Search(query string, full bool, opts internal.SearchOptions) ([]internal.Book, error) {
+	defer f.enter(fmt.Sprintf("search %s full=%v field=%s limit=%d", query, full, opts.Field, opts.Limit))()
+	if query == "nothing" {
+		return nil, nil
+	}
+	return []internal.Book{{ID: "2", Title: "Dune"}}, nil
+}
+
+func (f *fakeBackend) ListShelf(shelf string, opts internal.ShelfListOptions) ([]internal.ShelfEntry, error) {
+	defer f.enter(fmt.Sprintf("list %s limit=%d page=%d", shelf, opts.Limit, opts.Page))()
+	return []internal.ShelfEntry{{Book: internal.Book{ID: "2"}, MyRating: 5}}, nil
+}
+
+func (f *fakeBackend) Shelve(input, shelf string) (string, error) {
+	defer f.enter("shelve " + input + " " + shelf)()
+	return "2", nil
+}
+
+func newTestServer(t *testing.T, token string) (*httptest.Server, *fakeBackend) {
+	t.Helper()
+	backend := &fakeBackend{}
+	srv := &Server{Backend: backend, Token: token, Version: "test", Reset: func() { backend.resets++ }}
+	ts := httptest.NewServer(srv.Handler())
+	t.Cleanup(ts.Close)
+	return ts, backend
+}
+
+func do(t *testing.T, method, url, token, body string) (int, string) {
+	t.Helper()
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Unrouted requests get net/http's plain-text 404 and 405.
+	if ct := resp.Header.Get("Content-Type"); ct != "application/json" && resp.StatusCode != 404 && resp.StatusCode != 405 {
+		t.Errorf("%s %s: Content-Type %q", method, url, ct)
+	}
+	return resp.StatusCode, strings.TrimSpace(string(data))
+}
+
+func TestServer_Endpoints(t *testing.T) {
+	ts, backend := newTestServer(t, "")
+	tests := []struct {
+		method, path, body string
+		status             int
+		want               string
+		call               string
+	}{
+		{"GET", "/books/9780441013593", "", 200, `"id":"9780441013593"`, "book 9780441013593"},
+		{"GET", "/books/panic", "", 502, `{"error":"internal error: context deadline exceeded","code":"error"}`, "book panic"},
+		{"GET", "/books/404", "", 404, `{"error":"book not found","code":"not_found"}`, "book 404"},
+		{"GET", "/search?q=dune", "", 200, `{"books":[{"id":"2"`, "search dune full=false field= limit=0"},
+		{"GET", "/search?q=dune&full=1&field=title&limit=5", "", 200, `"title":"Dune"`, "search dune full=true field=title limit=5"},
+		{"GET", "/search?q=nothing", "", 200, `{"books":[]}`, "search nothing full=false field= limit=0"},
+		{"GET", "/shelves/read?limit=10&page=2", "", 200, `"my_rating":5`, "list read limit=10 page=2"},
+		{"GET", "/shelves/%23ALL%23", "", 200, `{"books":[`, "list #ALL# limit=0 page=0"},
+		{"POST", "/shelves/to-read/books", `{"book":"9780441013593"}`, 200, `{"book_id":"2","shelf":"to-read"}`, "shelve 9780441013593 to-read"},
+
+		{"GET", "/search", "", 400, `{"error":"bad request: missing query parameter q","code":"invalid_input"}`, ""},
+		{"GET", "/search?q=dune&limit=x", "", 400, `limit must be a non-negative integer`, ""},
+		{"GET", "/shelves/read?page=-1", "", 400, `page must be a non-negative integer`, ""},
+		{"POST", "/shelves/read/books", `{"isbn":"1"}`, 400, `missing \"book\"`, ""},
+		{"POST", "/shelves/read/books", `not json`, 400, `invalid JSON body`, ""},
+		{"DELETE", "/books/2", "", 405, ``, ""},
+		{"GET", "/authors/1", "", 404, ``, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			backend.calls = nil
+			status, body := do(t, tt.method, ts.URL+tt.path, "", tt.body)
+			if status != tt.status {
+				t.Errorf("status %d, want %d (%s)", status, tt.status, body)
+			}
+			if !strings.Contains(body, tt.want) {
+				t.Errorf("body %s, want it to contain %s", body, tt.want)
+			}
+			var calls []string
+			if tt.call != "" {
+				calls = []string{tt.call}
+			}
+			if fmt.Sprint(backend.calls) != fmt.Sprint(calls) {
+				t.Errorf("backend calls %q, want %q", backend.calls, calls)
+			}
+		})
+	}
+	if backend.resets != 1 {
+		t.Errorf("Reset called %d times, want 1 for the panic", backend.resets)
+	}
+}
+
+func TestServer_Token(t *testing.T) {
+	ts, backend := newTestServer(t, "s3cret")
+	for _, token := range []string{"", "wrong"} {
+		status, _ := do(t, "GET", ts.URL+"/books/2", token, "")
+		if status != http.StatusUnauthorized {
+			t.Errorf("token %q: status %d, want 401", token, status)
+		}
+	}
+	if len(backend.calls) > 0 {
+		t.Errorf("unauthorized requests reached the backend: %q", backend.calls)
+	}
+	if status, body := do(t, "GET", ts.URL+"/books/2", "s3cret", ""); status != http.StatusOK {
+		t.Errorf("right token: status %d (%s)", status, body)
+	}
+	if status, _ := do(t, "GET", ts.URL+"/openapi.json", "", ""); status != http.StatusOK {
+		t.Errorf("openapi.json without token: status %d, want 200", status)
+	}
+}
+
+// TestServer_CrossSite: a web page can POST text/plain without a CORS
+// preflight, or rebind its own host name to 127.0.0.1; neither reaches
+// the backend.
+func TestServer_CrossSite(t *testing.T) {
+	ts, backend := newTestServer(t, "")
+	send := func(host, contentType string) int {
+		req, _ := http.NewRequest("POST", ts.URL+"/shelves/read/books", strings.NewReader(`{"book":"2"}`))
+		if host != "" {
+			req.Host = host
+		}
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	for _, ct := range []string{"", "text/plain", "application/x-www-form-urlencoded"} {
+		if status := send("", ct); status != http.StatusUnsupportedMediaType {
+			t.Errorf("Content-Type %q: status %d, want 415", ct, status)
+		}
+	}
+	if status := send("evil.example:8080", "application/json"); status != http.StatusForbidden {
+		t.Errorf("foreign Host: status %d, want 403", status)
+	}
+	if len(backend.calls) > 0 {
+		t.Errorf("cross-site requests reached the backend: %q", backend.calls)
+	}
+	for _, host := range []string{"", "localhost:8080", "[::1]:8080"} {
+		if status := send(host, "application/json; charset=utf-8"); status != http.StatusOK {
+			t.Errorf("Host %q: status %d, want 200", host, status)
+		}
+	}
+}
+
+func TestServer_AllowedHost(t *testing.T) {
+	for _, tt := range []struct {
+		addr, token, host string
+		want              bool
+	}{
+		{"127.0.0.1:8080", "", "127.0.0.1:8080", true},
+		{"127.0.0.1:8080", "", "localhost", true},
+		{"127.0.0.1:8080", "", "attacker.example:8080", false},
+		{"192.168.1.5:8080", "s3cret", "192.168.1.5:8080", true},
+		{"192.168.1.5:8080", "s3cret", "attacker.example", false},
+		{":8080", "s3cret", "nas.local:8080", true},
+		{":8080", "", "nas.local:8080", false},
+	} {
+		s := &Server{Addr: tt.addr, Token: tt.token}
+		if got := s.allowedHost(tt.host); got != tt.want {
+			t.Errorf("addr %s token %q: allowedHost(%q) = %v, want %v", tt.addr, tt.token, tt.host, got, tt.want)
+		}
+	}
+}
+
+func TestServer_Serializes(t *testing.T) {
+	ts, backend := newTestServer(t, "")
+	var wg sync.WaitGroup
+	for i := range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			do(t, "GET", fmt.Sprintf("%s/books/%d", ts.URL, i), "", "")
+		}()
+	}
+	wg.Wait()
+	if len(backend.calls) != 10 {
+		t.Errorf("got %d backend calls, want 10", len(backend.calls))
+	}
+	if backend.overlap {
+		t.Error("backend calls overlapped")
+	}
+}
+
+func TestOpenAPI(t *testing.T) {
+	ts, _ := newTestServer(t, "")
+	_, body := do(t, "GET", ts.URL+"/openapi.json", "", "")
+	var doc struct {
+		OpenAPI string                               `json:"openapi"`
+		Info    struct{ Version string }             `json:"info"`
+		Paths   map[string]map[string]map[string]any `json:"paths"`
+		Comps   struct {
+			Schemas map[string]map[string]any `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal([]byte(body), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc.OpenAPI != "3.1.0" || doc.Info.Version != "test" {
+		t.Errorf("openapi %q, version %q", doc.OpenAPI, doc.Info.Version)
+	}
+	for path, method := range map[string]string{
+		"/books/{id}":           "get",
+		"/search":               "get",
+		"/shelves/{name}":       "get",
+		"/shelves/{name}/books": "post",
+	} {
+		if doc.Paths[path][method] == nil {
+			t.Errorf("no %s %s", method, path)
+		}
+	}
+	book := doc.Comps.Schemas["Book"]["properties"].(map[string]any)
+	if _, ok := book["isbn13"]; !ok {
+		t.Errorf("Book schema has no isbn13: %v", book)
+	}
+}
+
+func TestCheckAddr(t *testing.T) {
+	for _, tt := range []struct {
+		addr, token string
+		ok          bool
+	}{
+		{"127.0.0.1:8080", "", true},
+		{"localhost:8080", "", true},
+		{"[::1]:8080", "", true},
+		{"127.0.0.2:80", "", true},
+		{":8080", "", false},
+		{"0.0.0.0:8080", "", false},
+		{"192.168.1.5:8080", "", false},
+		{"myhost:8080", "", false},
+		{":8080", "s3cret", true},
+		{"8080", "", false},
+	} {
+		err := CheckAddr(tt.addr, tt.token)
+		if (err == nil) != tt.ok {
+			t.Errorf("CheckAddr(%q, %q) = %v, want ok %v", tt.addr, tt.token, err, tt.ok)
+		}
+		if err != nil && internal.CodeOf(err) != internal.CodeInvalidInput {
+			t.Errorf("CheckAddr(%q) code = %s, want invalid_input", tt.addr, internal.CodeOf(err))
+		}
+	}
+}
@@ -26,7 +26,7 @@ func Login(b *Browser, cfg *Config) error {
 	b.Log.Record("find_signin_button", map[string]any{"selector": ".authPortalSignInButton"}, err)
 	if err != nil {
 		saveDebugArtifacts(b)
-		return fmt.Errorf("could not find 'Sign in with email' button: %w", err)
+		return Errorf(CodeSelectorDrift, "could not find 'Sign in with email' button: %w", err)
 	}
 	signInBtn.MustClick()
 	b.Page.MustWaitStable()
@@ -35,7 +35,7 @@ func Login(b *Browser, cfg *Config) error {
 	emailField, err := b.Page.Timeout(30 * time.Second).Element(`#ap_email, input[name="email"], input[type="email"]`)
 	if err != nil {
 		saveDebugArtifacts(b)
-		return fmt.Errorf("could not find email field — run with --no-headless to debug: %w", err)
+		return Errorf(CodeSelectorDrift, "could not find email field — run with --no-headless to debug: %w", err)
 	}
 
 	emailField.MustSelectAllText().MustInput(cfg.Email)
@@ -43,7 +43,7 @@ func Login(b *Browser, cfg *Config) error {
 	passwordField, err := b.Page.Timeout(5 * time.Second).Element(`#ap_password, input[name="password"], input[type="password"]`)
 	if err != nil {
 		saveDebugArtifacts(b)
-		return fmt.Errorf("could not find password field: %w", err)
+		return Errorf(CodeSelectorDrift, "could not find password field: %w", err)
 	}
 	passwordField.MustSelectAllText().MustInput(cfg.Password)
 
@@ -51,7 +51,7 @@ func Login(b *Browser, cfg *Config) error {
 	submitBtn, err := b.Page.Timeout(5 * time.Second).Element(`#signInSubmit, input[type="submit"], button[type="submit"]`)
 	if err != nil {
 		saveDebugArtifacts(b)
-		return fmt.Errorf("could not find submit button: %w", err)
+		return Errorf(CodeSelectorDrift, "could not find submit button: %w", err)
 	}
 	submitBtn.MustClick()
 
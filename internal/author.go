@@ -0,0 +1,233 @@
+package internal
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AuthorBooksOptions narrows an author's book list. The zero value walks
+// every page.
+type AuthorBooksOptions struct {
+	Page  int // fetch only this 1-based page; 0 walks every page
+	Limit int // stop after this many books; 0 means no limit
+}
+
+// authorBooksPerPage is the number of books /author/list renders per page.
+const authorBooksPerPage = 30
+
+// AuthorIDFromInput returns the author ID in an ID, id-slug
+// ("5236.Andre_Brink") or goodreads.com/author/show/… URL. ok is false for
+// anything else, such as an author's name.
+func AuthorIDFromInput(input string) (id string, ok bool) {
+	s := strings.TrimSpace(input)
+	if m := _authorURLIDRE.FindStringSubmatch(s); m != nil {
+		return m[1], true
+	}
+	if m := _idSlugRE.FindStringSubmatch(s); m != nil {
+		return m[1], true
+	}
+	return "", false
+}
+
+// ParseAuthorHTML extracts an author's details from /author/show/<id>.
+// The page is classic Rails markup: the name in h1.authorName, a list of
+// dataTitle/dataItem pairs (Born, Died, Website, Genre), the bio in
+// freeText spans, and a hreview-aggregate line with the rating counts and
+// number of distinct works.
+func ParseAuthorHTML(html, authorID string) (Author, error) {
+	m := _authorNameRE.FindStringSubmatch(html)
+	if m == nil {
+		return Author{}, Errorf(CodeNotFound, "no author name found on the page for author %s", authorID)
+	}
+	a := Author{
+		ID:   authorID,
+		Name: cellText(m[1]),
+		URL:  fmt.Sprintf("%s/author/show/%s", BaseURL, authorID),
+	}
+	if m := _authorImageRE.FindStringSubmatch(html); m != nil {
+		a.ImageURL = m[1]
+	}
+	if m := _authorBornInRE.FindStringSubmatch(html); m != nil {
+		a.BornIn = strings.TrimPrefix(cellText(m[1]), "in ")
+	}
+	if m := _authorBirthRE.FindStringSubmatch(html); m != nil {
+		a.BornAt = authorDate(cellText(m[1]))
+	}
+	if m := _authorDeathRE.FindStringSubmatch(html); m != nil {
+		a.DiedAt = authorDate(cellText(m[1]))
+	}
+	if m := _authorWebsiteRE.FindStringSubmatch(html); m != nil {
+		a.Website = decodeHTMLEntities(m[1])
+	}
+	if m := _authorGenresRE.FindStringSubmatch(html); m != nil {
+		for _, g := range _authorGenreLinkRE.FindAllStringSubmatch(m[1], -1) {
+			a.Genres = append(a.Genres, cellText(g[1]))
+		}
+	}
+	a.Bio = parseAuthorBio(html)
+	if m := _authorAverageRE.FindStringSubmatch(html); m != nil {
+		a.Rating = m[1]
+	}
+	if m := _authorRatingsRE.FindStringSubmatch(html); m != nil {
+		a.RatingsCount, _ = strconv.Atoi(m[1])
+	}
+	if m := _authorReviewsRE.FindStringSubmatch(html); m != nil {
+		a.ReviewsCount, _ = strconv.Atoi(m[1])
+	}
+	if m := _authorWorksRE.FindStringSubmatch(html); m != nil {
+		a.WorksCount, _ = strconv.Atoi(strings.ReplaceAll(m[1], ",", ""))
+	}
+	return a, nil
+}
+
+// parseAuthorBio picks the full bio out of the page. Like reviews, long
+// bios are rendered twice — a truncated freeTextContainer and a hidden
+// freeText span — and short ones only once.
+func parseAuthorBio(html string) string {
+	text := ""
+	for _, m := range _authorBioRE.FindAllStringSubmatch(html, -1) {
+		if text == "" || m[1] == "" {
+			text = m[2]
+		}
+	}
+	text = _brRE.ReplaceAllString(text, "\n")
+	return strings.TrimSpace(decodeHTMLEntities(_tagRE.ReplaceAllString(text, "")))
+}
+
+// authorDate turns Goodreads' "May 29, 1935" into 1935-05-29, leaving
+// partial dates such as "1935" as they are.
+func authorDate(s string) string {
+	for _, layout := range []string{"January 2, 2006", "January 02, 2006"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.Format("2006-01-02")
+		}
+	}
+	return s
+}
+
+// fetchAuthor fetches and parses /author/show/<id> through fetch.
+func fetchAuthor(fetch func(string) (string, error), authorID string) (Author, error) {
+	html, err := fetch(fmt.Sprintf("%s/author/show/%s", BaseURL, authorID))
+	if err != nil {
+		return Author{}, fmt.Errorf("fetching author %s: %w", authorID, err)
+	}
+	return ParseAuthorHTML(html, authorID)
+}
+
+// authorBookPages collects an author's books from /author/list/<id>
+// through fetch, most popular first. The rows are the same
+// schema.org/Book rows as on /search. Paging stops after opts.Page, at
+// opts.Limit books, or at the first short page.
+func authorBookPages(fetch func(string) (string, error), authorID string, opts AuthorBooksOptions) ([]Book, error) {
+	page := 1
+	if opts.Page > 0 {
+		page = opts.Page
+	}
+	books := []Book{}
+	for {
+		u := fmt.Sprintf("%s/author/list/%s?page=%d&per_page=%d", BaseURL, authorID, page, authorBooksPerPage)
+		html, err := fetch(u)
+		if err != nil {
+			return nil, fmt.Errorf("fetching books of author %s, page %d: %w", authorID, page, err)
+		}
+		results := ParseSearchResultsHTML(html)
+		books = append(books, results...)
+		if opts.Limit > 0 && len(books) >= opts.Limit {
+			return books[:opts.Limit], nil
+		}
+		if opts.Page > 0 || len(results) < authorBooksPerPage {
+			return books, nil
+		}
+		page++
+	}
+}
+
+// FetchAuthor downloads an author page over plain HTTP. Like the other
+// classic pages it may be walled behind the WAF challenge; callers can
+// fall back to Browser.FetchAuthor on ErrAWSWAFChallenge.
+func (c *Client) FetchAuthor(authorID string) (Author, error) {
+	return fetchAuthor(c.fetchHTML, authorID)
+}
+
+// AuthorBooks lists an author's books over plain HTTP.
+func (c *Client) AuthorBooks(authorID string, opts AuthorBooksOptions) ([]Book, error) {
+	return authorBookPages(c.fetchHTML, authorID, opts)
+}
+
+// FetchAuthor is Client.FetchAuthor through the browser.
+func (b *Browser) FetchAuthor(authorID string) (Author, error) {
+	return fetchAuthor(b.FetchRenderedHTML, authorID)
+}
+
+// AuthorBooks is Client.AuthorBooks through the browser.
+func (b *Browser) AuthorBooks(authorID string, opts AuthorBooksOptions) ([]Book, error) {
+	return authorBookPages(b.FetchRenderedHTML, authorID, opts)
+}
+
+// SearchAuthors finds authors by name through autocomplete, which matches
+// book titles and author names alike: the distinct authors of the hits
+// come back in hit order, with exact (case-insensitive) name matches
+// first. Only ID and Name are set.
+func (c *Client) SearchAuthors(name string) ([]Author, error) {
+	return c.searchAuthors(BaseURL, name)
+}
+
+func (c *Client) searchAuthors(base, name string) ([]Author, error) {
+	books, err := c.autocomplete(base, name)
+	if err != nil {
+		return nil, err
+	}
+	seen := map[string]bool{}
+	var exact, others []Author
+	for _, bk := range books {
+		if bk.AuthorID == "" || seen[bk.AuthorID] {
+			continue
+		}
+		seen[bk.AuthorID] = true
+		a := Author{ID: bk.AuthorID, Name: bk.Author, URL: fmt.Sprintf("%s/author/show/%s", BaseURL, bk.AuthorID)}
+		if strings.EqualFold(strings.TrimSpace(bk.Author), strings.TrimSpace(name)) {
+			exact = append(exact, a)
+		} else {
+			others = append(others, a)
+		}
+	}
+	return append(exact, others...), nil
+}
+
+// _authorURLIDRE captures the ID from an /author/show/<id>[.slug] path.
+var _authorURLIDRE = regexp.MustCompile(`/author/show/(\d+)`)
+
+// _authorNameRE captures the name inside h1.authorName.
+var _authorNameRE = regexp.MustCompile(`(?s)<h1[^>]*class="authorName"[^>]*>(.*?)</h1>`)
+
+// _authorImageRE captures the author photo from the left column.
+var _authorImageRE = regexp.MustCompile(`(?s)class="leftContainer authorLeftContainer".*?<img[^>]*src="([^"]+)"`)
+
+// _authorBornInRE captures the birthplace, which is a bare text node
+// after the "Born" dataTitle ("in Vrede, South Africa").
+var _authorBornInRE = regexp.MustCompile(`(?s)<div class="dataTitle">Born</div>\s*([^<]+)`)
+
+var (
+	_authorBirthRE   = regexp.MustCompile(`(?s)itemprop=['"]birthDate['"][^>]*>(.*?)</div>`)
+	_authorDeathRE   = regexp.MustCompile(`(?s)itemprop=['"]deathDate['"][^>]*>(.*?)</div>`)
+	_authorWebsiteRE = regexp.MustCompile(`(?s)<div class="dataTitle">Website</div>\s*<div class="dataItem">\s*<a[^>]*href="([^"]+)"`)
+	_authorGenresRE  = regexp.MustCompile(`(?s)<div class="dataTitle">Genre</div>\s*<div class="dataItem">(.*?)</div>`)
+)
+
+// _authorGenreLinkRE captures each genre link's text.
+var _authorGenreLinkRE = regexp.MustCompile(`(?s)<a[^>]*href="/genres/[^"]*"[^>]*>(.*?)</a>`)
+
+// _authorBioRE captures the bio spans, freeTextContainer and freeText.
+var _authorBioRE = regexp.MustCompile(`(?s)<span id="freeText(Container)?author\d+"[^>]*>(.*?)</span>`)
+
+// The aggregate rating line carries the exact counts in value-title spans'
+// title attributes ("23807"), next to their formatted text.
+var (
+	_authorAverageRE = regexp.MustCompile(`itemprop=['"]ratingValue['"][^>]*>\s*([\d.]+)`)
+	_authorRatingsRE = regexp.MustCompile(`title="(\d+)"[^>]*itemprop=['"]ratingCount['"]`)
+	_authorReviewsRE = regexp.MustCompile(`title="(\d+)"[^>]*itemprop=['"]reviewCount['"]`)
+	_authorWorksRE   = regexp.MustCompile(`>([\d,]+) distinct works?<`)
+)
@@ -0,0 +1,162 @@
+package internal
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// authorPageHTML is the relevant part of /author/show/5236.Andre_Brink in
+// Goodreads' markup.
+const authorPageHTML = `<div class="leftContainer authorLeftContainer">
+  <a rel="nofollow" title="André Brink" href="/photo/author/5236.Andre_Brink"><img alt="André Brink" itemprop="image" src="https://images.gr-assets.com/authors/5236.jpg" /></a>
+</div>
+<div class="rightContainer">
+  <h1 class="authorName">
+    <span itemprop="name">André Brink</span>
+  </h1>
+  <div class="dataTitle">Born</div>
+  in Vrede, Free State, South Africa
+  <div class="dataItem" itemprop='birthDate'>May 29, 1935</div>
+  <div class="dataTitle">Died</div>
+  <div class="dataItem" itemprop='deathDate'>February 06, 2015</div>
+  <div class="dataTitle">Website</div>
+  <div class="dataItem">
+    <a target="_blank" rel="nofollow" itemprop="url" href="http://www.andrebrink.co.za/">http://www.andrebrink.co.za/</a>
+  </div>
+  <div class="dataTitle">Genre</div>
+  <div class="dataItem">
+    <a href="/genres/fiction">Fiction</a>, <a href="/genres/literary-fiction">Literary Fiction</a>
+  </div>
+  <div class="aboutAuthorInfo">
+    <span id="freeTextContainerauthor5236">André Philippus Brink was a South African novelist…</span>
+    <span id="freeTextauthor5236" style="display:none">André Philippus Brink was a South African novelist, essayist &amp; professor.<br /><br />He wrote in Afrikaans and English.</span>
+  </div>
+  <div class="hreview-aggregate" itemprop="aggregateRating" itemscope="" itemtype="http://schema.org/AggregateRating">
+    Average rating: <span class="rating"><span class="average" itemprop="ratingValue">3.83</span></span>
+    &middot;
+    <span class="votes"><span class="value-title" title="23807" itemprop="ratingCount">23,807 ratings</span></span>
+    &middot;
+    <span class="count"><span class="value-title" title="2123" itemprop="reviewCount">2,123 reviews</span></span>
+    &middot;
+    <a href="/author/list/5236.Andre_Brink">86 distinct works</a>
+  </div>
+</div>`
+
+func TestParseAuthorHTML(t *testing.T) {
+	got, err := ParseAuthorHTML(authorPageHTML, "5236")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Author{
+		ID:           "5236",
+		Name:         "André Brink",
+		URL:          "https://www.goodreads.com/author/show/5236",
+		ImageURL:     "https://images.gr-assets.com/authors/5236.jpg",
+		Bio:          "André Philippus Brink was a South African novelist, essayist & professor.\n\nHe wrote in Afrikaans and English.",
+		BornAt:       "1935-05-29",
+		BornIn:       "Vrede, Free State, South Africa",
+		DiedAt:       "2015-02-06",
+		Website:      "http://www.andrebrink.co.za/",
+		Genres:       []string{"Fiction", "Literary Fiction"},
+		Rating:       "3.83",
+		RatingsCount: 23807,
+		ReviewsCount: 2123,
+		WorksCount:   86,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got  %+v\nwant %+v", got, want)
+	}
+
+	if _, err := ParseAuthorHTML("<html></html>", "1"); err == nil {
+		t.Error("expected an error for a page without an author name")
+	}
+}
+
+func TestAuthorIDFromInput(t *testing.T) {
+	tests := []struct {
+		input, want string
+		ok          bool
+	}{
+		{"5236", "5236", true},
+		{"5236.Andre_Brink", "5236", true},
+		{"https://www.goodreads.com/author/show/5236.Andre_Brink", "5236", true},
+		{"André Brink", "", false},
+	}
+	for _, tt := range tests {
+		id, ok := AuthorIDFromInput(tt.input)
+		if id != tt.want || ok != tt.ok {
+			t.Errorf("AuthorIDFromInput(%q) = %q, %v; want %q, %v", tt.input, id, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestAuthorBookPages(t *testing.T) {
+	var pages []string
+	fetch := func(u string) (string, error) {
+		pu, _ := url.Parse(u)
+		if pu.Path != "/author/list/5236" {
+			t.Errorf("path = %q", pu.Path)
+		}
+		page := pu.Query().Get("page")
+		pages = append(pages, page)
+		n := authorBooksPerPage
+		if page == "3" {
+			n = 5
+		}
+		var sb strings.Builder
+		for i := 0; i < n; i++ {
+			sb.WriteString(searchRowHTML(i+1, "Book", "André Brink"))
+		}
+		return sb.String(), nil
+	}
+
+	tests := []struct {
+		name      string
+		opts      AuthorBooksOptions
+		wantBooks int
+		wantPages []string
+	}{
+		{"all", AuthorBooksOptions{}, 2*authorBooksPerPage + 5, []string{"1", "2", "3"}},
+		{"one page", AuthorBooksOptions{Page: 2}, authorBooksPerPage, []string{"2"}},
+		{"limit", AuthorBooksOptions{Limit: 40}, 40, []string{"1", "2"}},
+	}
+	for _, tt := range tests {
+		pages = nil
+		books, err := authorBookPages(fetch, "5236", tt.opts)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if len(books) != tt.wantBooks || !reflect.DeepEqual(pages, tt.wantPages) {
+			t.Errorf("%s: got %d books from pages %v, want %d from %v", tt.name, len(books), pages, tt.wantBooks, tt.wantPages)
+		}
+	}
+}
+
+func TestSearchAuthors(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]autoCompleteResult{
+			{BookID: "1", Title: "Brink: A Biography", Author: autoCompleteAuth{ID: 99, Name: "Someone Else"}},
+			{BookID: "2", Title: "A Dry White Season", Author: autoCompleteAuth{ID: 5236, Name: "André Brink"}},
+			{BookID: "3", Title: "A Chain of Voices", Author: autoCompleteAuth{ID: 5236, Name: "André Brink"}},
+		})
+	}))
+	defer ts.Close()
+	c := &Client{HTTP: ts.Client()}
+
+	got, err := c.searchAuthors(ts.URL, "andré brink")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, a := range got {
+		ids = append(ids, a.ID)
+	}
+	if want := []string{"5236", "99"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("author IDs = %v, want %v (exact match first, no duplicates)", ids, want)
+	}
+}
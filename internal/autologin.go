@@ -0,0 +1,165 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Auto-login signs in again when a command finds the saved session gone,
+// for cron jobs with nobody around to run `goodreads login`. Amazon locks
+// accounts that fail to sign in too often, so attempts are spaced out:
+// after each one the next waits at least LoginCooldown, doubling with
+// every failure in a row up to MaxLoginCooldown. The attempts are kept in
+// LoginStatePath so the spacing holds across runs.
+const (
+	LoginCooldown    = 15 * time.Minute
+	MaxLoginCooldown = 24 * time.Hour
+)
+
+// staleLoginLock is how old a lock file must be before it is taken to be
+// left behind by a crashed run; a login takes a minute or two at most.
+const staleLoginLock = 10 * time.Minute
+
+// LoginStatePath is where auto-login records its attempts, next to the
+// session file.
+func LoginStatePath() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".goodreads-cli-login-state")
+}
+
+// ClearLoginState forgets earlier auto-login attempts, for after a
+// manual login.
+func ClearLoginState() error {
+	if err := os.Remove(LoginStatePath()); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// loginState is the content of LoginStatePath.
+type loginState struct {
+	LastAttempt time.Time `json:"last_attempt"`
+	Failures    int       `json:"failures"` // failed attempts in a row
+	LastError   string    `json:"last_error,omitempty"`
+}
+
+// nextAttempt is the earliest time auto-login may try again.
+func (s loginState) nextAttempt() time.Time {
+	if s.LastAttempt.IsZero() {
+		return time.Time{}
+	}
+	wait := LoginCooldown
+	for i := 1; i < s.Failures && wait < MaxLoginCooldown; i++ {
+		wait *= 2
+	}
+	return s.LastAttempt.Add(min(wait, MaxLoginCooldown))
+}
+
+// loadLoginState reads path; a missing or unreadable file is no attempts
+// yet.
+func loadLoginState(path string) loginState {
+	var s loginState
+	if data, err := os.ReadFile(path); err == nil {
+		json.Unmarshal(data, &s)
+	}
+	return s
+}
+
+// lockLoginState takes path's lock file, so that of several runs finding
+// the session gone at once only one checks the cooldown and logs in. ok
+// is false if another run holds the lock.
+func lockLoginState(path string) (unlock func(), ok bool, err error) {
+	lock := path + ".lock"
+	f, err := os.OpenFile(lock, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if os.IsExist(err) {
+		if fi, serr := os.Stat(lock); serr == nil && time.Since(fi.ModTime()) > staleLoginLock {
+			os.Remove(lock)
+			f, err = os.OpenFile(lock, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		}
+	}
+	if os.IsExist(err) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	fmt.Fprintln(f, os.Getpid())
+	f.Close()
+	return func() { os.Remove(lock) }, true, nil
+}
+
+func saveLoginState(path string, s loginState) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// EnsureLoggedIn returns nil if b has a Goodreads session, and otherwise
+// ErrNotLoggedIn — unless autoLogin is set, or auto_login is in the
+// config, in which case it logs in b with LoadConfig's credentials,
+// reporting that on w, and carries on. A login that fails, or one the
+// cooldown or another run's login holds back, is a not_logged_in error
+// saying why.
+func EnsureLoggedIn(b *Browser, autoLogin bool, w io.Writer) error {
+	if b.IsLoggedIn() {
+		return nil
+	}
+	cfg, err := LoadConfig()
+	if !autoLogin && (err != nil || !cfg.AutoLogin) {
+		return ErrNotLoggedIn
+	}
+	if err != nil {
+		return WithCode(CodeNotLoggedIn, fmt.Errorf("not logged in, and can't log in automatically: %w", err))
+	}
+
+	path := LoginStatePath()
+	unlock, ok, err := lockLoginState(path)
+	if err != nil {
+		return WithCode(CodeNotLoggedIn, fmt.Errorf("not logged in, and can't lock %s for an automatic login: %w", path, err))
+	}
+	if !ok {
+		return Errorf(CodeNotLoggedIn, "not logged in, and another run is logging in automatically — retry shortly")
+	}
+	defer unlock()
+
+	state := loadLoginState(path)
+	if next := state.nextAttempt(); time.Now().Before(next) {
+		last := state.LastAttempt.Local().Format("2006-01-02 15:04")
+		why := "an automatic login at " + last + " succeeded but the session is gone again"
+		if state.Failures > 0 {
+			why = fmt.Sprintf("the automatic login at %s failed (%s)", last, state.LastError)
+		}
+		return Errorf(CodeNotLoggedIn, "not logged in: %s, and it won't be retried before %s — run 'goodreads login'",
+			why, next.Local().Format("2006-01-02 15:04"))
+	}
+
+	// The attempt is recorded as failed before it starts, so one that
+	// crashes still counts, and none is made that can't be recorded.
+	state.LastAttempt = time.Now()
+	state.Failures++
+	state.LastError = "interrupted"
+	if err := saveLoginState(path, state); err != nil {
+		return WithCode(CodeNotLoggedIn, fmt.Errorf("not logged in, and can't record an automatic login attempt: %w", err))
+	}
+
+	fmt.Fprintln(w, "Session expired — logging in again...")
+	err = Login(b, cfg)
+	b.Log.Record("auto_login", map[string]any{"failures_before": state.Failures - 1}, err)
+	if err != nil {
+		state.LastError = err.Error()
+		saveLoginState(path, state)
+		if CodeOf(err) == CodeError {
+			err = WithCode(CodeNotLoggedIn, err)
+		}
+		return fmt.Errorf("automatic login: %w", err)
+	}
+	state.Failures, state.LastError = 0, ""
+	saveLoginState(path, state)
+	return nil
+}
@@ -0,0 +1,98 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoginState_NextAttempt(t *testing.T) {
+	last := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{0, 15 * time.Minute}, // after a success
+		{1, 15 * time.Minute},
+		{2, 30 * time.Minute},
+		{4, 2 * time.Hour},
+		{7, 16 * time.Hour},
+		{8, 24 * time.Hour},
+		{50, 24 * time.Hour},
+	}
+	for _, tt := range tests {
+		s := loginState{LastAttempt: last, Failures: tt.failures}
+		if got := s.nextAttempt().Sub(last); got != tt.want {
+			t.Errorf("%d failures: wait %v, want %v", tt.failures, got, tt.want)
+		}
+	}
+	if !(loginState{}).nextAttempt().IsZero() {
+		t.Error("no attempts yet should allow one at once")
+	}
+}
+
+func TestLoginState_SaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state")
+	if s := loadLoginState(path); s.Failures != 0 || !s.LastAttempt.IsZero() {
+		t.Errorf("missing file loaded as %+v", s)
+	}
+
+	want := loginState{LastAttempt: time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC), Failures: 2, LastError: "login failed"}
+	if err := saveLoginState(path, want); err != nil {
+		t.Fatal(err)
+	}
+	got := loadLoginState(path)
+	if !got.LastAttempt.Equal(want.LastAttempt) || got.Failures != want.Failures || got.LastError != want.LastError {
+		t.Errorf("loaded %+v, want %+v", got, want)
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi.Mode().Perm() != 0600 {
+		t.Errorf("state file mode = %v, want 0600", fi.Mode().Perm())
+	}
+}
+
+func TestClearLoginState(t *testing.T) {
+	tmpDir := t.TempDir()
+	origHome := os.Getenv("HOME")
+	os.Setenv("HOME", tmpDir)
+	defer os.Setenv("HOME", origHome)
+
+	if err := ClearLoginState(); err != nil {
+		t.Fatalf("ClearLoginState() with no file: %v", err)
+	}
+	saveLoginState(LoginStatePath(), loginState{Failures: 3})
+	if err := ClearLoginState(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(LoginStatePath()); !os.IsNotExist(err) {
+		t.Error("state file still exists after ClearLoginState()")
+	}
+}
+
+func TestLockLoginState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state")
+	unlock, ok, err := lockLoginState(path)
+	if err != nil || !ok {
+		t.Fatalf("first lock = %v, %v", ok, err)
+	}
+	if _, ok, err := lockLoginState(path); err != nil || ok {
+		t.Errorf("second lock while held = %v, %v; want refused", ok, err)
+	}
+	unlock()
+	unlock, ok, err = lockLoginState(path)
+	if err != nil || !ok {
+		t.Fatalf("lock after unlock = %v, %v", ok, err)
+	}
+
+	// A lock left behind by a crashed run is taken over.
+	old := time.Now().Add(-staleLoginLock - time.Minute)
+	os.Chtimes(path+".lock", old, old)
+	if _, ok, err := lockLoginState(path); err != nil || !ok {
+		t.Errorf("lock over a stale one = %v, %v", ok, err)
+	}
+	unlock()
+}
@@ -0,0 +1,106 @@
+package internal
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// NewTab opens another tab in b's browser, sharing its session and
+// interaction log, so several pages can be fetched at once. Closing the
+// tab leaves b and its browser open.
+func (b *Browser) NewTab() (*Browser, error) {
+	page, err := b.Rod.Page(proto.TargetCreateTarget{URL: "about:blank"})
+	if err != nil {
+		b.Log.Record("new_tab", nil, err)
+		return nil, fmt.Errorf("opening a tab: %w", err)
+	}
+	b.Log.Record("new_tab", nil, nil)
+	return &Browser{Rod: b.Rod, Page: page, Log: b.Log, userID: b.userID, shared: true}, nil
+}
+
+// FetchBooks fetches the details of every book in ids using up to tabs
+// browser tabs at once (b's own page is one of them), and calls fn with
+// each result in the order of ids as soon as it and those before it are
+// done. A tab that can't be opened just means fewer run in parallel.
+func (b *Browser) FetchBooks(ids []string, tabs int, fn func(i int, book Book, err error)) {
+	workers := []*Browser{b}
+	for len(workers) < min(tabs, len(ids)) {
+		tab, err := b.NewTab()
+		if err != nil {
+			break
+		}
+		defer tab.Close()
+		workers = append(workers, tab)
+	}
+
+	type result struct {
+		book Book
+		err  error
+	}
+	parallelInOrder(len(ids), len(workers), func(w, i int) result {
+		book, err := workers[w].FetchBookDetails(ids[i])
+		return result{book, err}
+	}, func(i int, r result) {
+		fn(i, r.book, r.err)
+	})
+}
+
+// parallelInOrder runs do for items 0..count-1 on up to workers
+// goroutines, passing each the worker's index so it can use that
+// worker's resources. emit is called from one goroutine at a time, in
+// item order, as soon as an item and every item before it are done.
+func parallelInOrder[R any](count, workers int, do func(worker, i int) R, emit func(i int, r R)) {
+	workers = max(1, min(workers, count))
+	items := make(chan int)
+	done := make([]bool, count)
+	results := make([]R, count)
+	var mu sync.Mutex
+	next := 0
+
+	var wg sync.WaitGroup
+	for w := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range items {
+				r := do(w, i)
+				mu.Lock()
+				results[i], done[i] = r, true
+				for next < count && done[next] {
+					emit(next, results[next])
+					var zero R
+					results[next] = zero
+					next++
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for i := range count {
+		items <- i
+	}
+	close(items)
+	wg.Wait()
+}
+
+// ReadBookList reads book references (IDs, ISBNs, ASINs or URLs) one per
+// line, as for --from-file. Only each line's first field counts, so an ID
+// can be followed by a note such as the title; blank lines and lines
+// starting with # are skipped.
+func ReadBookList(r io.Reader) ([]string, error) {
+	var refs []string
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		fields := strings.Fields(sc.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		refs = append(refs, fields[0])
+	}
+	return refs, sc.Err()
+}
@@ -0,0 +1,63 @@
+package internal
+
+import (
+	"math/rand"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestParallelInOrder(t *testing.T) {
+	for _, workers := range []int{0, 1, 4, 50} {
+		var mu sync.Mutex
+		running, peak := 0, 0
+		var got []int
+		parallelInOrder(20, workers, func(w, i int) int {
+			mu.Lock()
+			running++
+			peak = max(peak, running)
+			mu.Unlock()
+			time.Sleep(time.Duration(rand.Intn(3)) * time.Millisecond)
+			mu.Lock()
+			running--
+			mu.Unlock()
+			return i * i
+		}, func(i, r int) {
+			if r != i*i {
+				t.Errorf("workers %d: item %d got result %d", workers, i, r)
+			}
+			got = append(got, i)
+		})
+
+		want := make([]int, 20)
+		for i := range want {
+			want[i] = i
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("workers %d: emitted %v, want items in order", workers, got)
+		}
+		if limit := max(1, workers); peak > limit {
+			t.Errorf("workers %d: %d ran at once", workers, peak)
+		}
+	}
+}
+
+func TestParallelInOrder_Empty(t *testing.T) {
+	parallelInOrder(0, 4, func(w, i int) int { return i }, func(i, r int) {
+		t.Errorf("emit called for item %d of none", i)
+	})
+}
+
+func TestReadBookList(t *testing.T) {
+	in := "# to shelve\n55145261\n\n  9780525555216  \r\nhttps://www.goodreads.com/book/show/2.Dune\t(2)\n18690730 Tuokio tuulessa\n"
+	got, err := ReadBookList(strings.NewReader(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"55145261", "9780525555216", "https://www.goodreads.com/book/show/2.Dune", "18690730"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadBookList = %q, want %q", got, want)
+	}
+}
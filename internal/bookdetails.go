@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -33,7 +34,7 @@ func ParseBookDetailsFromHTML(html string, legacyID string) (Book, error) {
 
 	book := findBookNode(apollo, legacyID)
 	if book == nil {
-		return b, fmt.Errorf("no Book node found for legacyId %s", legacyID)
+		return b, Errorf(CodeNotFound, "no Book node found for legacyId %s", legacyID)
 	}
 
 	if s, ok := book["title"].(string); ok {
@@ -92,23 +93,144 @@ func ParseBookDetailsFromHTML(html string, legacyID string) (Book, error) {
 		}
 	}
 
-	// Original title comes from the Work that this edition belongs to —
-	// the Book node carries a `work.__ref` edge.
-	if work, ok := book["work"].(map[string]any); ok {
-		if ref, ok := work["__ref"].(string); ok {
-			if w, ok := apollo[ref].(map[string]any); ok {
-				if wd, ok := w["details"].(map[string]any); ok {
-					if s, ok := wd["originalTitle"].(string); ok {
-						b.OriginalTitle = s
+	// Series: each bookSeries entry carries the position and a ref to the
+	// Series node holding the name.
+	if entries, ok := book["bookSeries"].([]any); ok {
+		for _, e := range entries {
+			entry, ok := e.(map[string]any)
+			if !ok {
+				continue
+			}
+			var se SeriesEntry
+			if s, ok := entry["userPosition"].(string); ok {
+				se.Position = s
+			}
+			if series := resolveRef(apollo, entry["series"]); series != nil {
+				se.Name, _ = series["title"].(string)
+				se.URL, _ = series["webUrl"].(string)
+				if m := _seriesIDRE.FindStringSubmatch(se.URL); m != nil {
+					se.ID = m[1]
+				}
+			}
+			if se.Name != "" {
+				b.Series = append(b.Series, se)
+			}
+		}
+	}
+
+	if genres, ok := book["bookGenres"].([]any); ok {
+		for _, g := range genres {
+			if bg, ok := g.(map[string]any); ok {
+				if genre, ok := bg["genre"].(map[string]any); ok {
+					if s, ok := genre["name"].(string); ok {
+						b.Genres = append(b.Genres, s)
 					}
 				}
 			}
 		}
 	}
 
+	// Original title, awards, characters, places and rating statistics
+	// come from the Work that this edition belongs to — the Book node
+	// carries a `work.__ref` edge.
+	if w := resolveRef(apollo, book["work"]); w != nil {
+		if n, ok := w["legacyId"].(float64); ok {
+			b.WorkID = strconv.FormatInt(int64(n), 10)
+		}
+		if wd, ok := w["details"].(map[string]any); ok {
+			if s, ok := wd["originalTitle"].(string); ok {
+				b.OriginalTitle = s
+			}
+			applyWorkDetails(&b, wd)
+		}
+		if stats, ok := w["stats"].(map[string]any); ok {
+			applyWorkStats(&b, stats)
+		}
+	}
+
 	return b, nil
 }
 
+// resolveRef follows an Apollo `{"__ref": "Type:…"}` edge to its node.
+func resolveRef(apollo map[string]any, edge any) map[string]any {
+	e, ok := edge.(map[string]any)
+	if !ok {
+		return nil
+	}
+	ref, ok := e["__ref"].(string)
+	if !ok {
+		return nil
+	}
+	node, _ := apollo[ref].(map[string]any)
+	return node
+}
+
+func applyWorkDetails(b *Book, wd map[string]any) {
+	if awards, ok := wd["awardsWon"].([]any); ok {
+		for _, a := range awards {
+			award, ok := a.(map[string]any)
+			if !ok {
+				continue
+			}
+			var aw Award
+			aw.Name, _ = award["name"].(string)
+			aw.Category, _ = award["category"].(string)
+			aw.Designation, _ = award["designation"].(string)
+			if ms, ok := award["awardedAt"].(float64); ok {
+				aw.Year = time.UnixMilli(int64(ms)).UTC().Year()
+			}
+			if aw.Name != "" {
+				b.Awards = append(b.Awards, aw)
+			}
+		}
+	}
+	if chars, ok := wd["characters"].([]any); ok {
+		for _, c := range chars {
+			if ch, ok := c.(map[string]any); ok {
+				if s, ok := ch["name"].(string); ok && s != "" {
+					b.Characters = append(b.Characters, s)
+				}
+			}
+		}
+	}
+	if places, ok := wd["places"].([]any); ok {
+		for _, p := range places {
+			pl, ok := p.(map[string]any)
+			if !ok {
+				continue
+			}
+			var place Place
+			place.Name, _ = pl["name"].(string)
+			place.Country, _ = pl["countryName"].(string)
+			if place.Name != "" {
+				b.Places = append(b.Places, place)
+			}
+		}
+	}
+}
+
+func applyWorkStats(b *Book, stats map[string]any) {
+	if f, ok := stats["averageRating"].(float64); ok {
+		b.Rating = strconv.FormatFloat(f, 'f', 2, 64)
+	}
+	if n, ok := stats["ratingsCount"].(float64); ok {
+		b.RatingsCount = int(n)
+	}
+	if n, ok := stats["textReviewsCount"].(float64); ok {
+		b.ReviewsCount = int(n)
+	}
+	if dist, ok := stats["ratingsCountDist"].([]any); ok {
+		b.RatingDist = make([]int, 0, len(dist))
+		for _, d := range dist {
+			n, _ := d.(float64)
+			b.RatingDist = append(b.RatingDist, int(n))
+		}
+	}
+}
+
+// _seriesIDRE reads the series ID from its URL (/series/45175-dune).
+var _seriesIDRE = regexp.MustCompile(`/series/(\d+)`)
+
 // extractApolloState pulls the __NEXT_DATA__ payload's apolloState map
 // out of the page. Returns the flat reference map keyed by entity
 // references like "Book:kca://..." or "Work:kca://...".
@@ -116,7 +238,7 @@ func extractApolloState(html string) (map[string]any, error) {
 	re := regexp.MustCompile(`(?s)__NEXT_DATA__"\s+type="application/json"\s*>(.*?)</script>`)
 	m := re.FindStringSubmatch(html)
 	if len(m) < 2 {
-		return nil, fmt.Errorf("__NEXT_DATA__ block not found")
+		return nil, Errorf(CodeSelectorDrift, "__NEXT_DATA__ block not found")
 	}
 	var payload struct {
 		Props struct {
@@ -129,7 +251,7 @@ func extractApolloState(html string) (map[string]any, error) {
 		return nil, fmt.Errorf("decoding __NEXT_DATA__: %w", err)
 	}
 	if payload.Props.PageProps.ApolloState == nil {
-		return nil, fmt.Errorf("apolloState missing from __NEXT_DATA__")
+		return nil, Errorf(CodeSelectorDrift, "apolloState missing from __NEXT_DATA__")
 	}
 	return payload.Props.PageProps.ApolloState, nil
 }
@@ -167,7 +289,7 @@ func extractJSONLD(html string) (map[string]any, error) {
 	re := regexp.MustCompile(`(?s)<script type="application/ld\+json">(.*?)</script>`)
 	m := re.FindStringSubmatch(html)
 	if len(m) < 2 {
-		return nil, fmt.Errorf("JSON-LD block not found")
+		return nil, Errorf(CodeSelectorDrift, "JSON-LD block not found")
 	}
 	var ld map[string]any
 	if err := json.Unmarshal([]byte(m[1]), &ld); err != nil {
@@ -3,6 +3,7 @@ package internal
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 )
 
@@ -81,3 +82,68 @@ func TestParseBookDetailsFromHTML(t *testing.T) {
 		t.Errorf("Format = %q, want %q", got.Format, want.Format)
 	}
 }
+
+// TestParseBookDetailsFromHTML_WorkDetails checks the work-level fields of
+// the same fixture: the work ID, genres, rating statistics, the Booker
+// nomination, and the characters and places of the story.
+func TestParseBookDetailsFromHTML_WorkDetails(t *testing.T) {
+	html, err := os.ReadFile(filepath.Join("testdata", "book_18690730_tuokio_tuulessa.html"))
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+	got, err := ParseBookDetailsFromHTML(string(html), "18690730")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+
+	if got.WorkID != "2041274" {
+		t.Errorf("WorkID = %q, want 2041274", got.WorkID)
+	}
+	if got.Rating != "4.06" || got.RatingsCount != 889 || got.ReviewsCount != 85 {
+		t.Errorf("stats = %s / %d ratings / %d reviews, want 4.06 / 889 / 85", got.Rating, got.RatingsCount, got.ReviewsCount)
+	}
+	if want := []int{9, 32, 160, 382, 306}; !reflect.DeepEqual(got.RatingDist, want) {
+		t.Errorf("RatingDist = %v, want %v", got.RatingDist, want)
+	}
+	if len(got.Genres) < 3 || got.Genres[0] != "Africa" || got.Genres[1] != "Fiction" {
+		t.Errorf("Genres = %v, want Africa, Fiction, … in page order", got.Genres)
+	}
+	wantAward := Award{Name: "Booker Prize", Year: 1976, Designation: "NOMINEE"}
+	if len(got.Awards) != 1 || got.Awards[0] != wantAward {
+		t.Errorf("Awards = %+v, want [%+v]", got.Awards, wantAward)
+	}
+	if len(got.Characters) != 1 || got.Characters[0] != "Elisabeth Larsson, Adam Mantoor" {
+		t.Errorf("Characters = %v", got.Characters)
+	}
+	if want := []Place{{Name: "Cape of Good Hope", Country: "South Africa"}}; !reflect.DeepEqual(got.Places, want) {
+		t.Errorf("Places = %+v, want %+v", got.Places, want)
+	}
+	if len(got.Series) != 0 {
+		t.Errorf("Series = %+v, want none — the book is a standalone", got.Series)
+	}
+}
+
+// TestParseBookDetailsFromHTML_Series covers bookSeries, which the
+// standalone fixture leaves empty: a book in two series, one with a
+// fractional position.
+func TestParseBookDetailsFromHTML_Series(t *testing.T) {
+	html := `<script id="__NEXT_DATA__" type="application/json">{"props":{"pageProps":{"apolloState":{
+		"Book:kca://book/1": {"legacyId": 44767458, "title": "Dune", "bookSeries": [
+			{"__typename": "BookSeries", "userPosition": "1", "series": {"__ref": "Series:kca://series/1"}},
+			{"__typename": "BookSeries", "userPosition": "0.5", "series": {"__ref": "Series:kca://series/2"}}
+		]},
+		"Series:kca://series/1": {"__typename": "Series", "title": "Dune", "webUrl": "https://www.goodreads.com/series/45175-dune"},
+		"Series:kca://series/2": {"__typename": "Series", "title": "Dune Chronicles Omnibus", "webUrl": "https://www.goodreads.com/series/99999-omnibus"}
+	}}}}</script>`
+	got, err := ParseBookDetailsFromHTML(html, "44767458")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	want := []SeriesEntry{
+		{ID: "45175", Name: "Dune", Position: "1", URL: "https://www.goodreads.com/series/45175-dune"},
+		{ID: "99999", Name: "Dune Chronicles Omnibus", Position: "0.5", URL: "https://www.goodreads.com/series/99999-omnibus"},
+	}
+	if !reflect.DeepEqual(got.Series, want) {
+		t.Errorf("Series = %+v\nwant %+v", got.Series, want)
+	}
+}
@@ -4,10 +4,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/go-rod/rod"
 	"github.com/go-rod/rod/lib/launcher"
+	"github.com/go-rod/rod/lib/launcher/flags"
 	"github.com/go-rod/rod/lib/proto"
 )
 
@@ -20,10 +22,55 @@ type Browser struct {
 	Rod  *rod.Browser
 	Page *rod.Page
 	Log  *InteractionLog
+
+	userID string // cached by UserID
+	shared bool   // a tab in a browser we didn't launch: the daemon's or --browser-url
+}
+
+// BrowserOptions says which Chrome to drive. The zero value launches a
+// visible Chromium from rod's download cache; Headless hides it.
+type BrowserOptions struct {
+	Headless bool
+	// URL is the DevTools address of an already running Chrome — a
+	// ws:// or wss:// endpoint such as browserless, or an http://host:port
+	// / bare port whose /json/version names one. Nothing is launched.
+	URL string
+	// Bin is the Chrome or Chromium binary to launch instead of rod's.
+	Bin string
+	// Flags are extra Chrome command-line flags, "name" or "name=value",
+	// with or without leading dashes: "proxy-server=http://proxy:3128",
+	// "user-data-dir=/tmp/profile".
+	Flags []string
+}
+
+// WithEnv fills in what o leaves unset from GOODREADS_BROWSER_URL and
+// GOODREADS_BROWSER_BIN, and puts the space-separated flags in
+// GOODREADS_BROWSER_FLAGS before o's own, so flags given on the command
+// line override the environment.
+func (o BrowserOptions) WithEnv() BrowserOptions {
+	if o.URL == "" {
+		o.URL = os.Getenv("GOODREADS_BROWSER_URL")
+	}
+	if o.Bin == "" {
+		o.Bin = os.Getenv("GOODREADS_BROWSER_BIN")
+	}
+	if env := strings.Fields(os.Getenv("GOODREADS_BROWSER_FLAGS")); len(env) > 0 {
+		o.Flags = append(env, o.Flags...)
+	}
+	return o
 }
 
-// NewBrowser launches a Chrome instance and navigates to goodreads.com.
-// Set headless to false to see the browser for debugging.
+// useDaemon reports whether a running browser daemon can serve o: not
+// when o names its own Chrome, binary or flags, which the daemon's
+// browser wasn't started with.
+func (o BrowserOptions) useDaemon() bool {
+	return o.URL == "" && o.Bin == "" && len(o.Flags) == 0
+}
+
+// NewBrowser connects to Chrome and navigates to goodreads.com: to
+// opts.URL if given, else — unless opts sets a binary or flags — to a
+// tab in the browser daemon (`goodreads daemon start`) if it is running,
+// else to a newly launched Chromium.
 //
 // Chromium's setuid sandbox depends on either kernel.unprivileged_userns_clone
 // being enabled or running as root with the helper binary. Many Linux
@@ -33,31 +80,39 @@ type Browser struct {
 // user-facing browser; the sandbox guarantees aren't load-bearing here.
 // Disable it on Linux unconditionally and let GOODREADS_BROWSER_SANDBOX=1
 // force it back on for the cases where it actually works.
-func NewBrowser(headless bool) (*Browser, error) {
-	l := launcher.New().
-		Headless(headless)
-	if os.Getenv("GOODREADS_BROWSER_SANDBOX") != "1" {
-		l = l.NoSandbox(true)
+func NewBrowser(opts BrowserOptions) (*Browser, error) {
+	if opts.useDaemon() {
+		if b, err := connectDaemon(opts.Headless); b != nil || err != nil {
+			return b, err
+		}
 	}
-	u, err := l.Launch()
+
+	u, l, err := devToolsURL(opts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to launch browser: %w\n\nOn Linux, install required dependencies:\n  sudo apt install -y libnss3 libatk1.0-0 libatk-bridge2.0-0 libcups2 libxdamage1 libxrandr2 libgbm1 libpango-1.0-0 libcairo2 libasound2 libxcomposite1 libxfixes3 libxkbcommon0 libdrm2 libatspi2.0-0", err)
+		return nil, err
 	}
 
 	browser := rod.New().ControlURL(u)
 	if err := browser.Connect(); err != nil {
-		return nil, fmt.Errorf("failed to connect to browser: %w", err)
+		if l != nil {
+			l.Kill()
+		}
+		return nil, fmt.Errorf("failed to connect to browser at %s: %w", u, err)
 	}
 
 	page, err := browser.Page(proto.TargetCreateTarget{URL: "https://www.goodreads.com"})
 	if err != nil {
-		browser.MustClose()
+		if l != nil {
+			browser.MustClose()
+		}
 		return nil, fmt.Errorf("failed to open page: %w", err)
 	}
 	page.MustWaitStable()
 
-	b := &Browser{Rod: browser, Page: page, Log: NewInteractionLog()}
-	b.Log.Record("browser_launch", map[string]any{"headless": headless}, nil)
+	// A browser we didn't launch is only borrowed: Close must leave it
+	// running, as with the daemon's.
+	b := &Browser{Rod: browser, Page: page, Log: NewInteractionLog(), shared: l == nil}
+	b.Log.Record("browser_launch", map[string]any{"headless": opts.Headless, "remote": opts.URL != ""}, nil)
 
 	if err := b.LoadCookies(); err == nil {
 		// Reload page with cookies applied
@@ -68,8 +123,61 @@ func NewBrowser(headless bool) (*Browser, error) {
 	return b, nil
 }
 
-// Close cleans up the browser.
+// devToolsURL returns the DevTools URL to connect to: opts.URL
+// resolved to a websocket address, or that of a Chromium it launches, in
+// which case the launcher is returned too. See NewBrowser for why the
+// sandbox is off by default.
+func devToolsURL(opts BrowserOptions) (string, *launcher.Launcher, error) {
+	if opts.URL != "" {
+		if strings.HasPrefix(opts.URL, "ws://") || strings.HasPrefix(opts.URL, "wss://") {
+			return opts.URL, nil, nil
+		}
+		u, err := launcher.ResolveURL(opts.URL)
+		if err != nil {
+			return "", nil, fmt.Errorf("finding the DevTools endpoint of %s: %w", opts.URL, err)
+		}
+		return u, nil, nil
+	}
+
+	l := newLauncher(opts)
+	u, err := l.Launch()
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to launch browser: %w\n\nOn Linux, install required dependencies:\n  sudo apt install -y libnss3 libatk1.0-0 libatk-bridge2.0-0 libcups2 libxdamage1 libxrandr2 libgbm1 libpango-1.0-0 libcairo2 libasound2 libxcomposite1 libxfixes3 libxkbcommon0 libdrm2 libatspi2.0-0\n\nOr point --browser-bin at an installed Chrome, or --browser-url at a running one.", err)
+	}
+	return u, l, nil
+}
+
+// newLauncher configures, but doesn't start, the Chromium launcher.
+func newLauncher(opts BrowserOptions) *launcher.Launcher {
+	l := launcher.New().
+		Headless(opts.Headless)
+	if os.Getenv("GOODREADS_BROWSER_SANDBOX") != "1" {
+		l = l.NoSandbox(true)
+	}
+	if opts.Bin != "" {
+		l = l.Bin(opts.Bin)
+	}
+	for _, f := range opts.Flags {
+		name, value, hasValue := strings.Cut(strings.TrimLeft(f, "-"), "=")
+		if name == "" {
+			continue
+		}
+		if hasValue {
+			l = l.Set(flags.Flag(name), value)
+		} else {
+			l = l.Set(flags.Flag(name))
+		}
+	}
+	return l
+}
+
+// Close cleans up the browser. A borrowed browser — the daemon's or one
+// given by URL — only has our tab closed and keeps running.
 func (b *Browser) Close() {
+	if b.shared {
+		b.Page.Close()
+		return
+	}
 	b.Rod.MustClose()
 }
 
@@ -139,23 +247,93 @@ func (b *Browser) FetchBookDetails(bookID string) (Book, error) {
 }
 
 // ListShelf navigates through the browser to the logged-in user's shelf
-// page and returns the parsed books. Same WAF motivation as
-// FetchBookDetails.
-func (b *Browser) ListShelf(shelfName string) ([]Book, error) {
+// page and returns the parsed books from every page. Same WAF motivation
+// as FetchBookDetails.
+func (b *Browser) ListShelf(shelfName string) ([]ShelfEntry, error) {
+	books := []ShelfEntry{}
+	err := b.ListShelfPages(shelfName, ShelfListOptions{}, func(page []ShelfEntry) error {
+		books = append(books, page...)
+		return nil
+	})
+	return books, err
+}
+
+// ListShelfPages is ListShelf with paging options, calling fn once per page
+// as results arrive.
+func (b *Browser) ListShelfPages(shelfName string, opts ShelfListOptions, fn func([]ShelfEntry) error) error {
+	userID, err := b.UserID()
+	if err != nil {
+		return err
+	}
+	return walkShelf(b.FetchRenderedHTML, userID, shelfName, opts, fn)
+}
+
+// UserID returns the logged-in user's numeric Goodreads ID, discovered from
+// the signed-in home page on first use and cached for the session.
+func (b *Browser) UserID() (string, error) {
+	if b.userID != "" {
+		return b.userID, nil
+	}
 	homeHTML, err := b.FetchRenderedHTML(BaseURL + "/")
 	if err != nil {
-		return nil, fmt.Errorf("fetching home page: %w", err)
+		return "", fmt.Errorf("fetching home page: %w", err)
 	}
 	userID, err := ExtractUserIDFromHomeHTML(homeHTML)
 	if err != nil {
-		return nil, err
+		return "", err
 	}
-	u := fmt.Sprintf("%s/review/list/%s?shelf=%s&per_page=100", BaseURL, userID, shelfName)
-	html, err := b.FetchRenderedHTML(u)
+	b.userID = userID
+	return userID, nil
+}
+
+// postFormJS submits a form-encoded request from inside the page the way
+// Rails UJS does for data-remote forms and data-method links: the CSRF
+// token comes from the page's csrf-token meta tag, and PUT/PATCH/DELETE
+// tunnel through a POST with a _method field. Resolves to the HTTP status.
+const postFormJS = `async (method, path, fields) => {
+	const meta = document.querySelector('meta[name="csrf-token"]');
+	const token = meta ? meta.content : '';
+	const body = new URLSearchParams();
+	if (method !== 'POST') body.append('_method', method.toLowerCase());
+	for (const [k, v] of Object.entries(fields || {})) body.append(k, v);
+	body.append('authenticity_token', token);
+	const res = await fetch(path, {
+		method: 'POST',
+		body,
+		credentials: 'same-origin',
+		headers: {
+			'X-CSRF-Token': token,
+			'X-Requested-With': 'XMLHttpRequest',
+			'Accept': 'text/javascript, text/html, application/json',
+		},
+	});
+	return res.status;
+}`
+
+// PostForm sends a form request to a Goodreads Rails endpoint using the
+// browser's session and the CSRF token of the currently loaded page, which
+// must be a classic (non-Next.js) goodreads.com page such as /review/list.
+// Used for the account operations that have a plain Rails endpoint but no
+// stable control to click — shelf management in particular. Field values
+// are left out of the interaction log; only their names are recorded.
+func (b *Browser) PostForm(method, path string, fields map[string]string) error {
+	names := make([]string, 0, len(fields))
+	for k := range fields {
+		names = append(names, k)
+	}
+	res, err := b.Page.Eval(postFormJS, method, path, fields)
+	status := 0
+	if err == nil && res != nil {
+		status = res.Value.Int()
+	}
+	b.Log.Record("post_form", map[string]any{"method": method, "path": path, "fields": names, "status": status}, err)
 	if err != nil {
-		return nil, fmt.Errorf("fetching shelf %q: %w", shelfName, err)
+		return fmt.Errorf("submitting %s %s: %w", method, path, err)
+	}
+	if status < 200 || status >= 400 {
+		return httpStatusError(status, "%s %s returned status %d", method, path, status)
 	}
-	return ParseShelfHTML(html)
+	return nil
 }
 
 // IsLoggedIn checks if the user is logged in by looking for user-specific elements.
@@ -0,0 +1,96 @@
+package internal
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-rod/rod/lib/launcher/flags"
+)
+
+func TestBrowserOptions_WithEnv(t *testing.T) {
+	t.Setenv("GOODREADS_BROWSER_URL", "ws://chrome:3000")
+	t.Setenv("GOODREADS_BROWSER_BIN", "/usr/bin/chromium")
+	t.Setenv("GOODREADS_BROWSER_FLAGS", "--proxy-server=http://proxy:3128  --lang=fi")
+
+	got := BrowserOptions{Headless: true, Bin: "/opt/chrome", Flags: []string{"lang=en"}}.WithEnv()
+	want := BrowserOptions{
+		Headless: true,
+		URL:      "ws://chrome:3000",
+		Bin:      "/opt/chrome",
+		Flags:    []string{"--proxy-server=http://proxy:3128", "--lang=fi", "lang=en"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("WithEnv = %+v, want %+v", got, want)
+	}
+}
+
+func TestNewLauncher(t *testing.T) {
+	l := newLauncher(BrowserOptions{
+		Headless: true,
+		Bin:      "/opt/chrome",
+		Flags:    []string{"--proxy-server=http://proxy:3128", "user-data-dir=/tmp/profile", "lang=fi", "lang=en", "--disable-gpu", "--"},
+	})
+	for name, want := range map[flags.Flag]string{
+		"proxy-server":  "http://proxy:3128",
+		"user-data-dir": "/tmp/profile",
+		"lang":          "en", // the later flag wins
+		flags.Bin:       "/opt/chrome",
+	} {
+		if got := l.Get(name); got != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+	if !l.Has("disable-gpu") || !l.Has(flags.Headless) {
+		t.Errorf("missing valueless flags: %v", l.FormatArgs())
+	}
+}
+
+func TestDevToolsURL_WebSocketURLIsUsedAsIs(t *testing.T) {
+	u, l, err := devToolsURL(BrowserOptions{URL: "wss://chrome.example.com?token=x"})
+	if err != nil || l != nil || u != "wss://chrome.example.com?token=x" {
+		t.Errorf("devToolsURL = %q, %v, %v", u, l, err)
+	}
+}
+
+// TestLaunchChromium_ResolvesHTTPEndpoint: a host:port is asked for its
+// websocket URL, keeping the host we reached it on.
+func TestDevToolsURL_ResolvesHTTPEndpoint(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/json/version" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"webSocketDebuggerUrl":"ws://0.0.0.0:9222/devtools/browser/abc"}`))
+	}))
+	defer srv.Close()
+
+	host := strings.TrimPrefix(srv.URL, "http://")
+	u, l, err := devToolsURL(BrowserOptions{URL: host})
+	if err != nil || l != nil {
+		t.Fatalf("devToolsURL = %v, %v", l, err)
+	}
+	if want := "ws://" + host + "/devtools/browser/abc"; u != want {
+		t.Errorf("url = %q, want %q", u, want)
+	}
+}
+
+// TestBrowserOptions_UseDaemon: the daemon's browser can't honour another
+// binary or extra flags, so those launch their own.
+func TestBrowserOptions_UseDaemon(t *testing.T) {
+	for _, tt := range []struct {
+		opts BrowserOptions
+		want bool
+	}{
+		{BrowserOptions{Headless: true}, true},
+		{BrowserOptions{URL: "localhost:9222"}, false},
+		{BrowserOptions{Bin: "/opt/chrome"}, false},
+		{BrowserOptions{Flags: []string{"proxy-server=http://proxy:3128"}}, false},
+	} {
+		if got := tt.opts.useDaemon(); got != tt.want {
+			t.Errorf("%+v.useDaemon() = %v, want %v", tt.opts, got, tt.want)
+		}
+	}
+}
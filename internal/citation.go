@@ -0,0 +1,356 @@
+package internal
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// CitationFormats are the formats WriteCitations accepts.
+var CitationFormats = []string{"bibtex", "csl-json", "ris", "marcxml"}
+
+// WriteCitations writes books as bibliography records for reference
+// managers such as Zotero: BibTeX, CSL-JSON, RIS or MARCXML. Each record
+// gets a citation key from CitationKeys.
+func WriteCitations(w io.Writer, format string, books []Book) error {
+	keys := CitationKeys(books)
+	switch format {
+	case "bibtex":
+		return writeBibTeX(w, books, keys)
+	case "csl-json":
+		return writeCSLJSON(w, books, keys)
+	case "ris":
+		return writeRIS(w, books, keys)
+	case "marcxml":
+		return writeMARCXML(w, books)
+	}
+	return Errorf(CodeInvalidInput, "unknown citation format %q — use %s", format, strings.Join(CitationFormats, ", "))
+}
+
+// CitationKeys returns a key per book in the usual author-year-word shape
+// with the Goodreads ID appended ("brink1978tuokio-18690730"), folded to
+// ASCII so every format accepts it. A key depends only on its book, so it
+// stays the same from one export to the next, and the ID keeps two
+// editions or namesakes apart.
+func CitationKeys(books []Book) []string {
+	keys := make([]string, len(books))
+	for i, b := range books {
+		keys[i] = citationKey(b)
+	}
+	return keys
+}
+
+func citationKey(b Book) string {
+	_, family := splitPersonName(b.Author)
+	word := ""
+	for _, w := range strings.Fields(b.Title) {
+		if w = asciiKey(w); w != "" && !citationStopWords[w] {
+			word = w
+			break
+		}
+	}
+	key := asciiKey(family) + b.Year + word
+	switch id := asciiKey(b.ID); {
+	case key == "":
+		key = "goodreads" + id
+	case id != "":
+		key += "-" + id
+	}
+	return key
+}
+
+// citationStopWords are leading articles skipped when picking the key's
+// title word.
+var citationStopWords = map[string]bool{"a": true, "an": true, "the": true, "n": true}
+
+// asciiKey folds s to lowercase ASCII letters and digits, dropping
+// diacritics it knows and any other character.
+func asciiKey(s string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if f, ok := asciiFold[r]; ok {
+			sb.WriteString(f)
+		} else if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// splitPersonName splits "André P. Brink" into given "André P." and
+// family "Brink".
+func splitPersonName(name string) (given, family string) {
+	fields := strings.Fields(name)
+	if len(fields) == 0 {
+		return "", ""
+	}
+	last := len(fields) - 1
+	return strings.Join(fields[:last], " "), fields[last]
+}
+
+func writeBibTeX(w io.Writer, books []Book, keys []string) error {
+	for i, b := range books {
+		var sb strings.Builder
+		fmt.Fprintf(&sb, "@book{%s,\n", keys[i])
+		field := func(name, value string) {
+			if value != "" {
+				fmt.Fprintf(&sb, "  %s = {%s},\n", name, bibtexEscape(value))
+			}
+		}
+		field("author", authorLastFirst(b.Author))
+		field("title", b.Title)
+		field("origtitle", differentFrom(b.OriginalTitle, b.Title))
+		field("publisher", b.Publisher)
+		field("year", b.Year)
+		field("isbn", firstNonEmpty(b.ISBN13, b.ISBN))
+		if b.Pages > 0 {
+			field("pagetotal", fmt.Sprint(b.Pages))
+		}
+		field("language", b.Language)
+		field("url", b.URL)
+		sb.WriteString("}\n")
+		if i < len(books)-1 {
+			sb.WriteString("\n")
+		}
+		if _, err := io.WriteString(w, sb.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// bibtexEscape escapes LaTeX's special characters and writes accented
+// letters as LaTeX accent commands ("ä" → {\"a}), so the output works
+// with 8-bit BibTeX as well as biber. Characters without a known
+// command are left as UTF-8.
+func bibtexEscape(s string) string {
+	var sb strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '\\':
+			sb.WriteString(`\textbackslash{}`)
+		case strings.ContainsRune("&%$#_{}", r):
+			sb.WriteByte('\\')
+			sb.WriteRune(r)
+		case r == '~':
+			sb.WriteString(`\textasciitilde{}`)
+		case r == '^':
+			sb.WriteString(`\textasciicircum{}`)
+		default:
+			if cmd, ok := latexAccents[r]; ok {
+				sb.WriteString("{" + cmd + "}")
+			} else {
+				sb.WriteRune(r)
+			}
+		}
+	}
+	return sb.String()
+}
+
+// cslItem is a CSL-JSON book record.
+type cslItem struct {
+	ID            string      `json:"id"`
+	Type          string      `json:"type"`
+	Title         string      `json:"title"`
+	OriginalTitle string      `json:"original-title,omitempty"`
+	Author        []cslName   `json:"author,omitempty"`
+	Issued        *cslDate    `json:"issued,omitempty"`
+	Publisher     string      `json:"publisher,omitempty"`
+	ISBN          string      `json:"ISBN,omitempty"`
+	Pages         json.Number `json:"number-of-pages,omitempty"`
+	Language      string      `json:"language,omitempty"`
+	URL           string      `json:"URL,omitempty"`
+}
+
+type cslName struct {
+	Family string `json:"family,omitempty"`
+	Given  string `json:"given,omitempty"`
+}
+
+type cslDate struct {
+	DateParts [][]json.Number `json:"date-parts"`
+}
+
+func writeCSLJSON(w io.Writer, books []Book, keys []string) error {
+	items := make([]cslItem, 0, len(books))
+	for i, b := range books {
+		it := cslItem{
+			ID:            keys[i],
+			Type:          "book",
+			Title:         b.Title,
+			OriginalTitle: differentFrom(b.OriginalTitle, b.Title),
+			Publisher:     b.Publisher,
+			ISBN:          firstNonEmpty(b.ISBN13, b.ISBN),
+			Language:      b.Language,
+			URL:           b.URL,
+		}
+		if given, family := splitPersonName(b.Author); family != "" {
+			it.Author = []cslName{{Family: family, Given: given}}
+		}
+		if b.Year != "" {
+			it.Issued = &cslDate{DateParts: [][]json.Number{{json.Number(b.Year)}}}
+		}
+		if b.Pages > 0 {
+			it.Pages = json.Number(fmt.Sprint(b.Pages))
+		}
+		items = append(items, it)
+	}
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	return enc.Encode(items)
+}
+
+func writeRIS(w io.Writer, books []Book, keys []string) error {
+	for i, b := range books {
+		var sb strings.Builder
+		tag := func(name, value string) {
+			if value != "" {
+				fmt.Fprintf(&sb, "%s  - %s\r\n", name, strings.Join(strings.Fields(value), " "))
+			}
+		}
+		tag("TY", "BOOK")
+		tag("ID", keys[i])
+		tag("AU", authorLastFirst(b.Author))
+		tag("TI", b.Title)
+		tag("OP", differentFrom(b.OriginalTitle, b.Title))
+		tag("PY", b.Year)
+		tag("PB", b.Publisher)
+		tag("SN", firstNonEmpty(b.ISBN13, b.ISBN))
+		if b.Pages > 0 {
+			tag("SP", fmt.Sprint(b.Pages))
+		}
+		tag("LA", b.Language)
+		tag("UR", b.URL)
+		sb.WriteString("ER  - \r\n")
+		if _, err := io.WriteString(w, sb.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MARCXML records, reduced to the fields a book record needs.
+type marcCollection struct {
+	XMLName xml.Name     `xml:"http://www.loc.gov/MARC21/slim collection"`
+	Records []marcRecord `xml:"record"`
+}
+
+type marcRecord struct {
+	Leader        string          `xml:"leader"`
+	ControlFields []marcControl   `xml:"controlfield"`
+	DataFields    []marcDataField `xml:"datafield"`
+}
+
+type marcControl struct {
+	Tag   string `xml:"tag,attr"`
+	Value string `xml:",chardata"`
+}
+
+type marcDataField struct {
+	Tag       string         `xml:"tag,attr"`
+	Ind1      string         `xml:"ind1,attr"`
+	Ind2      string         `xml:"ind2,attr"`
+	Subfields []marcSubfield `xml:"subfield"`
+}
+
+type marcSubfield struct {
+	Code  string `xml:"code,attr"`
+	Value string `xml:",chardata"`
+}
+
+func writeMARCXML(w io.Writer, books []Book) error {
+	coll := marcCollection{}
+	for _, b := range books {
+		rec := marcRecord{
+			Leader:        "00000nam a2200000 i 4500",
+			ControlFields: []marcControl{{Tag: "001", Value: b.ID}, {Tag: "003", Value: "Goodreads"}},
+		}
+		field := func(tag, ind1, ind2 string, subfields ...string) {
+			f := marcDataField{Tag: tag, Ind1: ind1, Ind2: ind2}
+			for i := 0; i+1 < len(subfields); i += 2 {
+				if subfields[i+1] != "" {
+					f.Subfields = append(f.Subfields, marcSubfield{Code: subfields[i], Value: subfields[i+1]})
+				}
+			}
+			if len(f.Subfields) > 0 {
+				rec.DataFields = append(rec.DataFields, f)
+			}
+		}
+		field("020", " ", " ", "a", b.ISBN13)
+		field("020", " ", " ", "a", b.ISBN)
+		field("100", "1", " ", "a", authorLastFirst(b.Author))
+		field("240", "1", "0", "a", differentFrom(b.OriginalTitle, b.Title))
+		field("245", "1", "0", "a", b.Title)
+		field("264", " ", "1", "b", b.Publisher, "c", b.Year)
+		if b.Pages > 0 {
+			field("300", " ", " ", "a", fmt.Sprintf("%d pages", b.Pages))
+		}
+		if b.Language != "" {
+			field("546", " ", " ", "a", "In "+b.Language+".")
+		}
+		field("856", "4", "0", "u", b.URL)
+		coll.Records = append(coll.Records, rec)
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(coll); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// differentFrom returns s unless it repeats other.
+func differentFrom(s, other string) string {
+	if s == other {
+		return ""
+	}
+	return s
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// latexAccents maps the accented letters of European languages to LaTeX
+// commands.
+var latexAccents = map[rune]string{
+	'à': "\\`a", 'á': `\'a`, 'â': `\^a`, 'ã': `\~a`, 'ä': `\"a`, 'å': `\aa`, 'æ': `\ae`,
+	'À': "\\`A", 'Á': `\'A`, 'Â': `\^A`, 'Ã': `\~A`, 'Ä': `\"A`, 'Å': `\AA`, 'Æ': `\AE`,
+	'ç': `\c{c}`, 'Ç': `\c{C}`,
+	'è': "\\`e", 'é': `\'e`, 'ê': `\^e`, 'ë': `\"e`,
+	'È': "\\`E", 'É': `\'E`, 'Ê': `\^E`, 'Ë': `\"E`,
+	'ì': "\\`i", 'í': `\'i`, 'î': `\^i`, 'ï': `\"i`,
+	'Ì': "\\`I", 'Í': `\'I`, 'Î': `\^I`, 'Ï': `\"I`,
+	'ñ': `\~n`, 'Ñ': `\~N`,
+	'ò': "\\`o", 'ó': `\'o`, 'ô': `\^o`, 'õ': `\~o`, 'ö': `\"o`, 'ø': `\o`,
+	'Ò': "\\`O", 'Ó': `\'O`, 'Ô': `\^O`, 'Õ': `\~O`, 'Ö': `\"O`, 'Ø': `\O`,
+	'ù': "\\`u", 'ú': `\'u`, 'û': `\^u`, 'ü': `\"u`,
+	'Ù': "\\`U", 'Ú': `\'U`, 'Û': `\^U`, 'Ü': `\"U`,
+	'ý': `\'y`, 'ÿ': `\"y`, 'Ý': `\'Y`,
+	'ß': `\ss`, 'š': `\v{s}`, 'Š': `\v{S}`, 'ž': `\v{z}`, 'Ž': `\v{Z}`,
+	'č': `\v{c}`, 'Č': `\v{C}`, 'ł': `\l`, 'Ł': `\L`, 'ő': `\H{o}`, 'ű': `\H{u}`,
+	'‘': "`", '’': "'", '“': "``", '”': "''", '–': "--", '—': "---",
+}
+
+// asciiFold maps lowercase accented letters to ASCII for citation keys.
+var asciiFold = map[rune]string{
+	'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'ä': "a", 'å': "a", 'æ': "ae",
+	'ç': "c", 'č': "c", 'è': "e", 'é': "e", 'ê': "e", 'ë': "e",
+	'ì': "i", 'í': "i", 'î': "i", 'ï': "i", 'ñ': "n", 'ł': "l",
+	'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ö': "o", 'ø': "o", 'ő': "o",
+	'ù': "u", 'ú': "u", 'û': "u", 'ü': "u", 'ű': "u",
+	'ý': "y", 'ÿ': "y", 'ß': "ss", 'š': "s", 'ž': "z",
+}
@@ -0,0 +1,166 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// fixtureBook parses the Finnish edition of "An Instant in the Wind".
+func fixtureBook(t *testing.T) Book {
+	t.Helper()
+	html, err := os.ReadFile(filepath.Join("testdata", "book_18690730_tuokio_tuulessa.html"))
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+	b, err := ParseBookDetailsFromHTML(string(html), "18690730")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	return b
+}
+
+func TestCitationKeys(t *testing.T) {
+	books := []Book{
+		{ID: "18690730", Title: "Tuokio tuulessa", Author: "André Brink", Year: "1978"},
+		{ID: "1", Title: "The Äärimmäinen kirja", Author: "Väinö Linna", Year: "1954"},
+		{ID: "2", Title: "Dune", Author: "Frank Herbert", Year: "1965"},
+		{ID: "3", Title: "Dune", Author: "Frank Herbert", Year: "1965"},
+		{ID: "4"},
+	}
+	want := []string{"brink1978tuokio-18690730", "linna1954aarimmainen-1", "herbert1965dune-2", "herbert1965dune-3", "goodreads4"}
+	if got := CitationKeys(books); !reflect.DeepEqual(got, want) {
+		t.Errorf("CitationKeys = %q, want %q", got, want)
+	}
+	// A key depends only on its own book.
+	if got := CitationKeys(books[2:3]); got[0] != want[2] {
+		t.Errorf("key changed with the other books: %q", got[0])
+	}
+}
+
+func TestWriteCitations_BibTeX(t *testing.T) {
+	var buf bytes.Buffer
+	b := fixtureBook(t)
+	b.Title = "Tuokio tuulessa & muita: 100% å"
+	if err := WriteCitations(&buf, "bibtex", []Book{b}); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, line := range []string{
+		"@book{brink1978tuokio-18690730,\n",
+		`  author = {Brink, Andr{\'e}},` + "\n",
+		`  title = {Tuokio tuulessa \& muita: 100\% {\aa}},` + "\n",
+		`  origtitle = {{'}n Oomblik in die wind},` + "\n",
+		"  publisher = {WSOY},\n",
+		"  year = {1978},\n",
+		"  isbn = {9789510085660},\n",
+		"  pagetotal = {350},\n",
+		"  language = {Finnish},\n",
+	} {
+		if !strings.Contains(out, line) {
+			t.Errorf("missing %q in\n%s", line, out)
+		}
+	}
+	if !strings.HasSuffix(out, "}\n") {
+		t.Errorf("entry not closed:\n%s", out)
+	}
+}
+
+func TestWriteCitations_CSLJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteCitations(&buf, "csl-json", []Book{fixtureBook(t)}); err != nil {
+		t.Fatal(err)
+	}
+	var items []map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &items); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if len(items) != 1 {
+		t.Fatalf("got %d items", len(items))
+	}
+	it := items[0]
+	if it["id"] != "brink1978tuokio-18690730" || it["type"] != "book" || it["title"] != "Tuokio tuulessa" ||
+		it["ISBN"] != "9789510085660" || it["original-title"] != "’n Oomblik in die wind" {
+		t.Errorf("item = %v", it)
+	}
+	if want := []any{map[string]any{"family": "Brink", "given": "André"}}; !reflect.DeepEqual(it["author"], want) {
+		t.Errorf("author = %v", it["author"])
+	}
+	if want := map[string]any{"date-parts": []any{[]any{1978.0}}}; !reflect.DeepEqual(it["issued"], want) {
+		t.Errorf("issued = %v", it["issued"])
+	}
+	if it["number-of-pages"] != 350.0 {
+		t.Errorf("number-of-pages = %v", it["number-of-pages"])
+	}
+}
+
+func TestWriteCitations_RIS(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteCitations(&buf, "ris", []Book{fixtureBook(t), {ID: "2", Title: "Dune", Author: "Frank Herbert"}}); err != nil {
+		t.Fatal(err)
+	}
+	records := strings.Split(strings.TrimSuffix(buf.String(), "ER  - \r\n"), "ER  - \r\n")
+	if len(records) != 2 {
+		t.Fatalf("got %d records:\n%s", len(records), buf.String())
+	}
+	want := "TY  - BOOK\r\nID  - brink1978tuokio-18690730\r\nAU  - Brink, André\r\nTI  - Tuokio tuulessa\r\n" +
+		"OP  - ’n Oomblik in die wind\r\nPY  - 1978\r\nPB  - WSOY\r\nSN  - 9789510085660\r\nSP  - 350\r\nLA  - Finnish\r\n"
+	if !strings.HasPrefix(records[0], want) {
+		t.Errorf("first record =\n%q\nwant prefix\n%q", records[0], want)
+	}
+	if !strings.HasPrefix(records[1], "TY  - BOOK\r\n") {
+		t.Errorf("second record = %q", records[1])
+	}
+}
+
+func TestWriteCitations_MARCXML(t *testing.T) {
+	var buf bytes.Buffer
+	b := fixtureBook(t)
+	b.Title = "Tuokio <tuulessa> & muuta"
+	if err := WriteCitations(&buf, "marcxml", []Book{b}); err != nil {
+		t.Fatal(err)
+	}
+	var coll marcCollection
+	if err := xml.Unmarshal(buf.Bytes(), &coll); err != nil {
+		t.Fatalf("invalid XML: %v\n%s", err, buf.String())
+	}
+	if len(coll.Records) != 1 {
+		t.Fatalf("got %d records", len(coll.Records))
+	}
+	fields := map[string]string{}
+	for _, f := range coll.Records[0].DataFields {
+		var vals []string
+		for _, s := range f.Subfields {
+			vals = append(vals, s.Code+"="+s.Value)
+		}
+		fields[f.Tag] += strings.Join(vals, " ") + ";"
+	}
+	want := map[string]string{
+		"020": "a=9789510085660;a=9510085669;",
+		"100": "a=Brink, André;",
+		"240": "a=’n Oomblik in die wind;",
+		"245": "a=Tuokio <tuulessa> & muuta;",
+		"264": "b=WSOY c=1978;",
+		"300": "a=350 pages;",
+		"546": "a=In Finnish.;",
+	}
+	for tag, v := range want {
+		if fields[tag] != v {
+			t.Errorf("%s = %q, want %q", tag, fields[tag], v)
+		}
+	}
+	if !strings.Contains(buf.String(), `xmlns="http://www.loc.gov/MARC21/slim"`) {
+		t.Error("missing MARC21 slim namespace")
+	}
+}
+
+func TestWriteCitations_UnknownFormat(t *testing.T) {
+	if err := WriteCitations(&bytes.Buffer{}, "endnote", nil); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"net/http/cookiejar"
 	"net/url"
 	"os"
+	"strconv"
 
 	"github.com/go-rod/rod/lib/proto"
 )
@@ -91,7 +92,11 @@ func (c *Client) loadRodSession() {
 
 // Search calls the Goodreads autocomplete endpoint and returns matching books.
 func (c *Client) Search(query string) ([]Book, error) {
-	reqURL := fmt.Sprintf("%s/book/auto_complete?format=json&q=%s", BaseURL, url.QueryEscape(query))
+	return c.autocomplete(BaseURL, query)
+}
+
+func (c *Client) autocomplete(base, query string) ([]Book, error) {
+	reqURL := fmt.Sprintf("%s/book/auto_complete?format=json&q=%s", base, url.QueryEscape(query))
 
 	req, err := http.NewRequest("GET", reqURL, nil)
 	if err != nil {
@@ -109,7 +114,7 @@ func (c *Client) Search(query string) ([]Book, error) {
 	c.Log.Record("http_search", map[string]any{"url": reqURL, "status": resp.StatusCode}, nil)
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("search returned status %d", resp.StatusCode)
+		return nil, httpStatusError(resp.StatusCode, "search returned status %d", resp.StatusCode)
 	}
 
 	var results []autoCompleteResult
@@ -119,12 +124,16 @@ func (c *Client) Search(query string) ([]Book, error) {
 
 	var books []Book
 	for _, r := range results {
-		books = append(books, Book{
+		bk := Book{
 			ID:       r.BookID,
 			Title:    r.Title,
 			Author:   r.Author.Name,
 			ImageURL: r.ImageURL,
-		})
+		}
+		if r.Author.ID != 0 {
+			bk.AuthorID = strconv.Itoa(r.Author.ID)
+		}
+		books = append(books, bk)
 	}
 	return books, nil
 }
@@ -162,7 +171,7 @@ func (c *Client) FetchBookDetails(bookID string) (Book, error) {
 	c.Log.Record("http_book_details", map[string]any{"url": reqURL, "status": resp.StatusCode}, nil)
 
 	if resp.StatusCode != http.StatusOK {
-		return Book{}, fmt.Errorf("book page returned status %d", resp.StatusCode)
+		return Book{}, httpStatusError(resp.StatusCode, "book page returned status %d", resp.StatusCode)
 	}
 
 	body, err := io.ReadAll(resp.Body)
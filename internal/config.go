@@ -11,6 +11,9 @@ import (
 type Config struct {
 	Email    string `yaml:"email"`
 	Password string `yaml:"password"`
+	// AutoLogin logs in again with these credentials when a command
+	// finds the saved session expired, as --auto-login does.
+	AutoLogin bool `yaml:"auto_login"`
 }
 
 func ConfigPath() string {
@@ -24,14 +27,23 @@ func SessionPath() string {
 }
 
 func LoadConfig() (*Config, error) {
-	// Environment variables take precedence
+	data, err := os.ReadFile(ConfigPath())
+
+	// Environment variables take precedence; the file may still set
+	// other options.
 	email := os.Getenv("GOODREADS_EMAIL")
 	password := os.Getenv("GOODREADS_PASSWORD")
 	if email != "" && password != "" {
-		return &Config{Email: email, Password: password}, nil
+		cfg := Config{Email: email, Password: password}
+		if err == nil {
+			var file Config
+			if yaml.Unmarshal(data, &file) == nil {
+				cfg.AutoLogin = file.AutoLogin
+			}
+		}
+		return &cfg, nil
 	}
 
-	data, err := os.ReadFile(ConfigPath())
 	if err != nil {
 		return nil, fmt.Errorf("config file not found at %s: %w\nCreate it with your Goodreads email and password:\n  email: you@example.com\n  password: yourpassword\nOr set GOODREADS_EMAIL and GOODREADS_PASSWORD environment variables.", ConfigPath(), err)
 	}
@@ -42,7 +54,7 @@ func LoadConfig() (*Config, error) {
 	}
 
 	if cfg.Email == "" || cfg.Password == "" {
-		return nil, fmt.Errorf("config file must contain 'email' and 'password' fields")
+		return nil, Errorf(CodeInvalidInput, "config file must contain 'email' and 'password' fields")
 	}
 
 	return &cfg, nil
@@ -151,7 +151,7 @@ func TestLoadConfigEnvOverridesFile(t *testing.T) {
 	defer os.Setenv("HOME", origHome)
 
 	// Write a config file
-	os.WriteFile(filepath.Join(tmpDir, ".goodreads-cli.yaml"), []byte("email: file@example.com\npassword: filepass"), 0600)
+	os.WriteFile(filepath.Join(tmpDir, ".goodreads-cli.yaml"), []byte("email: file@example.com\npassword: filepass\nauto_login: true"), 0600)
 
 	// Set env vars — should take precedence
 	origEmail := os.Getenv("GOODREADS_EMAIL")
@@ -178,6 +178,9 @@ func TestLoadConfigEnvOverridesFile(t *testing.T) {
 	if cfg.Email != "env@example.com" {
 		t.Errorf("Email = %q, want env value", cfg.Email)
 	}
+	if !cfg.AutoLogin {
+		t.Error("AutoLogin from the file was dropped")
+	}
 }
 
 func TestLogout(t *testing.T) {
@@ -0,0 +1,294 @@
+package internal
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// The browser daemon keeps one logged-in Chromium running so commands
+// don't each launch a browser, load the home page, restore cookies and
+// clear the AWS WAF challenge again. Commands talk to it over a unix
+// socket with one JSON request and one JSON response per connection:
+//
+//	→ {"op":"connect"}
+//	← {"ok":true,"status":{"pid":1234,"control_url":"ws://127.0.0.1:…",…}}
+//
+// "connect" returns the DevTools URL for the command to attach to and
+// open its own tab, "status" also reports whether the session is logged
+// in, and "stop" shuts the daemon and its browser down.
+const (
+	DaemonOpStatus  = "status"
+	DaemonOpConnect = "connect"
+	DaemonOpStop    = "stop"
+)
+
+// DaemonRequest is one request to the daemon.
+type DaemonRequest struct {
+	Op string `json:"op"`
+}
+
+// DaemonResponse is the daemon's reply; Error is set when OK is false.
+type DaemonResponse struct {
+	OK     bool          `json:"ok"`
+	Error  string        `json:"error,omitempty"`
+	Status *DaemonStatus `json:"status,omitempty"`
+}
+
+// DaemonStatus describes a running daemon.
+type DaemonStatus struct {
+	PID         int       `json:"pid"`
+	ControlURL  string    `json:"control_url"`
+	Headless    bool      `json:"headless"`
+	StartedAt   time.Time `json:"started_at"`
+	LoggedIn    bool      `json:"logged_in"`   // only checked by "status"
+	Connections int       `json:"connections"` // commands served so far
+}
+
+// DaemonSocketPath is the daemon's unix socket, next to the session file.
+func DaemonSocketPath() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".goodreads-cli-daemon.sock")
+}
+
+// DaemonLogPath is where a daemon started in the background writes its
+// output.
+func DaemonLogPath() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".goodreads-cli-daemon.log")
+}
+
+// ErrDaemonNotRunning is returned by DaemonCall when nothing is listening
+// on the socket.
+var ErrDaemonNotRunning = errors.New("browser daemon is not running")
+
+// DaemonCall sends op to the daemon listening on socketPath.
+func DaemonCall(socketPath, op string) (DaemonResponse, error) {
+	conn, err := net.DialTimeout("unix", socketPath, 2*time.Second)
+	if err != nil {
+		return DaemonResponse{}, ErrDaemonNotRunning
+	}
+	defer conn.Close()
+	// "status" reloads the daemon's page to check the session.
+	conn.SetDeadline(time.Now().Add(30 * time.Second))
+
+	if err := json.NewEncoder(conn).Encode(DaemonRequest{Op: op}); err != nil {
+		return DaemonResponse{}, fmt.Errorf("talking to the browser daemon: %w", err)
+	}
+	var resp DaemonResponse
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		return DaemonResponse{}, fmt.Errorf("reading the browser daemon's reply: %w", err)
+	}
+	if !resp.OK {
+		return resp, fmt.Errorf("browser daemon: %s", resp.Error)
+	}
+	return resp, nil
+}
+
+// connectDaemon returns a new tab in the daemon's browser, or nil if no
+// daemon is running, GOODREADS_NO_DAEMON=1 is set, or a visible browser
+// was asked for and the daemon's is headless. Failing to attach to a
+// running daemon falls back to launching a browser too: the daemon is an
+// optimisation, never a requirement.
+func connectDaemon(headless bool) (*Browser, error) {
+	if os.Getenv("GOODREADS_NO_DAEMON") == "1" {
+		return nil, nil
+	}
+	resp, err := DaemonCall(DaemonSocketPath(), DaemonOpConnect)
+	if err != nil || resp.Status == nil || (!headless && resp.Status.Headless) {
+		return nil, nil
+	}
+
+	browser := rod.New().ControlURL(resp.Status.ControlURL)
+	if err := browser.Connect(); err != nil {
+		return nil, nil
+	}
+	page, err := browser.Page(proto.TargetCreateTarget{URL: "https://www.goodreads.com"})
+	if err != nil {
+		return nil, nil
+	}
+	page.MustWaitStable()
+
+	b := &Browser{Rod: browser, Page: page, Log: NewInteractionLog(), shared: true}
+	b.Log.Record("browser_connect_daemon", map[string]any{"pid": resp.Status.PID}, nil)
+	return b, nil
+}
+
+// daemonServer answers requests on the daemon socket. status is called
+// for "status" and "connect" (with check set for "status" only); stop is
+// closed by "stop".
+type daemonServer struct {
+	status func(check bool) DaemonStatus
+	stop   chan struct{}
+
+	mu       sync.Mutex // serialises status, which may use the page
+	served   int
+	stopOnce sync.Once
+}
+
+func newDaemonServer(status func(check bool) DaemonStatus) *daemonServer {
+	return &daemonServer{status: status, stop: make(chan struct{})}
+}
+
+// serve accepts connections until ln is closed.
+func (s *daemonServer) serve(ln net.Listener) {
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *daemonServer) handle(conn net.Conn) {
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(30 * time.Second))
+
+	var req DaemonRequest
+	var resp DaemonResponse
+	line, err := bufio.NewReader(conn).ReadBytes('\n')
+	if err == nil {
+		err = json.Unmarshal(line, &req)
+	}
+	switch {
+	case err != nil:
+		resp.Error = "bad request: " + err.Error()
+	case req.Op == DaemonOpStatus || req.Op == DaemonOpConnect:
+		s.mu.Lock()
+		if req.Op == DaemonOpConnect {
+			s.served++
+		}
+		st := s.status(req.Op == DaemonOpStatus)
+		st.Connections = s.served
+		s.mu.Unlock()
+		resp = DaemonResponse{OK: true, Status: &st}
+	case req.Op == DaemonOpStop:
+		resp.OK = true
+		s.stopOnce.Do(func() { close(s.stop) })
+	default:
+		resp.Error = fmt.Sprintf("unknown op %q", req.Op)
+	}
+	json.NewEncoder(conn).Encode(resp)
+}
+
+// RunDaemon runs the browser daemon in the foreground until it is sent
+// "stop" or interrupted: it launches Chromium (or connects to opts.URL),
+// restores the saved session and serves requests on socketPath.
+func RunDaemon(opts BrowserOptions, socketPath string) error {
+	if _, err := DaemonCall(socketPath, DaemonOpStatus); err == nil {
+		return fmt.Errorf("a browser daemon is already running on %s", socketPath)
+	}
+	// Nobody answered, so any socket file left is from a daemon that died.
+	os.Remove(socketPath)
+
+	u, l, err := devToolsURL(opts)
+	if err != nil {
+		return err
+	}
+	if l != nil {
+		defer l.Kill()
+	}
+
+	browser := rod.New().ControlURL(u)
+	if err := browser.Connect(); err != nil {
+		return fmt.Errorf("failed to connect to browser: %w", err)
+	}
+	page, err := browser.Page(proto.TargetCreateTarget{URL: BaseURL})
+	if err != nil {
+		return fmt.Errorf("failed to open page: %w", err)
+	}
+	page.MustWaitStable()
+	b := &Browser{Rod: browser, Page: page, Log: NewInteractionLog()}
+	if err := b.LoadCookies(); err == nil {
+		page.MustNavigate(BaseURL)
+		page.MustWaitStable()
+	}
+
+	ln, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return fmt.Errorf("listening on %s: %w", socketPath, err)
+	}
+	defer os.Remove(socketPath)
+	os.Chmod(socketPath, 0o600)
+
+	base := DaemonStatus{PID: os.Getpid(), ControlURL: u, Headless: opts.Headless, StartedAt: time.Now().UTC()}
+	srv := newDaemonServer(func(check bool) DaemonStatus {
+		st := base
+		if check {
+			// Reload so a login or logout in another tab shows, and
+			// the session is kept warm.
+			page.Timeout(15 * time.Second).Navigate(BaseURL)
+			page.Timeout(15 * time.Second).WaitStable(time.Second)
+			st.LoggedIn = b.IsLoggedIn()
+		}
+		return st
+	})
+	go srv.serve(ln)
+	fmt.Printf("Browser daemon %d listening on %s (logged in: %v)\n", base.PID, socketPath, b.IsLoggedIn())
+
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+	select {
+	case <-srv.stop:
+	case <-sig:
+	}
+	ln.Close()
+	if l != nil {
+		browser.Close()
+	} else {
+		page.Close()
+	}
+	fmt.Println("Browser daemon stopped.")
+	return nil
+}
+
+// SpawnDaemon starts args (this executable's daemon command) detached
+// from the terminal, with its output in DaemonLogPath, and waits until
+// it answers on socketPath.
+func SpawnDaemon(socketPath string, args []string) (DaemonStatus, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return DaemonStatus{}, err
+	}
+	logFile, err := os.OpenFile(DaemonLogPath(), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return DaemonStatus{}, err
+	}
+	defer logFile.Close()
+
+	c := exec.Command(exe, args...)
+	c.Stdout, c.Stderr = logFile, logFile
+	c.SysProcAttr = daemonSysProcAttr()
+	if err := c.Start(); err != nil {
+		return DaemonStatus{}, fmt.Errorf("starting the browser daemon: %w", err)
+	}
+	exited := make(chan error, 1)
+	go func() { exited <- c.Wait() }()
+
+	deadline := time.Now().Add(90 * time.Second)
+	for time.Now().Before(deadline) {
+		select {
+		case err := <-exited:
+			return DaemonStatus{}, fmt.Errorf("browser daemon exited during startup (%v) — see %s", err, DaemonLogPath())
+		case <-time.After(500 * time.Millisecond):
+		}
+		if resp, err := DaemonCall(socketPath, DaemonOpStatus); err == nil && resp.Status != nil {
+			return *resp.Status, nil
+		}
+	}
+	c.Process.Kill()
+	return DaemonStatus{}, fmt.Errorf("browser daemon did not start within 90s — see %s", DaemonLogPath())
+}
@@ -0,0 +1,82 @@
+package internal
+
+import (
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// daemonSocket returns a short socket path: unix socket paths are limited
+// to ~104 bytes, which t.TempDir can exceed on macOS.
+func daemonSocket(t *testing.T) string {
+	t.Helper()
+	dir, err := os.MkdirTemp("", "grd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return filepath.Join(dir, "d.sock")
+}
+
+func TestDaemonProtocol(t *testing.T) {
+	socket := daemonSocket(t)
+	ln, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Skipf("unix sockets unavailable: %v", err)
+	}
+	defer ln.Close()
+
+	checks := 0
+	srv := newDaemonServer(func(check bool) DaemonStatus {
+		if check {
+			checks++
+		}
+		return DaemonStatus{PID: 42, ControlURL: "ws://127.0.0.1:9222/devtools/browser/x", LoggedIn: check}
+	})
+	go srv.serve(ln)
+
+	for range 2 {
+		resp, err := DaemonCall(socket, DaemonOpConnect)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.Status.ControlURL != "ws://127.0.0.1:9222/devtools/browser/x" || resp.Status.LoggedIn {
+			t.Errorf("connect = %+v", resp.Status)
+		}
+	}
+
+	resp, err := DaemonCall(socket, DaemonOpStatus)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st := resp.Status; st.PID != 42 || !st.LoggedIn || st.Connections != 2 || checks != 1 {
+		t.Errorf("status = %+v after %d checks", st, checks)
+	}
+
+	if _, err := DaemonCall(socket, "reload"); err == nil {
+		t.Error("expected an error for an unknown op")
+	}
+
+	if _, err := DaemonCall(socket, DaemonOpStop); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-srv.stop:
+	case <-time.After(time.Second):
+		t.Error("stop did not signal the daemon")
+	}
+	// A second stop must not panic on the closed channel.
+	if _, err := DaemonCall(socket, DaemonOpStop); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDaemonCall_NotRunning(t *testing.T) {
+	_, err := DaemonCall(daemonSocket(t), DaemonOpStatus)
+	if !errors.Is(err, ErrDaemonNotRunning) {
+		t.Errorf("err = %v, want ErrDaemonNotRunning", err)
+	}
+}
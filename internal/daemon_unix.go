@@ -0,0 +1,11 @@
+//go:build !windows
+
+package internal
+
+import "syscall"
+
+// daemonSysProcAttr starts the daemon in its own session, so closing the
+// terminal that ran `goodreads daemon start` doesn't take it down.
+func daemonSysProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{Setsid: true}
+}
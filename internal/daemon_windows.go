@@ -0,0 +1,18 @@
+//go:build windows
+
+package internal
+
+import "syscall"
+
+// detachedProcess is DETACHED_PROCESS, which package syscall doesn't name.
+const detachedProcess = 0x00000008
+
+// daemonSysProcAttr starts the daemon without a console and outside the
+// console's process group, so closing the window or Ctrl+C in it doesn't
+// take the daemon down.
+func daemonSysProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{
+		CreationFlags: detachedProcess | syscall.CREATE_NEW_PROCESS_GROUP,
+		HideWindow:    true,
+	}
+}
@@ -22,7 +22,7 @@ func PostReply(b *Browser, topicID string, message string, bookID string, author
 	textarea, err := b.Page.Timeout(10 * time.Second).Element(`#comment_body_usertext`)
 	if err != nil {
 		saveDebugArtifacts(b)
-		return fmt.Errorf("could not find comment textarea: %w", err)
+		return Errorf(CodeSelectorDrift, "could not find comment textarea: %w", err)
 	}
 
 	textarea.MustClick()
@@ -32,7 +32,7 @@ func PostReply(b *Browser, topicID string, message string, bookID string, author
 	postBtn, err := b.Page.Timeout(5 * time.Second).Element(`input[type="submit"][value="Post"]`)
 	if err != nil {
 		saveDebugArtifacts(b)
-		return fmt.Errorf("could not find Post button: %w", err)
+		return Errorf(CodeSelectorDrift, "could not find Post button: %w", err)
 	}
 	postBtn.MustClick()
 
@@ -57,7 +57,7 @@ func PostNewTopic(b *Browser, topicURL string, subject string, message string, b
 	subjectField, err := b.Page.Timeout(10 * time.Second).Element(`input[name="topic[subject]"], input[name="topic[title]"], #topic_subject, #topic_title`)
 	if err != nil {
 		saveDebugArtifacts(b)
-		return fmt.Errorf("could not find topic subject field: %w", err)
+		return Errorf(CodeSelectorDrift, "could not find topic subject field: %w", err)
 	}
 	subjectField.MustClick()
 	subjectField.MustInput(subject)
@@ -66,7 +66,7 @@ func PostNewTopic(b *Browser, topicURL string, subject string, message string, b
 	textarea, err := b.Page.Timeout(5 * time.Second).Element(`#comment_body_usertext`)
 	if err != nil {
 		saveDebugArtifacts(b)
-		return fmt.Errorf("could not find comment textarea: %w", err)
+		return Errorf(CodeSelectorDrift, "could not find comment textarea: %w", err)
 	}
 	textarea.MustClick()
 	textarea.MustInput(message)
@@ -75,7 +75,7 @@ func PostNewTopic(b *Browser, topicURL string, subject string, message string, b
 	postBtn, err := b.Page.Timeout(5 * time.Second).Element(`input[type="submit"][value="Post"]`)
 	if err != nil {
 		saveDebugArtifacts(b)
-		return fmt.Errorf("could not find Post button: %w", err)
+		return Errorf(CodeSelectorDrift, "could not find Post button: %w", err)
 	}
 	postBtn.MustClick()
 
@@ -133,20 +133,26 @@ func resolveBookName(b *Browser, bookID string) (string, error) {
 	titleEl, err := b.Page.Timeout(10 * time.Second).Element(`h1[data-testid="bookTitle"], h1.Text__title1`)
 	if err != nil {
 		saveDebugArtifacts(b)
-		return "", fmt.Errorf("could not find book title: %w", err)
+		return "", Errorf(CodeSelectorDrift, "could not find book title: %w", err)
 	}
 	return titleEl.MustText(), nil
 }
 
-// resolveAuthorName navigates to an author page and extracts the name.
+// resolveAuthorName navigates to an author page and extracts the name,
+// through the author page parser or, should that fail, the page's
+// heading.
 func resolveAuthorName(b *Browser, authorID string) (string, error) {
+	if a, err := b.FetchAuthor(authorID); err == nil && a.Name != "" {
+		return a.Name, nil
+	}
+
 	b.Page.MustNavigate(fmt.Sprintf("https://www.goodreads.com/author/show/%s", authorID))
 	b.Page.MustWaitStable()
 
 	nameEl, err := b.Page.Timeout(10 * time.Second).Element(`h1.authorName span[itemprop="name"], h1.authorName, .authorName span`)
 	if err != nil {
 		saveDebugArtifacts(b)
-		return "", fmt.Errorf("could not find author name: %w", err)
+		return "", Errorf(CodeSelectorDrift, "could not find author name: %w", err)
 	}
 	return nameEl.MustText(), nil
 }
@@ -156,7 +162,7 @@ func openMentionBox(b *Browser) error {
 	addLink, err := b.Page.Timeout(5*time.Second).ElementR(`a`, "add book/author")
 	if err != nil {
 		saveDebugArtifacts(b)
-		return fmt.Errorf("could not find 'add book/author' link: %w", err)
+		return Errorf(CodeSelectorDrift, "could not find 'add book/author' link: %w", err)
 	}
 	if err := addLink.Click(proto.InputMouseButtonLeft, 1); err != nil {
 		return fmt.Errorf("clicking 'add book/author': %w", err)
@@ -176,7 +182,7 @@ func addBookMention(b *Browser, bookName string, bookID string) error {
 	searchInput, err := b.Page.Timeout(5 * time.Second).Element(`#search_query`)
 	if err != nil {
 		saveDebugArtifacts(b)
-		return fmt.Errorf("could not find book search input: %w", err)
+		return Errorf(CodeSelectorDrift, "could not find book search input: %w", err)
 	}
 	if err := searchInput.Click(proto.InputMouseButtonLeft, 1); err != nil {
 		return fmt.Errorf("clicking search input: %w", err)
@@ -189,7 +195,7 @@ func addBookMention(b *Browser, bookName string, bookID string) error {
 	searchBtn, err := b.Page.Timeout(5 * time.Second).Element(`#add_mention_box_form input[type="submit"]`)
 	if err != nil {
 		saveDebugArtifacts(b)
-		return fmt.Errorf("could not find search button: %w", err)
+		return Errorf(CodeSelectorDrift, "could not find search button: %w", err)
 	}
 	if err := searchBtn.Click(proto.InputMouseButtonLeft, 1); err != nil {
 		return fmt.Errorf("clicking search button: %w", err)
@@ -202,7 +208,7 @@ func addBookMention(b *Browser, bookName string, bookID string) error {
 	addBtn, err := b.Page.Timeout(10 * time.Second).Element(addBtnSelector)
 	if err != nil {
 		saveDebugArtifacts(b)
-		return fmt.Errorf("could not find Add button for book %s: %w", bookID, err)
+		return Errorf(CodeSelectorDrift, "could not find Add button for book %s: %w", bookID, err)
 	}
 	_, err = addBtn.Eval(`() => this.click()`, nil)
 	if err != nil {
@@ -225,7 +231,7 @@ func addAuthorMention(b *Browser, authorName string, authorID string) error {
 	authorTab, err := b.Page.Timeout(5 * time.Second).Element(`#authorLink`)
 	if err != nil {
 		saveDebugArtifacts(b)
-		return fmt.Errorf("could not find Author tab: %w", err)
+		return Errorf(CodeSelectorDrift, "could not find Author tab: %w", err)
 	}
 	if err := authorTab.Click(proto.InputMouseButtonLeft, 1); err != nil {
 		return fmt.Errorf("clicking Author tab: %w", err)
@@ -236,7 +242,7 @@ func addAuthorMention(b *Browser, authorName string, authorID string) error {
 	authorInput, err := b.Page.Timeout(5 * time.Second).Element(`#quote_author_name`)
 	if err != nil {
 		saveDebugArtifacts(b)
-		return fmt.Errorf("could not find author search input: %w", err)
+		return Errorf(CodeSelectorDrift, "could not find author search input: %w", err)
 	}
 	if err := authorInput.Click(proto.InputMouseButtonLeft, 1); err != nil {
 		return fmt.Errorf("clicking author input: %w", err)
@@ -248,7 +254,7 @@ func addAuthorMention(b *Browser, authorName string, authorID string) error {
 	searchBtn, err := b.Page.Timeout(5 * time.Second).Element(`#author_mention_form input[type="submit"]`)
 	if err != nil {
 		saveDebugArtifacts(b)
-		return fmt.Errorf("could not find author search button: %w", err)
+		return Errorf(CodeSelectorDrift, "could not find author search button: %w", err)
 	}
 	if err := searchBtn.Click(proto.InputMouseButtonLeft, 1); err != nil {
 		return fmt.Errorf("clicking author search: %w", err)
@@ -261,7 +267,7 @@ func addAuthorMention(b *Browser, authorName string, authorID string) error {
 	addBtn, err := b.Page.Timeout(10 * time.Second).Element(addBtnSelector)
 	if err != nil {
 		saveDebugArtifacts(b)
-		return fmt.Errorf("could not find Add button for author %s: %w", authorID, err)
+		return Errorf(CodeSelectorDrift, "could not find Add button for author %s: %w", authorID, err)
 	}
 	_, err = addBtn.Eval(`() => this.click()`, nil)
 	if err != nil {
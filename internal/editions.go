@@ -0,0 +1,284 @@
+package internal
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EditionOptions narrows an editions listing. Both filters are passed to
+// the /work/editions page, which does the filtering itself; the zero
+// value lists every edition.
+type EditionOptions struct {
+	Language string // language code as Goodreads uses it ("fin", "eng", …)
+	Format   string // "Paperback", "Hardcover", "ebook", "Kindle Edition", …
+}
+
+// editionsPerPage is the page size requested from /work/editions, the
+// largest it accepts.
+const editionsPerPage = 100
+
+// ParseEditionsHTML extracts the editions from a /work/editions/<work_id>
+// page. Each edition is a `div.elementList` with the title link, plain
+// rows such as "Published 1978 by WSOY" and "Hardcover, 350 pages", and a
+// "moreDetails" block of dataTitle/dataValue pairs for the ISBNs, ASIN,
+// edition language and average rating.
+func ParseEditionsHTML(html string) []Book {
+	blocks := _editionBlockRE.Split(html, -1)
+	books := []Book{}
+	for _, block := range blocks[1:] {
+		m := _editionTitleRE.FindStringSubmatch(block)
+		if m == nil {
+			continue
+		}
+		bk := Book{
+			ID:    m[1],
+			Title: cellText(m[2]),
+			URL:   fmt.Sprintf("%s/book/show/%s", BaseURL, m[1]),
+		}
+		if a := _searchAuthorRE.FindStringSubmatch(block); a != nil {
+			bk.Author = decodeHTMLEntities(strings.TrimSpace(a[1]))
+		}
+		if c := _editionCoverRE.FindStringSubmatch(block); c != nil {
+			bk.ImageURL = c[1]
+		}
+		for _, row := range _editionPlainRowRE.FindAllStringSubmatch(block, -1) {
+			applyEditionRow(&bk, cellText(row[1]))
+		}
+		for _, d := range _editionDataRE.FindAllStringSubmatch(block, -1) {
+			applyEditionData(&bk, strings.TrimSpace(d[1]), cellText(d[2]))
+		}
+		books = append(books, bk)
+	}
+	return books
+}
+
+// applyEditionRow reads one untitled row of an edition: the publication
+// line or the format and page count.
+func applyEditionRow(bk *Book, row string) {
+	if strings.HasPrefix(row, "Published") || strings.HasPrefix(row, "Expected publication") {
+		when, publisher, _ := strings.Cut(row, " by ")
+		if m := _editionYearRE.FindString(when); m != "" {
+			bk.Year = m
+		}
+		bk.Publisher = strings.TrimSpace(publisher)
+		return
+	}
+	if m := _editionPagesRE.FindStringSubmatch(row); m != nil {
+		bk.Pages, _ = strconv.Atoi(m[1])
+	}
+	if format, _, _ := strings.Cut(row, ","); !_editionPagesRE.MatchString(format) {
+		bk.Format = strings.TrimSpace(format)
+	}
+}
+
+// applyEditionData reads one dataTitle/dataValue pair.
+func applyEditionData(bk *Book, title, value string) {
+	switch strings.TrimSuffix(title, ":") {
+	case "ISBN":
+		// "9510085669 (ISBN13: 9789510085660)", or just the ISBN-13 for
+		// editions without an ISBN-10.
+		isbn, rest, _ := strings.Cut(value, " ")
+		if len(isbn) == 13 {
+			bk.ISBN13 = isbn
+		} else {
+			bk.ISBN = isbn
+		}
+		if m := _editionISBN13RE.FindStringSubmatch(rest); m != nil {
+			bk.ISBN13 = m[1]
+		}
+	case "ASIN":
+		bk.ASIN = value
+	case "Edition language":
+		bk.Language = value
+	case "Average rating":
+		if m := _editionRatingRE.FindStringSubmatch(value); m != nil {
+			bk.Rating = m[1]
+			bk.RatingsCount, _ = strconv.Atoi(strings.ReplaceAll(m[2], ",", ""))
+		}
+	}
+}
+
+// editionsURL builds the URL of one page of a work's editions.
+func editionsURL(workID string, opts EditionOptions, page int) string {
+	v := url.Values{}
+	v.Set("expanded", "true")
+	v.Set("per_page", strconv.Itoa(editionsPerPage))
+	v.Set("page", strconv.Itoa(page))
+	if opts.Format != "" {
+		v.Set("filter_by_format", opts.Format)
+	}
+	if opts.Language != "" {
+		v.Set("filter_by_language", opts.Language)
+	}
+	return fmt.Sprintf("%s/work/editions/%s?%s", BaseURL, workID, v.Encode())
+}
+
+// editionPages fetches every page of a work's editions through fetch,
+// stopping at the first short page.
+func editionPages(fetch func(string) (string, error), workID string, opts EditionOptions) ([]Book, error) {
+	books := []Book{}
+	for page := 1; ; page++ {
+		html, err := fetch(editionsURL(workID, opts, page))
+		if err != nil {
+			return nil, fmt.Errorf("fetching editions page %d: %w", page, err)
+		}
+		editions := ParseEditionsHTML(html)
+		books = append(books, editions...)
+		if len(editions) < editionsPerPage {
+			return books, nil
+		}
+	}
+}
+
+// ListEditions returns every edition of a work, most popular first as
+// Goodreads orders them. The work ID is Book.WorkID from FetchBookDetails.
+func (b *Browser) ListEditions(workID string, opts EditionOptions) ([]Book, error) {
+	books, err := editionPages(b.FetchRenderedHTML, workID, opts)
+	b.Log.Record("list_editions", map[string]any{
+		"workID": workID, "language": opts.Language, "format": opts.Format, "count": len(books),
+	}, err)
+	return books, err
+}
+
+// SwitchEdition moves the logged-in user's shelving of a book — shelves,
+// rating, review and reading dates — to another edition of the same work,
+// through the "Switch to this edition" control on the work's editions
+// page. Afterwards the new edition must be shelved and the old one not.
+func SwitchEdition(b *Browser, shelvedID, editionID string) error {
+	if shelvedID == editionID {
+		return Errorf(CodeInvalidInput, "book %s is already that edition", shelvedID)
+	}
+	book, err := b.FetchBookDetails(shelvedID)
+	if err != nil {
+		return err
+	}
+	if book.WorkID == "" {
+		return Errorf(CodeSelectorDrift, "could not find the work of book %s", shelvedID)
+	}
+	shelved, _, err := readShelfButton(b)
+	if err != nil {
+		saveDebugArtifacts(b)
+		return err
+	}
+	if !shelved {
+		return Errorf(CodeNotFound, "book %s is not on your shelves", shelvedID)
+	}
+
+	clicked, err := clickSwitchEdition(b, book.WorkID, editionID)
+	if err != nil {
+		return err
+	}
+	if !clicked {
+		saveDebugArtifacts(b)
+		return fmt.Errorf("book %s is not an edition of %q (work %s), or has no \"Switch to this edition\" control",
+			editionID, book.Title, book.WorkID)
+	}
+	b.Page.MustWaitStable()
+
+	if err := verifyEditionSwitch(b, shelvedID, editionID); err != nil {
+		saveDebugArtifacts(b)
+		return err
+	}
+	return b.SaveCookies()
+}
+
+// clickSwitchEdition walks the work's editions pages until it finds the
+// row for editionID and clicks its switch control. clicked is false when
+// no page has such a row.
+func clickSwitchEdition(b *Browser, workID, editionID string) (clicked bool, err error) {
+	for page := 1; ; page++ {
+		html, err := b.FetchRenderedHTML(editionsURL(workID, EditionOptions{}, page))
+		if err != nil {
+			return false, fmt.Errorf("fetching editions page %d: %w", page, err)
+		}
+		res, err := b.Page.Eval(clickSwitchEditionJS, editionID)
+		clicked = err == nil && res != nil && res.Value.Bool()
+		b.Log.Record("click_switch_edition", map[string]any{
+			"workID": workID, "editionID": editionID, "page": page, "clicked": clicked,
+		}, err)
+		if err != nil {
+			saveDebugArtifacts(b)
+			return false, fmt.Errorf("clicking \"Switch to this edition\": %w", err)
+		}
+		if clicked || len(ParseEditionsHTML(html)) < editionsPerPage {
+			return clicked, nil
+		}
+	}
+}
+
+// verifyEditionSwitch polls the two book pages until the new edition shows
+// as shelved and the old one as not.
+func verifyEditionSwitch(b *Browser, oldID, newID string) error {
+	deadline := time.Now().Add(15 * time.Second)
+	var newShelved, oldShelved bool
+	for {
+		var err error
+		b.Page.MustNavigate(fmt.Sprintf("%s/book/show/%s", BaseURL, newID))
+		b.Page.MustWaitStable()
+		newShelved, _, err = readShelfButton(b)
+		if err == nil && newShelved {
+			b.Page.MustNavigate(fmt.Sprintf("%s/book/show/%s", BaseURL, oldID))
+			b.Page.MustWaitStable()
+			oldShelved, _, err = readShelfButton(b)
+		}
+		ok := err == nil && newShelved && !oldShelved
+		b.Log.Record("verify_edition_switch_poll", map[string]any{
+			"oldID": oldID, "newID": newID, "newShelved": newShelved, "oldShelved": oldShelved, "ok": ok,
+		}, err)
+		if ok {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(2 * time.Second)
+	}
+	if !newShelved {
+		return fmt.Errorf("edition switch could not be verified — book %s is not shelved", newID)
+	}
+	return fmt.Errorf("edition switch could not be verified — book %s is still shelved too", oldID)
+}
+
+// clickSwitchEditionJS finds the editions-page row linking to the given
+// book and clicks its "Switch to this edition" link or button, reporting
+// whether it found one. The current edition's row has no such control.
+const clickSwitchEditionJS = `(id) => {
+	const href = new RegExp('/book/show/' + id + '(?:[^0-9]|$)');
+	for (const row of document.querySelectorAll('.elementList')) {
+		const link = row.querySelector('a.bookTitle');
+		if (!link || !href.test(link.getAttribute('href') || '')) continue;
+		const el = [...row.querySelectorAll('a, button, input[type="submit"]')]
+			.find((e) => /switch to this edition/i.test(e.textContent || e.value || ''));
+		if (!el) return false;
+		el.click();
+		return true;
+	}
+	return false;
+}`
+
+// _editionBlockRE splits the editions page at each edition's container.
+var _editionBlockRE = regexp.MustCompile(`<div class="elementList[^"]*"`)
+
+// _editionTitleRE captures the book ID and title from the title link.
+var _editionTitleRE = regexp.MustCompile(`(?s)<a[^>]*class="bookTitle"[^>]*href="/book/show/(\d+)[^"]*"[^>]*>(.*?)</a>`)
+
+// _editionCoverRE captures the cover image URL from the image column.
+var _editionCoverRE = regexp.MustCompile(`(?s)class="leftAlignedImage".*?<img[^>]*src="([^"]+)"`)
+
+// _editionPlainRowRE captures dataRows holding only text, which leaves
+// out the title and author rows and the dataTitle/dataValue pairs.
+var _editionPlainRowRE = regexp.MustCompile(`(?s)<div class="dataRow">([^<]*)</div>`)
+
+// _editionDataRE captures a dataTitle/dataValue pair.
+var _editionDataRE = regexp.MustCompile(`(?s)<div class="dataTitle">(.*?)</div>\s*<div class="dataValue">(.*?)</div>`)
+
+var (
+	_editionYearRE   = regexp.MustCompile(`\d{4}`)
+	_editionPagesRE  = regexp.MustCompile(`(\d+) pages`)
+	_editionISBN13RE = regexp.MustCompile(`ISBN13:\s*(\d{13})`)
+	_editionRatingRE = regexp.MustCompile(`([\d.]+)\s*\(([\d,]+) ratings?\)`)
+)
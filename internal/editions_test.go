@@ -0,0 +1,144 @@
+package internal
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// editionHTML renders one edition on /work/editions/<id>?expanded=true in
+// Goodreads' markup. details holds the moreDetails dataRows.
+func editionHTML(id int, title, published, format, details string) string {
+	return fmt.Sprintf(`<div class="elementList clearFix">
+  <div class="leftAlignedImage" style="width: 60px;"><a href="/book/show/%[1]d-slug"><img alt="%[2]s" src="https://i.gr-assets.com/%[1]d.jpg" /></a></div>
+  <div class="editionData">
+    <div class="dataRow">
+      <a class="bookTitle" href="/book/show/%[1]d-slug">%[2]s</a>
+    </div>
+    <div class="dataRow">
+      %[3]s
+    </div>
+    <div class="dataRow">%[4]s</div>
+    <div class="moreDetails hideDetails">
+      <div class="dataRow">
+        <div class="dataTitle">Author(s):</div>
+        <div class="dataValue"><a class="authorName" itemprop="url" href="/author/show/5236.Andre_Brink"><span itemprop="name">André Brink</span></a></div>
+      </div>
+      %[5]s
+    </div>
+  </div>
+  <div class="otherEditionsActions"><a class="actionLinkLite" href="#">Switch to this edition</a></div>
+</div>`, id, title, published, format, details)
+}
+
+func dataRowHTML(title, value string) string {
+	return fmt.Sprintf(`<div class="dataRow"><div class="dataTitle">%s</div><div class="dataValue">%s</div></div>`, title, value)
+}
+
+func TestParseEditionsHTML(t *testing.T) {
+	html := `<div class="workEditions">` +
+		editionHTML(18690730, "Tuokio tuulessa", "Published 1978\n        by WSOY", "Hardcover, 350 pages",
+			dataRowHTML("ISBN:", `9510085669<span class="greyText"> (ISBN13: 9789510085660)</span>`)+
+				dataRowHTML("Edition language:", "Finnish")+
+				dataRowHTML("Average rating:", "4.06 (12 ratings)")) +
+		editionHTML(2041274, "A Chain of Voices &amp; Other", "Published October 1st 2011 by Vintage Digital", "Kindle Edition",
+			dataRowHTML("ASIN:", "B005OCTQVA")+
+				dataRowHTML("Edition language:", "English")) +
+		editionHTML(100, "Rumours of Rain", "Expected publication 2027 by Penguin", "Paperback, 432 pages",
+			dataRowHTML("ISBN:", "9780140067385")) +
+		`</div>`
+
+	got := ParseEditionsHTML(html)
+	want := []Book{
+		{
+			ID: "18690730", Title: "Tuokio tuulessa", Author: "André Brink",
+			URL:      "https://www.goodreads.com/book/show/18690730",
+			ImageURL: "https://i.gr-assets.com/18690730.jpg",
+			Year:     "1978", Publisher: "WSOY", Format: "Hardcover", Pages: 350,
+			ISBN: "9510085669", ISBN13: "9789510085660", Language: "Finnish",
+			Rating: "4.06", RatingsCount: 12,
+		},
+		{
+			ID: "2041274", Title: "A Chain of Voices & Other", Author: "André Brink",
+			URL:      "https://www.goodreads.com/book/show/2041274",
+			ImageURL: "https://i.gr-assets.com/2041274.jpg",
+			Year:     "2011", Publisher: "Vintage Digital", Format: "Kindle Edition",
+			ASIN: "B005OCTQVA", Language: "English",
+		},
+		{
+			ID: "100", Title: "Rumours of Rain", Author: "André Brink",
+			URL:      "https://www.goodreads.com/book/show/100",
+			ImageURL: "https://i.gr-assets.com/100.jpg",
+			Year:     "2027", Publisher: "Penguin", Format: "Paperback", Pages: 432,
+			ISBN13: "9780140067385",
+		},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d editions, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if !reflect.DeepEqual(got[i], want[i]) {
+			t.Errorf("edition %d = %+v\nwant %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestParseEditionsHTML_NoEditions(t *testing.T) {
+	if got := ParseEditionsHTML(`<div class="workEditions"></div>`); len(got) != 0 {
+		t.Errorf("got %d editions, want 0", len(got))
+	}
+}
+
+func TestEditionsURL(t *testing.T) {
+	u, err := url.Parse(editionsURL("2041274", EditionOptions{Language: "fin", Format: "ebook"}, 2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Path != "/work/editions/2041274" {
+		t.Errorf("path = %q", u.Path)
+	}
+	q := u.Query()
+	for k, v := range map[string]string{
+		"expanded": "true", "per_page": "100", "page": "2", "filter_by_language": "fin", "filter_by_format": "ebook",
+	} {
+		if q.Get(k) != v {
+			t.Errorf("%s = %q, want %q", k, q.Get(k), v)
+		}
+	}
+
+	u, _ = url.Parse(editionsURL("2041274", EditionOptions{}, 1))
+	if q := u.Query(); q.Has("filter_by_language") || q.Has("filter_by_format") {
+		t.Errorf("unfiltered URL carries filters: %s", u)
+	}
+}
+
+// TestEditionPages checks paging stops at the first short page.
+func TestEditionPages(t *testing.T) {
+	var pages []string
+	fetch := func(u string) (string, error) {
+		pu, _ := url.Parse(u)
+		page := pu.Query().Get("page")
+		pages = append(pages, page)
+		n := editionsPerPage
+		if page == "2" {
+			n = 3
+		}
+		var sb strings.Builder
+		for i := 0; i < n; i++ {
+			sb.WriteString(editionHTML(i+1, "Edition", "Published 2000 by X", "Paperback", ""))
+		}
+		return sb.String(), nil
+	}
+	books, err := editionPages(fetch, "1", EditionOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(books) != editionsPerPage+3 {
+		t.Errorf("got %d editions, want %d", len(books), editionsPerPage+3)
+	}
+	if !reflect.DeepEqual(pages, []string{"1", "2"}) {
+		t.Errorf("fetched pages %v, want [1 2]", pages)
+	}
+}
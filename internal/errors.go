@@ -0,0 +1,151 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+)
+
+// ErrorCode classifies a failure so scripts and agents can tell a missing
+// login from an AWS WAF block or a typo without matching error text. Each
+// code has its own process exit code; see ExitCode.
+type ErrorCode string
+
+const (
+	CodeError         ErrorCode = "error"          // anything not classified below
+	CodeInvalidInput  ErrorCode = "invalid_input"  // bad arguments, flags or input files
+	CodeNotLoggedIn   ErrorCode = "not_logged_in"  // no Goodreads session; run 'goodreads login'
+	CodeNotFound      ErrorCode = "not_found"      // the book, author, shelf or page doesn't exist
+	CodeWAFBlocked    ErrorCode = "waf_blocked"    // AWS WAF served a challenge instead of the page
+	CodeRateLimited   ErrorCode = "rate_limited"   // Goodreads answered 429 Too Many Requests
+	CodeSelectorDrift ErrorCode = "selector_drift" // a page no longer has the element we look for
+)
+
+// ErrorCodes lists the codes in exit code order, for documentation.
+var ErrorCodes = []ErrorCode{
+	CodeError, CodeInvalidInput, CodeNotLoggedIn, CodeNotFound,
+	CodeWAFBlocked, CodeRateLimited, CodeSelectorDrift,
+}
+
+// ExitCode is the process exit status for code: 1 for unclassified
+// errors, then 2 onwards in ErrorCodes order. 2 for invalid input matches
+// the usual meaning of a usage error.
+func (c ErrorCode) ExitCode() int {
+	for i, code := range ErrorCodes {
+		if code == c {
+			return i + 1
+		}
+	}
+	return 1
+}
+
+// codedError gives err a code without changing its message.
+type codedError struct {
+	code ErrorCode
+	err  error
+}
+
+func (e *codedError) Error() string { return e.err.Error() }
+func (e *codedError) Unwrap() error { return e.err }
+
+// WithCode marks err with code; a nil err stays nil. The message is
+// unchanged, and errors.Is and errors.As see through the mark.
+func WithCode(code ErrorCode, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &codedError{code, err}
+}
+
+// Errorf is fmt.Errorf marked with code.
+func Errorf(code ErrorCode, format string, args ...any) error {
+	return WithCode(code, fmt.Errorf(format, args...))
+}
+
+// CodeOf returns the code of the outermost marked error in err's chain,
+// or CodeError if there is none.
+func CodeOf(err error) ErrorCode {
+	var ce *codedError
+	if errors.As(err, &ce) {
+		return ce.code
+	}
+	return CodeError
+}
+
+// ErrorReport is an error as --error-format json writes it.
+type ErrorReport struct {
+	Code     ErrorCode `json:"code"`
+	Message  string    `json:"message"`
+	ExitCode int       `json:"exit_code"`
+}
+
+// Report describes err for --error-format json.
+func Report(err error) ErrorReport {
+	code := CodeOf(err)
+	return ErrorReport{Code: code, Message: err.Error(), ExitCode: code.ExitCode()}
+}
+
+// PanicError is the error for a recovered panic, such as go-rod's Must*
+// helpers raise on a navigation timeout. It is unclassified, so it exits
+// 1 and isn't mistaken for invalid input, whose exit code 2 is also what
+// Go gives an uncaught panic. Called from the deferred function that
+// recovered p, it keeps the stack p was raised on for PanicStack.
+func PanicError(p any) error {
+	return &panicError{value: p, stack: debug.Stack()}
+}
+
+type panicError struct {
+	value any
+	stack []byte
+}
+
+func (e *panicError) Error() string { return fmt.Sprintf("internal error: %v", e.value) }
+
+// PanicStack returns the stack of a PanicError, or nil for other errors.
+func PanicStack(err error) []byte {
+	var pe *panicError
+	if errors.As(err, &pe) {
+		return pe.stack
+	}
+	return nil
+}
+
+// Guard calls fn and returns a panic in it as a PanicError, so one
+// browser timeout neither ends a long-running server nor skips the CLI's
+// error report. After a panic, reset, if not nil, drops state the panic
+// may have left broken, such as a browser on a half-loaded page; closing
+// a broken browser may panic too, which is ignored.
+func Guard[T any](fn func() (T, error), reset func()) (out T, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			var zero T
+			out, err = zero, PanicError(p)
+			if reset != nil {
+				func() {
+					defer func() { recover() }()
+					reset()
+				}()
+			}
+		}
+	}()
+	return fn()
+}
+
+// ErrNotLoggedIn is returned when a command needs a Goodreads session and
+// the browser has none.
+var ErrNotLoggedIn = Errorf(CodeNotLoggedIn, "not logged in — run 'goodreads login' first")
+
+// httpStatusError is the error for an unexpected HTTP status, marked as
+// not found for 404 and rate limited for 429 so callers can tell those
+// apart from other failures.
+func httpStatusError(status int, format string, args ...any) error {
+	err := fmt.Errorf(format, args...)
+	switch status {
+	case http.StatusNotFound, http.StatusGone:
+		return WithCode(CodeNotFound, err)
+	case http.StatusTooManyRequests:
+		return WithCode(CodeRateLimited, err)
+	}
+	return err
+}
@@ -0,0 +1,104 @@
+package internal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestErrorCode_ExitCode(t *testing.T) {
+	want := map[ErrorCode]int{
+		CodeError:         1,
+		CodeInvalidInput:  2,
+		CodeNotLoggedIn:   3,
+		CodeNotFound:      4,
+		CodeWAFBlocked:    5,
+		CodeRateLimited:   6,
+		CodeSelectorDrift: 7,
+		"something_else":  1,
+	}
+	for code, exit := range want {
+		if got := code.ExitCode(); got != exit {
+			t.Errorf("%s.ExitCode() = %d, want %d", code, got, exit)
+		}
+	}
+}
+
+func TestCodeOf(t *testing.T) {
+	_, _, badInput := ClassifyBookInput("nope")
+	wrapped := fmt.Errorf("listing shelf: %w", fmt.Errorf("%w (url=x)", ErrAWSWAFChallenge))
+	for _, tt := range []struct {
+		err  error
+		want ErrorCode
+	}{
+		{nil, CodeError},
+		{errors.New("boom"), CodeError},
+		{ErrNotLoggedIn, CodeNotLoggedIn},
+		{wrapped, CodeWAFBlocked},
+		{Errorf(CodeNotFound, "book %s: %w", "1", Errorf(CodeSelectorDrift, "no title")), CodeNotFound},
+		{badInput, CodeInvalidInput},
+	} {
+		if got := CodeOf(tt.err); got != tt.want {
+			t.Errorf("CodeOf(%v) = %s, want %s", tt.err, got, tt.want)
+		}
+	}
+
+	if !errors.Is(wrapped, ErrAWSWAFChallenge) {
+		t.Error("errors.Is lost ErrAWSWAFChallenge through the code")
+	}
+	if err := Errorf(CodeNotFound, "book %s", "1"); err.Error() != "book 1" {
+		t.Errorf("message = %q, want it unchanged", err.Error())
+	}
+	if WithCode(CodeNotFound, nil) != nil {
+		t.Error("WithCode(nil) != nil")
+	}
+}
+
+func TestHTTPStatusCodes(t *testing.T) {
+	for _, tt := range []struct {
+		status int
+		want   ErrorCode
+	}{
+		{http.StatusNotFound, CodeNotFound},
+		{http.StatusTooManyRequests, CodeRateLimited},
+		{http.StatusInternalServerError, CodeError},
+	} {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(tt.status)
+		}))
+		c := &Client{HTTP: ts.Client()}
+		_, err := c.autocomplete(ts.URL, "dune")
+		if got := CodeOf(err); got != tt.want {
+			t.Errorf("status %d: CodeOf(%v) = %s, want %s", tt.status, err, got, tt.want)
+		}
+		_, err = c.fetchHTML(ts.URL + "/review/list/1")
+		if got := CodeOf(err); got != tt.want {
+			t.Errorf("status %d: fetchHTML CodeOf(%v) = %s, want %s", tt.status, err, got, tt.want)
+		}
+		ts.Close()
+	}
+}
+
+func TestReport(t *testing.T) {
+	resets := 0
+	_, err := Guard(func() (string, error) { panic("navigation timed out") }, func() {
+		resets++
+		panic("closing a broken browser")
+	})
+	if resets != 1 || PanicStack(err) == nil {
+		t.Errorf("after a panic: %d resets, stack %v", resets, PanicStack(err) != nil)
+	}
+	data, _ := json.Marshal(Report(err))
+	if want := `{"code":"error","message":"internal error: navigation timed out","exit_code":1}`; string(data) != want {
+		t.Errorf("panic report = %s, want %s", data, want)
+	}
+
+	data, _ = json.Marshal(Report(fmt.Errorf("listing: %w", ErrAWSWAFChallenge)))
+	if want := `"code":"waf_blocked"`; !strings.Contains(string(data), want) || !strings.Contains(string(data), `"exit_code":5`) {
+		t.Errorf("WAF report = %s", data)
+	}
+}
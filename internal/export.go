@@ -0,0 +1,121 @@
+package internal
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// goodreadsCSVColumns are the columns of Goodreads' own library export
+// (goodreads_library_export.csv), in order.
+var goodreadsCSVColumns = []string{
+	"Book Id", "Title", "Author", "Author l-f", "Additional Authors",
+	"ISBN", "ISBN13", "My Rating", "Average Rating", "Publisher", "Binding",
+	"Number of Pages", "Year Published", "Original Publication Year",
+	"Date Read", "Date Added", "Bookshelves", "Bookshelves with positions",
+	"Exclusive Shelf", "My Review", "Spoiler", "Private Notes", "Read Count",
+	"Owned Copies",
+}
+
+// GoodreadsCSVWriter writes shelf entries in the format of Goodreads'
+// library export, so tools that import that file (StoryGraph, Calibre
+// plugins, …) read ours unchanged.
+type GoodreadsCSVWriter struct {
+	w *csv.Writer
+}
+
+// NewGoodreadsCSVWriter writes the header row to w and returns a writer
+// for the entries.
+func NewGoodreadsCSVWriter(w io.Writer) (*GoodreadsCSVWriter, error) {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(goodreadsCSVColumns); err != nil {
+		return nil, err
+	}
+	return &GoodreadsCSVWriter{w: cw}, nil
+}
+
+// Write appends one row per entry.
+func (g *GoodreadsCSVWriter) Write(entries []ShelfEntry) error {
+	for _, e := range entries {
+		if err := g.w.Write(goodreadsCSVRow(e)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Flush writes any buffered rows to the underlying writer.
+func (g *GoodreadsCSVWriter) Flush() error {
+	g.w.Flush()
+	return g.w.Error()
+}
+
+// goodreadsCSVRow renders an entry the way Goodreads' export does: ISBNs
+// as ="…" formulas so spreadsheets keep leading zeros, dates as
+// YYYY/MM/DD, the want-to-read shelf under its "to-read" slug, and
+// Bookshelves listing every shelf but "read". Shelf positions and private
+// notes aren't on the shelf page, so those columns stay empty; so does
+// Publisher unless the caller fills it in from the book page.
+func goodreadsCSVRow(e ShelfEntry) []string {
+	exclusive := ""
+	var shelves []string
+	for _, s := range e.Shelves {
+		slug := s
+		if s == "want-to-read" {
+			slug = "to-read"
+		}
+		if exclusive == "" && IsExclusiveShelf(s) {
+			exclusive = slug
+		}
+		if slug != "read" {
+			shelves = append(shelves, slug)
+		}
+	}
+	pages := ""
+	if e.Pages > 0 {
+		pages = strconv.Itoa(e.Pages)
+	}
+	return []string{
+		e.ID,
+		e.Title,
+		e.Author,
+		authorLastFirst(e.Author),
+		"",
+		`="` + e.ISBN + `"`,
+		`="` + e.ISBN13 + `"`,
+		strconv.Itoa(e.MyRating),
+		e.Rating,
+		e.Publisher,
+		e.Format,
+		pages,
+		e.Year,
+		e.OriginalYear,
+		slashDate(e.DateRead),
+		slashDate(e.DateAdded),
+		strings.Join(shelves, ", "),
+		"",
+		exclusive,
+		e.Review,
+		"",
+		"",
+		strconv.Itoa(e.ReadCount),
+		"0",
+	}
+}
+
+// authorLastFirst turns "André P. Brink" into "Brink, André P.".
+// Single-word names are returned as they are.
+func authorLastFirst(name string) string {
+	fields := strings.Fields(name)
+	if len(fields) < 2 {
+		return name
+	}
+	last := len(fields) - 1
+	return fields[last] + ", " + strings.Join(fields[:last], " ")
+}
+
+// slashDate turns a YYYY-MM-DD date into Goodreads' export format.
+func slashDate(iso string) string {
+	return strings.ReplaceAll(iso, "-", "/")
+}
@@ -0,0 +1,100 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+)
+
+func TestGoodreadsCSVWriter(t *testing.T) {
+	entries := []ShelfEntry{
+		{
+			Book: Book{
+				ID: "18690730", Title: "Tuokio tuulessa", Author: "André Brink",
+				ISBN: "9510085669", ISBN13: "9789510085660", Rating: "4.06",
+				Format: "Hardcover", Pages: 350, Year: "1978", Publisher: "Otava",
+			},
+			MyRating: 5, Shelves: []string{"read", "favorites"},
+			Review: "Line one,\n\"quoted\"", ReadCount: 2,
+			DateRead: "2026-02-28", DateAdded: "2025-12-01", OriginalYear: "1976",
+		},
+		{
+			Book:    Book{ID: "55145261", Title: "The Anthropocene Reviewed", Author: "John Green"},
+			Shelves: []string{"want-to-read"},
+		},
+	}
+
+	var buf bytes.Buffer
+	w, err := NewGoodreadsCSVWriter(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Write(entries); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("output is not valid CSV: %v", err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("got %d rows, want header + 2", len(rows))
+	}
+	col := map[string]int{}
+	for i, name := range rows[0] {
+		col[name] = i
+	}
+	if len(col) != 24 || rows[0][0] != "Book Id" || rows[0][23] != "Owned Copies" {
+		t.Errorf("header = %q", rows[0])
+	}
+
+	want := map[string]string{
+		"Book Id":                   "18690730",
+		"Author l-f":                "Brink, André",
+		"ISBN":                      `="9510085669"`,
+		"ISBN13":                    `="9789510085660"`,
+		"My Rating":                 "5",
+		"Publisher":                 "Otava",
+		"Binding":                   "Hardcover",
+		"Number of Pages":           "350",
+		"Year Published":            "1978",
+		"Original Publication Year": "1976",
+		"Date Read":                 "2026/02/28",
+		"Date Added":                "2025/12/01",
+		"Bookshelves":               "favorites",
+		"Exclusive Shelf":           "read",
+		"My Review":                 "Line one,\n\"quoted\"",
+		"Read Count":                "2",
+	}
+	for name, v := range want {
+		if got := rows[1][col[name]]; got != v {
+			t.Errorf("%s = %q, want %q", name, got, v)
+		}
+	}
+
+	for name, v := range map[string]string{
+		"ISBN": `=""`, "My Rating": "0", "Number of Pages": "", "Date Read": "",
+		"Bookshelves": "to-read", "Exclusive Shelf": "to-read", "Publisher": "",
+	} {
+		if got := rows[2][col[name]]; got != v {
+			t.Errorf("row 2 %s = %q, want %q", name, got, v)
+		}
+	}
+}
+
+func TestAuthorLastFirst(t *testing.T) {
+	tests := map[string]string{
+		"André Brink":    "Brink, André",
+		"André P. Brink": "Brink, André P.",
+		"Homer":          "Homer",
+		"":               "",
+	}
+	for in, want := range tests {
+		if got := authorLastFirst(in); got != want {
+			t.Errorf("authorLastFirst(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,428 @@
+package internal
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Sources accepted by ParseImportCSV.
+const (
+	ImportGoodreads    = "goodreads"
+	ImportStoryGraph   = "storygraph"
+	ImportLibraryThing = "librarything"
+)
+
+// ImportRecord is one book from an import file, normalized across
+// sources. Shelf is an exclusive shelf name as the CLI uses it
+// (want-to-read, currently-reading, read); Shelves are extra custom
+// shelves. Dates are YYYY-MM-DD and Rating is 0-5, 0 for unrated.
+type ImportRecord struct {
+	Line        int      `json:"line"` // 1-based line in the file, the header being line 1
+	Title       string   `json:"title"`
+	Author      string   `json:"author"`
+	ISBN        string   `json:"isbn,omitempty"`
+	ISBN13      string   `json:"isbn13,omitempty"`
+	Shelf       string   `json:"shelf"`
+	Shelves     []string `json:"shelves,omitempty"`
+	Rating      int      `json:"rating,omitempty"`
+	DateStarted string   `json:"date_started,omitempty"`
+	DateRead    string   `json:"date_read,omitempty"`
+}
+
+// ParseImportCSV reads the export file of source — Goodreads' library
+// export, StoryGraph's export or LibraryThing's CSV export — into
+// records. Half-star ratings are rounded up and unparseable dates are
+// dropped; rows without a title are skipped.
+func ParseImportCSV(r io.Reader, source string) ([]ImportRecord, error) {
+	var parse func(row map[string]string) ImportRecord
+	switch source {
+	case ImportGoodreads:
+		parse = parseGoodreadsImportRow
+	case ImportStoryGraph:
+		parse = parseStoryGraphImportRow
+	case ImportLibraryThing:
+		parse = parseLibraryThingImportRow
+	default:
+		return nil, Errorf(CodeInvalidInput, "unknown import source %q — use goodreads, storygraph or librarything", source)
+	}
+
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.LazyQuotes = true // Goodreads writes ISBNs as bare ="…"
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading CSV header: %w", err)
+	}
+	for i := range header {
+		header[i] = strings.TrimSpace(strings.TrimPrefix(header[i], "\ufeff"))
+	}
+
+	var records []ImportRecord
+	for {
+		fields, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading CSV: %w", err)
+		}
+		line, _ := cr.FieldPos(0)
+		row := map[string]string{}
+		for i, name := range header {
+			if i < len(fields) {
+				row[name] = strings.TrimSpace(fields[i])
+			}
+		}
+		rec := parse(row)
+		if rec.Title == "" {
+			continue
+		}
+		rec.Line = line
+		records = append(records, rec)
+	}
+	return records, nil
+}
+
+func parseGoodreadsImportRow(row map[string]string) ImportRecord {
+	rec := ImportRecord{
+		Title:    row["Title"],
+		Author:   row["Author"],
+		ISBN:     importISBN(row["ISBN"]),
+		ISBN13:   importISBN(row["ISBN13"]),
+		Shelf:    canonicalShelfName(row["Exclusive Shelf"]),
+		Rating:   importRating(row["My Rating"]),
+		DateRead: importDate(row["Date Read"]),
+	}
+	for _, s := range strings.Split(row["Bookshelves"], ",") {
+		s = canonicalShelfName(strings.TrimSpace(s))
+		if s != "" && !IsExclusiveShelf(s) {
+			rec.Shelves = append(rec.Shelves, s)
+		}
+	}
+	return rec
+}
+
+// parseStoryGraphImportRow maps StoryGraph's export. Its "Dates Read"
+// column holds "start-end" pairs for each read; the most recent pair
+// gives the dates. Did-not-finish books have no Goodreads equivalent and
+// go to a "did-not-finish" shelf alongside read.
+func parseStoryGraphImportRow(row map[string]string) ImportRecord {
+	rec := ImportRecord{
+		Title:    row["Title"],
+		Author:   firstAuthor(row["Authors"]),
+		Rating:   importRating(row["Star Rating"]),
+		DateRead: importDate(row["Last Date Read"]),
+	}
+	rec.ISBN, rec.ISBN13 = splitISBN(importISBN(row["ISBN/UID"]))
+	switch row["Read Status"] {
+	case "to-read":
+		rec.Shelf = "want-to-read"
+	case "currently-reading":
+		rec.Shelf = "currently-reading"
+	case "did-not-finish":
+		rec.Shelf = "read"
+		rec.Shelves = append(rec.Shelves, "did-not-finish")
+	default:
+		rec.Shelf = "read"
+	}
+	if reads := strings.Split(row["Dates Read"], ","); reads[len(reads)-1] != "" {
+		last := strings.TrimSpace(reads[len(reads)-1])
+		if started, finished, ok := strings.Cut(last, "-"); ok {
+			rec.DateStarted = importDate(started)
+			if rec.DateRead == "" {
+				rec.DateRead = importDate(finished)
+			}
+		}
+	}
+	for _, tag := range strings.Split(row["Tags"], ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			rec.Shelves = append(rec.Shelves, tag)
+		}
+	}
+	return rec
+}
+
+// parseLibraryThingImportRow maps LibraryThing's CSV export. Its
+// collections stand in for shelves: "Currently reading" and "To read"
+// (or "Wishlist") map onto the Goodreads shelves of the same meaning and
+// everything else is taken as read.
+func parseLibraryThingImportRow(row map[string]string) ImportRecord {
+	rec := ImportRecord{
+		Title:       row["Title"],
+		Author:      lastFirstToName(row["Primary Author"]),
+		Rating:      importRating(row["Rating"]),
+		DateStarted: importDate(row["Date Started"]),
+		DateRead:    importDate(row["Date Read"]),
+	}
+	rec.ISBN, rec.ISBN13 = splitISBN(importISBN(row["ISBN"]))
+	collections := strings.ToLower(row["Collections"])
+	switch {
+	case strings.Contains(collections, "currently reading"):
+		rec.Shelf = "currently-reading"
+	case strings.Contains(collections, "to read"), strings.Contains(collections, "wishlist"):
+		rec.Shelf = "want-to-read"
+	default:
+		rec.Shelf = "read"
+	}
+	return rec
+}
+
+// importISBN strips the ="…" and […] wrappers exports put around ISBNs.
+func importISBN(s string) string {
+	s = strings.Trim(s, `=" []`)
+	if m := _importISBNRE.FindString(strings.ReplaceAll(s, "-", "")); m != "" {
+		return m
+	}
+	return ""
+}
+
+// splitISBN files an ISBN of either length under the right field.
+func splitISBN(isbn string) (isbn10, isbn13 string) {
+	if len(isbn) == 13 {
+		return "", isbn
+	}
+	return isbn, ""
+}
+
+// importRating parses "4", "4.5" or "4.0" stars into 0-5, rounding half
+// stars up.
+func importRating(s string) int {
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || f < 0 {
+		return 0
+	}
+	return min(int(math.Round(f)), 5)
+}
+
+// importDate normalizes YYYY/MM/DD and YYYY-MM-DD dates, returning "" for
+// anything else.
+func importDate(s string) string {
+	s = strings.ReplaceAll(strings.TrimSpace(s), "/", "-")
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t.Format("2006-01-02")
+	}
+	return ""
+}
+
+// firstAuthor returns the first of a comma-separated author list.
+func firstAuthor(s string) string {
+	first, _, _ := strings.Cut(s, ",")
+	return strings.TrimSpace(first)
+}
+
+// lastFirstToName turns "Brink, André" back into "André Brink".
+func lastFirstToName(s string) string {
+	last, first, ok := strings.Cut(s, ",")
+	if !ok {
+		return strings.TrimSpace(s)
+	}
+	return strings.TrimSpace(first) + " " + strings.TrimSpace(last)
+}
+
+// MatchImportRecord finds the Goodreads book for a record: by ISBN-13 or
+// ISBN-10 when it has one, else by searching for title and author. A
+// search hit only counts when its title is the record's title (ignoring
+// case, punctuation, subtitles and series) and it is by an author with
+// the same surname, so an unknown book is reported rather than shelved as
+// something else. The same goes for an ISBN that only autocomplete knows,
+// since its hits are a search too; /book/isbn's redirect is taken as is.
+// how says which route matched.
+func (c *Client) MatchImportRecord(rec ImportRecord) (bookID, how string, err error) {
+	return c.matchImportRecord(BaseURL, rec)
+}
+
+func (c *Client) matchImportRecord(base string, rec ImportRecord) (bookID, how string, err error) {
+	for _, isbn := range []string{rec.ISBN13, rec.ISBN} {
+		if isbn == "" {
+			continue
+		}
+		if id, err := c.isbnRedirect(base, isbn); err == nil && id != "" {
+			return id, "isbn", nil
+		}
+		books, err := c.autocomplete(base, isbn)
+		if err != nil || len(books) == 0 {
+			continue
+		}
+		if b, ok := matchTitleAuthor(books, rec.Title, rec.Author); ok {
+			return b.ID, "isbn", nil
+		}
+		return "", "", Errorf(CodeNotFound, "ISBN %s is %q by %s on Goodreads, not %q by %s",
+			isbn, books[0].Title, books[0].Author, rec.Title, rec.Author)
+	}
+
+	books, err := c.autocomplete(base, strings.TrimSpace(rec.Title+" "+rec.Author))
+	if err != nil {
+		return "", "", err
+	}
+	if b, ok := matchTitleAuthor(books, rec.Title, rec.Author); ok {
+		return b.ID, "search", nil
+	}
+	return "", "", Errorf(CodeNotFound, "no match for %q by %s", rec.Title, rec.Author)
+}
+
+// matchTitleAuthor picks the first search hit that fits title and author.
+func matchTitleAuthor(books []Book, title, author string) (Book, bool) {
+	want := matchKey(baseTitle(title))
+	surname := ""
+	if f := strings.Fields(author); len(f) > 0 {
+		surname = matchKey(f[len(f)-1])
+	}
+	for _, b := range books {
+		if want == "" || matchKey(baseTitle(b.Title)) != want {
+			continue
+		}
+		if surname != "" && !strings.Contains(matchKey(b.Author), surname) {
+			continue
+		}
+		return b, true
+	}
+	return Book{}, false
+}
+
+// baseTitle drops a subtitle ("Dune: Deluxe Edition") and Goodreads'
+// series suffix ("Dune (Dune, #1)").
+func baseTitle(title string) string {
+	title, _, _ = strings.Cut(title, ":")
+	if i := strings.LastIndex(title, " ("); i > 0 && strings.HasSuffix(title, ")") {
+		title = title[:i]
+	}
+	return title
+}
+
+// matchKey lowercases s and drops everything but letters and digits.
+func matchKey(s string) string {
+	return _nonAlnumRE.ReplaceAllString(strings.ToLower(s), "")
+}
+
+// ApplyImportRecord shelves bookID as rec describes: the exclusive shelf,
+// any custom shelves, then the rating and reading dates. An existing
+// review text is kept when the rating is set.
+func ApplyImportRecord(b *Browser, bookID string, rec ImportRecord) error {
+	shelf := rec.Shelf
+	if shelf == "" {
+		shelf = "read"
+	}
+	if err := AddToShelf(b, bookID, shelf); err != nil {
+		return fmt.Errorf("shelving as %s: %w", shelf, err)
+	}
+	for _, s := range rec.Shelves {
+		if err := AddToShelf(b, bookID, s); err != nil {
+			return fmt.Errorf("adding to shelf %s: %w", s, err)
+		}
+	}
+	if rec.Rating > 0 {
+		r, err := GetReview(b, bookID)
+		if err != nil {
+			return err
+		}
+		if r.Rating != rec.Rating {
+			r.Rating = rec.Rating
+			if err := SaveReview(b, r); err != nil {
+				return fmt.Errorf("rating: %w", err)
+			}
+		}
+	}
+	dates := ReadingDates{Started: rec.DateStarted}
+	if shelf == "read" {
+		dates.Finished = rec.DateRead
+	}
+	if dates.Started != "" || dates.Finished != "" {
+		if err := SetReadingDates(b, bookID, dates); err != nil {
+			return fmt.Errorf("reading dates: %w", err)
+		}
+	}
+	return nil
+}
+
+// Statuses of an ImportResult.
+const (
+	ImportStatusImported  = "imported"
+	ImportStatusUnmatched = "unmatched"
+	ImportStatusFailed    = "failed"
+)
+
+// ImportResult is the outcome of importing one record.
+type ImportResult struct {
+	Line   int    `json:"line"`
+	Title  string `json:"title"`
+	BookID string `json:"book_id,omitempty"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// ImportCheckpoint records finished rows of an import so a rerun can
+// skip them. Imported and unmatched rows count as finished; failed rows
+// are retried.
+type ImportCheckpoint struct {
+	path    string
+	Source  string               `json:"source"`
+	Results map[int]ImportResult `json:"results"` // by line
+}
+
+// LoadImportCheckpoint reads the checkpoint at path, or starts an empty
+// one when the file doesn't exist. A checkpoint written for another
+// source is an error, as its line numbers mean nothing for this file.
+func LoadImportCheckpoint(path, source string) (*ImportCheckpoint, error) {
+	cp := &ImportCheckpoint{path: path, Source: source, Results: map[int]ImportResult{}}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return cp, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, cp); err != nil {
+		return nil, fmt.Errorf("reading checkpoint %s: %w", path, err)
+	}
+	if cp.Source != source {
+		return nil, Errorf(CodeInvalidInput, "checkpoint %s is for a %s import, not %s", path, cp.Source, source)
+	}
+	if cp.Results == nil {
+		cp.Results = map[int]ImportResult{}
+	}
+	return cp, nil
+}
+
+// Done reports whether the row at line needs no more work.
+func (cp *ImportCheckpoint) Done(line int) bool {
+	r, ok := cp.Results[line]
+	return ok && r.Status != ImportStatusFailed
+}
+
+// Record stores a result and writes the checkpoint, through a temporary
+// file so an interrupted write never leaves a truncated checkpoint.
+func (cp *ImportCheckpoint) Record(r ImportResult) error {
+	cp.Results[r.Line] = r
+	data, err := json.MarshalIndent(cp, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(cp.path), ".import-checkpoint-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), cp.path)
+}
+
+var (
+	_importISBNRE = regexp.MustCompile(`^(?:\d{13}|\d{9}[\dX])$`)
+	_nonAlnumRE   = regexp.MustCompile(`[^\p{L}\p{N}]+`)
+)
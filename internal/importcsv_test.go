@@ -0,0 +1,192 @@
+package internal
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseImportCSV(t *testing.T) {
+	tests := []struct {
+		source string
+		csv    string
+		want   []ImportRecord
+	}{
+		{
+			source: ImportGoodreads,
+			csv: "\ufeffBook Id,Title,Author,Author l-f,Additional Authors,ISBN,ISBN13,My Rating,Average Rating,Publisher,Binding,Number of Pages,Year Published,Original Publication Year,Date Read,Date Added,Bookshelves,Bookshelves with positions,Exclusive Shelf,My Review,Spoiler,Private Notes,Read Count,Owned Copies\n" +
+				`18690730,Tuokio tuulessa,André Brink,"Brink, André",,="9510085669",="9789510085660",5,4.06,WSOY,Hardcover,350,1978,1976,2026/02/28,2025/12/01,"favorites, book-club",,read,,,,1,0` + "\n" +
+				`55145261,The Anthropocene Reviewed,John Green,"Green, John",,="",="",0,4.36,,,,,,,2026/01/05,to-read,to-read (#3),to-read,,,,0,0` + "\n",
+			want: []ImportRecord{
+				{Line: 2, Title: "Tuokio tuulessa", Author: "André Brink", ISBN: "9510085669", ISBN13: "9789510085660",
+					Shelf: "read", Shelves: []string{"favorites", "book-club"}, Rating: 5, DateRead: "2026-02-28"},
+				{Line: 3, Title: "The Anthropocene Reviewed", Author: "John Green", Shelf: "want-to-read"},
+			},
+		},
+		{
+			source: ImportStoryGraph,
+			csv: "Title,Authors,Contributors,ISBN/UID,Format,Read Status,Date Added,Last Date Read,Dates Read,Read Count,Moods,Pace,Character- or Plot-Driven?,Strong Character Development?,Loveable Characters?,Diverse Characters?,Flawed Characters?,Star Rating,Review,Content Warnings,Content Warning Description,Tags,Owned?\n" +
+				`Project Hail Mary,"Andy Weir, Ray Porter",,9780593135204,hardcover,read,2025/09/01,2025/10/03,"2021/06/01-2021/06/20, 2025/09/20-2025/10/03",2,,,,,,,,4.5,,,,"sci-fi, book-club",No` + "\n" +
+				`Piranesi,Susanna Clarke,,1635575664,paperback,did-not-finish,2025/09/01,,,0,,,,,,,,,,,,,No` + "\n",
+			want: []ImportRecord{
+				{Line: 2, Title: "Project Hail Mary", Author: "Andy Weir", ISBN13: "9780593135204", Shelf: "read",
+					Shelves: []string{"sci-fi", "book-club"}, Rating: 5, DateStarted: "2025-09-20", DateRead: "2025-10-03"},
+				{Line: 3, Title: "Piranesi", Author: "Susanna Clarke", ISBN: "1635575664", Shelf: "read", Shelves: []string{"did-not-finish"}},
+			},
+		},
+		{
+			source: ImportLibraryThing,
+			csv: `"Book Id","Title","Sort Character","Primary Author","Primary Author Role","Secondary Author","Rating","Date Started","Date Read","Collections","ISBN"` + "\n" +
+				`"1","A Dry White Season","3","Brink, André","","","4","2024-03-01","2024-03-20","Your library","[0060871504]"` + "\n" +
+				`"2","Dune","1","Herbert, Frank","","","","","","To read","[9780441172719]"` + "\n",
+			want: []ImportRecord{
+				{Line: 2, Title: "A Dry White Season", Author: "André Brink", ISBN: "0060871504", Shelf: "read",
+					Rating: 4, DateStarted: "2024-03-01", DateRead: "2024-03-20"},
+				{Line: 3, Title: "Dune", Author: "Frank Herbert", ISBN13: "9780441172719", Shelf: "want-to-read"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		got, err := ParseImportCSV(strings.NewReader(tt.csv), tt.source)
+		if err != nil {
+			t.Errorf("%s: %v", tt.source, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s:\ngot  %+v\nwant %+v", tt.source, got, tt.want)
+		}
+	}
+
+	if _, err := ParseImportCSV(strings.NewReader("Title\n"), "calibre"); err == nil {
+		t.Error("expected an error for an unknown source")
+	}
+}
+
+func TestMatchTitleAuthor(t *testing.T) {
+	books := []Book{
+		{ID: "1", Title: "Dune Messiah", Author: "Frank Herbert"},
+		{ID: "2", Title: "Dune (Dune, #1)", Author: "Frank Herbert"},
+		{ID: "3", Title: "Dune: The Graphic Novel", Author: "Brian Herbert"},
+	}
+	tests := []struct {
+		title, author, want string
+	}{
+		{"Dune", "Frank Herbert", "2"},
+		{"Dune Messiah", "Frank Herbert", "1"},
+		{"Dune: Deluxe Edition", "Frank Herbert", "2"},
+		{"Children of Dune", "Frank Herbert", ""},
+		{"Dune", "Someone Else", ""},
+	}
+	for _, tt := range tests {
+		b, ok := matchTitleAuthor(books, tt.title, tt.author)
+		if got := map[bool]string{true: b.ID}[ok]; got != tt.want {
+			t.Errorf("matchTitleAuthor(%q, %q) = %q, want %q", tt.title, tt.author, got, tt.want)
+		}
+	}
+}
+
+// TestMatchImportRecord: an ISBN Goodreads redirects is trusted, but one
+// only autocomplete finds must be the row's book.
+func TestMatchImportRecord(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/book/isbn/9780441013593":
+			http.Redirect(w, r, "/book/show/44767458-dune", http.StatusFound)
+		case "/book/auto_complete":
+			hits := map[string][]autoCompleteResult{
+				"9780441172719":                  {{BookID: "234225", Title: "Dune (Dune, #1)", Author: autoCompleteAuth{Name: "Frank Herbert"}}},
+				"9780593099322":                  {{BookID: "106", Title: "Dune Messiah (Dune, #2)", Author: autoCompleteAuth{Name: "Frank Herbert"}}},
+				"Children of Dune Frank Herbert": {{BookID: "117", Title: "Children of Dune (Dune, #3)", Author: autoCompleteAuth{Name: "Frank Herbert"}}},
+			}[r.URL.Query().Get("q")]
+			json.NewEncoder(w).Encode(append([]autoCompleteResult{}, hits...))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+	c := &Client{HTTP: ts.Client()}
+
+	tests := []struct {
+		rec             ImportRecord
+		wantID, wantHow string
+	}{
+		{ImportRecord{Title: "Anything", ISBN13: "9780441013593"}, "44767458", "isbn"},
+		{ImportRecord{Title: "Dune", Author: "Frank Herbert", ISBN13: "9780441172719"}, "234225", "isbn"},
+		{ImportRecord{Title: "Dune", Author: "Frank Herbert", ISBN13: "9780593099322"}, "", ""},
+		{ImportRecord{Title: "Children of Dune", Author: "Frank Herbert", ISBN13: "9780000000002"}, "117", "search"},
+	}
+	for _, tt := range tests {
+		id, how, err := c.matchImportRecord(ts.URL, tt.rec)
+		if id != tt.wantID || how != tt.wantHow {
+			t.Errorf("matchImportRecord(%q, %s) = %q, %q, %v; want %q, %q", tt.rec.Title, tt.rec.ISBN13, id, how, err, tt.wantID, tt.wantHow)
+		}
+		if tt.wantID == "" && CodeOf(err) != CodeNotFound {
+			t.Errorf("matchImportRecord(%q, %s) error = %v, want not_found", tt.rec.Title, tt.rec.ISBN13, err)
+		}
+	}
+}
+
+func TestImportCheckpoint(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "import.checkpoint.json")
+	cp, err := LoadImportCheckpoint(path, ImportGoodreads)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range []ImportResult{
+		{Line: 2, BookID: "18690730", Status: ImportStatusImported},
+		{Line: 3, Status: ImportStatusUnmatched},
+		{Line: 4, BookID: "1", Status: ImportStatusFailed, Error: "timeout"},
+	} {
+		if err := cp.Record(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cp, err = LoadImportCheckpoint(path, ImportGoodreads)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for line, want := range map[int]bool{2: true, 3: true, 4: false, 5: false} {
+		if got := cp.Done(line); got != want {
+			t.Errorf("Done(%d) = %v, want %v", line, got, want)
+		}
+	}
+	if cp.Results[2].BookID != "18690730" {
+		t.Errorf("line 2 = %+v", cp.Results[2])
+	}
+
+	if _, err := LoadImportCheckpoint(path, ImportStoryGraph); err == nil {
+		t.Error("expected an error loading a goodreads checkpoint for a storygraph import")
+	}
+}
+
+// TestParseImportCSV_OwnExport checks that `goodreads export` output
+// imports back unchanged.
+func TestParseImportCSV_OwnExport(t *testing.T) {
+	var buf strings.Builder
+	w, err := NewGoodreadsCSVWriter(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Write([]ShelfEntry{{
+		Book:     Book{ID: "18690730", Title: "Tuokio tuulessa", Author: "André Brink", ISBN: "9510085669"},
+		MyRating: 4, Shelves: []string{"currently-reading", "book-club"},
+	}})
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ParseImportCSV(strings.NewReader(buf.String()), ImportGoodreads)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []ImportRecord{{Line: 2, Title: "Tuokio tuulessa", Author: "André Brink", ISBN: "9510085669",
+		Shelf: "currently-reading", Shelves: []string{"book-club"}, Rating: 4}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got  %+v\nwant %+v", got, want)
+	}
+}
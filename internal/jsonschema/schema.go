@@ -0,0 +1,94 @@
+// Package jsonschema derives JSON Schemas from Go types, for the tool and
+// API descriptions the MCP and HTTP servers publish.
+package jsonschema
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+// For returns the JSON Schema of the JSON encoding of values of t's
+// type, as encoding/json would produce it: fields named by their json
+// tags, embedded structs flattened, and fields without omitempty listed
+// as required. A field's `desc` tag becomes its description. Slices,
+// maps and pointers that aren't omitempty may encode as null and are
+// typed accordingly.
+func For(t reflect.Type) map[string]any {
+	return schemaFor(t, map[reflect.Type]bool{})
+}
+
+var timeType = reflect.TypeFor[time.Time]()
+
+func schemaFor(t reflect.Type, seen map[reflect.Type]bool) map[string]any {
+	if t == timeType {
+		return map[string]any{"type": "string", "format": "date-time"}
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Pointer:
+		return schemaFor(t.Elem(), seen)
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": schemaFor(t.Elem(), seen)}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": schemaFor(t.Elem(), seen)}
+	case reflect.Struct:
+		if seen[t] {
+			return map[string]any{"type": "object"} // recursive type: stop here
+		}
+		seen[t] = true
+		defer delete(seen, t)
+
+		props := map[string]any{}
+		required := []string{}
+		addStructFields(t, props, &required, seen)
+		s := map[string]any{"type": "object", "properties": props}
+		if len(required) > 0 {
+			s["required"] = required
+		}
+		return s
+	}
+	return map[string]any{} // interface{} and the like: anything
+}
+
+func addStructFields(t reflect.Type, props map[string]any, required *[]string, seen map[reflect.Type]bool) {
+	for i := range t.NumField() {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			addStructFields(f.Type, props, required, seen)
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		omitempty := strings.Contains(","+opts+",", ",omitempty,")
+
+		s := schemaFor(f.Type, seen)
+		if k := f.Type.Kind(); !omitempty && (k == reflect.Slice || k == reflect.Map || k == reflect.Pointer) {
+			s["type"] = []any{s["type"], "null"}
+		}
+		if d := f.Tag.Get("desc"); d != "" {
+			s["description"] = d
+		}
+		props[name] = s
+		if !omitempty {
+			*required = append(*required, name)
+		}
+	}
+}
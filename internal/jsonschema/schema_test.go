@@ -0,0 +1,82 @@
+package jsonschema
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/yareeh/goodreads-cli/internal"
+)
+
+func TestFor_Book(t *testing.T) {
+	s := For(reflect.TypeFor[internal.Book]())
+	if s["type"] != "object" {
+		t.Fatalf("type = %v, want object", s["type"])
+	}
+	props := s["properties"].(map[string]any)
+
+	for name, want := range map[string]any{
+		"id":            "string",
+		"pages":         "integer",
+		"genres":        "array",
+		"series":        "array",
+		"ratings_count": "integer",
+	} {
+		p, ok := props[name].(map[string]any)
+		if !ok {
+			t.Errorf("no property %q", name)
+			continue
+		}
+		if p["type"] != want {
+			t.Errorf("%s type = %v, want %v", name, p["type"], want)
+		}
+	}
+
+	series := props["series"].(map[string]any)["items"].(map[string]any)
+	if _, ok := series["properties"].(map[string]any)["name"]; !ok {
+		t.Errorf("series items have no name property: %v", series)
+	}
+
+	required := s["required"].([]string)
+	for _, name := range []string{"id", "title", "author", "url"} {
+		if !slices.Contains(required, name) {
+			t.Errorf("%q not required: %v", name, required)
+		}
+	}
+	for _, name := range []string{"isbn13", "pages", "genres"} {
+		if slices.Contains(required, name) {
+			t.Errorf("omitempty field %q required", name)
+		}
+	}
+}
+
+func TestFor_TagsAndNullables(t *testing.T) {
+	type inner struct {
+		Shared string `json:"shared"`
+	}
+	type args struct {
+		inner
+		Query  string         `json:"query" desc:"what to look for"`
+		Limit  int            `json:"limit,omitempty"`
+		Tags   []string       `json:"tags"`
+		Extra  map[string]int `json:"extra,omitempty"`
+		Hidden string         `json:"-"`
+		secret string
+	}
+	s := For(reflect.TypeFor[args]())
+	props := s["properties"].(map[string]any)
+
+	want := map[string]any{
+		"shared": map[string]any{"type": "string"},
+		"query":  map[string]any{"type": "string", "description": "what to look for"},
+		"limit":  map[string]any{"type": "integer"},
+		"tags":   map[string]any{"type": []any{"array", "null"}, "items": map[string]any{"type": "string"}},
+		"extra":  map[string]any{"type": "object", "additionalProperties": map[string]any{"type": "integer"}},
+	}
+	if !reflect.DeepEqual(props, want) {
+		t.Errorf("properties = %v\nwant %v", props, want)
+	}
+	if got := s["required"]; !reflect.DeepEqual(got, []string{"shared", "query", "tags"}) {
+		t.Errorf("required = %v", got)
+	}
+}
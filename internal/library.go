@@ -0,0 +1,401 @@
+package internal
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite" // registers the "sqlite" database/sql driver
+)
+
+// Library is the local SQLite mirror of the user's shelves that
+// `goodreads sync` maintains, so read commands can work without a browser.
+// Each book row keeps the whole ShelfEntry as JSON next to the columns
+// queries filter and sort on.
+type Library struct {
+	db *sql.DB
+}
+
+// libraryVersion is the schema version kept in PRAGMA user_version.
+const libraryVersion = 1
+
+const librarySchema = `
+CREATE TABLE IF NOT EXISTS books (
+	id           TEXT PRIMARY KEY,
+	title        TEXT NOT NULL,
+	author       TEXT NOT NULL,
+	isbn         TEXT NOT NULL,
+	isbn13       TEXT NOT NULL,
+	pages        INTEGER NOT NULL,
+	my_rating    INTEGER NOT NULL,
+	review       TEXT NOT NULL,
+	date_started TEXT NOT NULL,
+	date_read    TEXT NOT NULL,
+	date_added   TEXT NOT NULL,
+	entry        TEXT NOT NULL -- the ShelfEntry as JSON
+);
+CREATE TABLE IF NOT EXISTS shelves (
+	book_id TEXT NOT NULL REFERENCES books(id) ON DELETE CASCADE,
+	shelf   TEXT NOT NULL,
+	PRIMARY KEY (book_id, shelf)
+);
+CREATE INDEX IF NOT EXISTS shelves_shelf ON shelves(shelf);
+CREATE TABLE IF NOT EXISTS meta (
+	key   TEXT PRIMARY KEY,
+	value TEXT NOT NULL
+);
+`
+
+// LibraryPath is where the library database lives by default, next to
+// the config and session files.
+func LibraryPath() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".goodreads-cli-library.db")
+}
+
+// OpenLibrary opens the library database at path, creating it if needed.
+func OpenLibrary(path string) (*Library, error) {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, err
+		}
+	}
+	db, err := sql.Open("sqlite", "file:"+path+"?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)")
+	if err != nil {
+		return nil, err
+	}
+	// One connection keeps the pragmas and transactions simple; the CLI
+	// never needs more.
+	db.SetMaxOpenConns(1)
+
+	var version int
+	if err := db.QueryRow(`PRAGMA user_version`).Scan(&version); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("opening library %s: %w", path, err)
+	}
+	if version > libraryVersion {
+		db.Close()
+		return nil, fmt.Errorf("library %s was written by a newer goodreads (schema %d) — upgrade, or delete it and sync again", path, version)
+	}
+	if _, err := db.Exec(librarySchema + fmt.Sprintf("PRAGMA user_version = %d;", libraryVersion)); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("creating library %s: %w", path, err)
+	}
+	return &Library{db: db}, nil
+}
+
+// Close closes the database.
+func (l *Library) Close() error {
+	return l.db.Close()
+}
+
+// LibrarySyncResult counts what a sync changed.
+type LibrarySyncResult struct {
+	Added     int `json:"added"`
+	Updated   int `json:"updated"`
+	Unchanged int `json:"unchanged"`
+	Removed   int `json:"removed"`
+}
+
+// errSyncCaughtUp stops an incremental sync's shelf walk early.
+var errSyncCaughtUp = errors.New("library is up to date")
+
+// Sync brings the library up to date from the user's "#ALL#" shelf.
+// walk lists that shelf page by page — Browser.ListShelfPages in the CLI.
+//
+// Rows are read most recently updated first. An incremental sync (full
+// false) stops at the first page on which nothing changed, since
+// everything older is already stored; it can't see books removed from
+// every shelf, which a full sync deletes. The first sync is always full.
+func (l *Library) Sync(walk func(ShelfListOptions, func([]ShelfEntry) error) error, full bool) (LibrarySyncResult, error) {
+	var res LibrarySyncResult
+	if _, synced, err := l.LastSync(); err != nil {
+		return res, err
+	} else if !synced {
+		full = true
+	}
+
+	seen := map[string]bool{}
+	err := walk(ShelfListOptions{Sort: "date_updated"}, func(page []ShelfEntry) error {
+		changed, err := l.storePage(page, &res)
+		if err != nil {
+			return err
+		}
+		for _, e := range page {
+			seen[e.ID] = true
+		}
+		if !full && !changed {
+			return errSyncCaughtUp
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errSyncCaughtUp) {
+		return res, err
+	}
+
+	if full {
+		n, err := l.removeUnseen(seen)
+		if err != nil {
+			return res, err
+		}
+		res.Removed = n
+	}
+	_, err = l.db.Exec(`INSERT INTO meta (key, value) VALUES ('last_sync', ?)
+		ON CONFLICT (key) DO UPDATE SET value = excluded.value`, time.Now().UTC().Format(time.RFC3339))
+	return res, err
+}
+
+// storePage upserts one page of entries in a transaction. changed is
+// false when no entry is new or differs in what the user set (see
+// userFields): the average rating and other book details drift without
+// moving a book up the date_updated order, so they are stored but don't
+// keep an incremental sync going.
+func (l *Library) storePage(page []ShelfEntry, res *LibrarySyncResult) (changed bool, err error) {
+	tx, err := l.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	for _, e := range page {
+		data, err := json.Marshal(e)
+		if err != nil {
+			return false, err
+		}
+		var old string
+		err = tx.QueryRow(`SELECT entry FROM books WHERE id = ?`, e.ID).Scan(&old)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			res.Added++
+			changed = true
+		case err != nil:
+			return false, err
+		case old == string(data):
+			res.Unchanged++
+			continue
+		default:
+			var prev ShelfEntry
+			if err := json.Unmarshal([]byte(old), &prev); err != nil {
+				return false, fmt.Errorf("reading stored book %s: %w", e.ID, err)
+			}
+			if reflect.DeepEqual(userFields(prev), userFields(e)) {
+				res.Unchanged++
+			} else {
+				res.Updated++
+				changed = true
+			}
+		}
+
+		if _, err := tx.Exec(`INSERT INTO books
+			(id, title, author, isbn, isbn13, pages, my_rating, review, date_started, date_read, date_added, entry)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT (id) DO UPDATE SET
+				title = excluded.title, author = excluded.author, isbn = excluded.isbn,
+				isbn13 = excluded.isbn13, pages = excluded.pages, my_rating = excluded.my_rating,
+				review = excluded.review, date_started = excluded.date_started,
+				date_read = excluded.date_read, date_added = excluded.date_added, entry = excluded.entry`,
+			e.ID, e.Title, e.Author, e.ISBN, e.ISBN13, e.Pages, e.MyRating, e.Review,
+			e.DateStarted, e.DateRead, e.DateAdded, string(data)); err != nil {
+			return false, fmt.Errorf("storing book %s: %w", e.ID, err)
+		}
+		if _, err := tx.Exec(`DELETE FROM shelves WHERE book_id = ?`, e.ID); err != nil {
+			return false, err
+		}
+		for _, s := range e.Shelves {
+			if _, err := tx.Exec(`INSERT OR IGNORE INTO shelves (book_id, shelf) VALUES (?, ?)`, e.ID, canonicalShelfName(s)); err != nil {
+				return false, err
+			}
+		}
+	}
+	return changed, tx.Commit()
+}
+
+// userFields is the part of e the user sets on Goodreads; editing any of
+// it bumps the book's date_updated.
+func userFields(e ShelfEntry) ShelfEntry {
+	return ShelfEntry{
+		ReviewID:    e.ReviewID,
+		MyRating:    e.MyRating,
+		Shelves:     e.Shelves,
+		Review:      e.Review,
+		ReadCount:   e.ReadCount,
+		DateStarted: e.DateStarted,
+		DateRead:    e.DateRead,
+		DateAdded:   e.DateAdded,
+		Reads:       e.Reads,
+	}
+}
+
+// removeUnseen deletes the books a full sync didn't find on any shelf.
+func (l *Library) removeUnseen(seen map[string]bool) (int, error) {
+	rows, err := l.db.Query(`SELECT id FROM books`)
+	if err != nil {
+		return 0, err
+	}
+	var gone []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		if !seen[id] {
+			gone = append(gone, id)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+	for _, id := range gone {
+		if _, err := l.db.Exec(`DELETE FROM books WHERE id = ?`, id); err != nil {
+			return 0, err
+		}
+	}
+	return len(gone), nil
+}
+
+// LastSync returns when the library was last synced; ok is false if it
+// never has been.
+func (l *Library) LastSync() (t time.Time, ok bool, err error) {
+	var v string
+	err = l.db.QueryRow(`SELECT value FROM meta WHERE key = 'last_sync'`).Scan(&v)
+	if errors.Is(err, sql.ErrNoRows) {
+		return time.Time{}, false, nil
+	}
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	t, err = time.Parse(time.RFC3339, v)
+	return t, err == nil, err
+}
+
+// Shelf returns the stored books on a shelf, most recently added first.
+// "#ALL#" returns every book.
+func (l *Library) Shelf(name string) ([]ShelfEntry, error) {
+	if name == "#ALL#" {
+		return l.queryEntries(`SELECT entry FROM books ORDER BY date_added DESC, id`)
+	}
+	return l.queryEntries(`SELECT b.entry FROM books b JOIN shelves s ON s.book_id = b.id
+		WHERE s.shelf = ? ORDER BY b.date_added DESC, b.id`, canonicalShelfName(name))
+}
+
+// Search returns the stored books whose title, author or ISBN contains
+// every word of query, ignoring case.
+func (l *Library) Search(query string) ([]ShelfEntry, error) {
+	words := strings.Fields(query)
+	if len(words) == 0 {
+		return []ShelfEntry{}, nil
+	}
+	var where []string
+	var args []any
+	for _, w := range words {
+		where = append(where, `(title || ' ' || author || ' ' || isbn || ' ' || isbn13) LIKE ? ESCAPE '\'`)
+		args = append(args, "%"+_likeEscaper.Replace(w)+"%")
+	}
+	return l.queryEntries(`SELECT entry FROM books WHERE `+strings.Join(where, " AND ")+
+		` ORDER BY date_added DESC, id`, args...)
+}
+
+var _likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func (l *Library) queryEntries(query string, args ...any) ([]ShelfEntry, error) {
+	rows, err := l.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	entries := []ShelfEntry{}
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+		var e ShelfEntry
+		if err := json.Unmarshal([]byte(data), &e); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+// LibraryStats summarises the library.
+type LibraryStats struct {
+	Books         int          `json:"books"`
+	Shelves       []ShelfCount `json:"shelves"` // largest first
+	Rated         int          `json:"rated"`
+	AverageRating float64      `json:"average_rating"` // of your own ratings
+	ReadPerYear   []YearCount  `json:"read_per_year"`  // by date read, newest first
+	LastSync      time.Time    `json:"last_sync"`
+}
+
+// ShelfCount is the number of books on one shelf.
+type ShelfCount struct {
+	Shelf string `json:"shelf"`
+	Books int    `json:"books"`
+}
+
+// YearCount is the number of books and pages finished in one year.
+type YearCount struct {
+	Year  string `json:"year"`
+	Books int    `json:"books"`
+	Pages int    `json:"pages"`
+}
+
+// Stats computes LibraryStats from the stored books.
+func (l *Library) Stats() (LibraryStats, error) {
+	var st LibraryStats
+	var avg sql.NullFloat64
+	if err := l.db.QueryRow(`SELECT COUNT(*), COUNT(NULLIF(my_rating, 0)), AVG(NULLIF(my_rating, 0)) FROM books`).
+		Scan(&st.Books, &st.Rated, &avg); err != nil {
+		return st, err
+	}
+	st.AverageRating = avg.Float64
+
+	rows, err := l.db.Query(`SELECT shelf, COUNT(*) FROM shelves GROUP BY shelf ORDER BY COUNT(*) DESC, shelf`)
+	if err != nil {
+		return st, err
+	}
+	st.Shelves = []ShelfCount{}
+	for rows.Next() {
+		var c ShelfCount
+		if err := rows.Scan(&c.Shelf, &c.Books); err != nil {
+			rows.Close()
+			return st, err
+		}
+		st.Shelves = append(st.Shelves, c)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return st, err
+	}
+
+	rows, err = l.db.Query(`SELECT substr(date_read, 1, 4) AS year, COUNT(*), SUM(pages) FROM books
+		WHERE date_read != '' GROUP BY year ORDER BY year DESC`)
+	if err != nil {
+		return st, err
+	}
+	st.ReadPerYear = []YearCount{}
+	for rows.Next() {
+		var c YearCount
+		if err := rows.Scan(&c.Year, &c.Books, &c.Pages); err != nil {
+			rows.Close()
+			return st, err
+		}
+		st.ReadPerYear = append(st.ReadPerYear, c)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return st, err
+	}
+
+	st.LastSync, _, err = l.LastSync()
+	return st, err
+}
@@ -0,0 +1,179 @@
+package internal
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func openTestLibrary(t *testing.T) *Library {
+	t.Helper()
+	lib, err := OpenLibrary(filepath.Join(t.TempDir(), "library.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { lib.Close() })
+	return lib
+}
+
+// walkFake returns a Library.Sync walk over f's #ALL# shelf.
+func walkFake(f *fakeShelf) func(ShelfListOptions, func([]ShelfEntry) error) error {
+	return func(opts ShelfListOptions, fn func([]ShelfEntry) error) error {
+		return walkShelf(f.fetch, "1", "#ALL#", opts, fn)
+	}
+}
+
+func TestLibrary_SyncIsIncremental(t *testing.T) {
+	lib := openTestLibrary(t)
+
+	f := &fakeShelf{n: 250}
+	res, err := lib.Sync(walkFake(f), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res != (LibrarySyncResult{Added: 250}) {
+		t.Errorf("first sync = %+v", res)
+	}
+	if !reflect.DeepEqual(f.pages, []int{1, 2, 3}) {
+		t.Errorf("first sync fetched pages %v, want all three", f.pages)
+	}
+
+	// Nothing changed: one page is enough to see that.
+	f.pages = nil
+	res, err = lib.Sync(walkFake(f), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res != (LibrarySyncResult{Unchanged: 100}) || !reflect.DeepEqual(f.pages, []int{1}) {
+		t.Errorf("incremental sync = %+v over pages %v", res, f.pages)
+	}
+
+	// A full sync walks everything and drops books no longer shelved.
+	f = &fakeShelf{n: 240}
+	res, err = lib.Sync(walkFake(f), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res != (LibrarySyncResult{Unchanged: 240, Removed: 10}) {
+		t.Errorf("full sync = %+v", res)
+	}
+	if _, ok, _ := lib.LastSync(); !ok {
+		t.Error("last sync time not recorded")
+	}
+}
+
+// TestLibrary_SyncIgnoresRatingDrift: the average rating changes without
+// bumping date_updated, so it mustn't make an incremental sync read on.
+func TestLibrary_SyncIgnoresRatingDrift(t *testing.T) {
+	lib := openTestLibrary(t)
+	entry := ShelfEntry{Book: Book{ID: "2", Title: "Dune", Rating: "4.27", RatingsCount: 1000}, MyRating: 5, Shelves: []string{"read"}}
+	sync := func(e ShelfEntry) (LibrarySyncResult, int) {
+		t.Helper()
+		pages := 0
+		res, err := lib.Sync(func(_ ShelfListOptions, fn func([]ShelfEntry) error) error {
+			for range 2 {
+				pages++
+				if err := fn([]ShelfEntry{e}); err != nil {
+					return err
+				}
+			}
+			return nil
+		}, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res, pages
+	}
+	sync(entry)
+
+	entry.Rating, entry.RatingsCount = "4.28", 1001
+	if res, pages := sync(entry); res != (LibrarySyncResult{Unchanged: 1}) || pages != 1 {
+		t.Errorf("rating-only change: %+v over %d pages, want 1 unchanged on the first page", res, pages)
+	}
+	if got, _ := lib.Shelf("#ALL#"); len(got) != 1 || got[0].Rating != "4.28" {
+		t.Errorf("stored entry %+v, want the new average rating", got)
+	}
+
+	entry.MyRating = 4
+	if res, pages := sync(entry); res.Updated != 1 || pages != 2 {
+		t.Errorf("my_rating change: %+v over %d pages, want it updated and the walk continued", res, pages)
+	}
+}
+
+func TestLibrary_Queries(t *testing.T) {
+	lib := openTestLibrary(t)
+	entries := []ShelfEntry{
+		{Book: Book{ID: "1", Title: "Dune", Author: "Herbert, Frank", ISBN13: "9780441172719", Pages: 412},
+			MyRating: 5, Shelves: []string{"read", "sci-fi"}, DateRead: "2025-03-01", DateAdded: "2025-01-01"},
+		{Book: Book{ID: "2", Title: "Dune Messiah", Author: "Herbert, Frank", Pages: 256},
+			MyRating: 3, Shelves: []string{"read"}, DateRead: "2026-01-10", DateAdded: "2025-06-01"},
+		{Book: Book{ID: "3", Title: "Piranesi", Author: "Clarke, Susanna"},
+			Shelves: []string{"to-read"}, DateAdded: "2026-02-01"},
+	}
+	walk := func(_ ShelfListOptions, fn func([]ShelfEntry) error) error { return fn(entries) }
+	if _, err := lib.Sync(walk, true); err != nil {
+		t.Fatal(err)
+	}
+
+	ids := func(es []ShelfEntry) []string {
+		out := []string{}
+		for _, e := range es {
+			out = append(out, e.ID)
+		}
+		return out
+	}
+	for _, tt := range []struct {
+		shelf string
+		want  []string
+	}{
+		{"read", []string{"2", "1"}},
+		{"want-to-read", []string{"3"}},
+		{"to-read", []string{"3"}},
+		{"#ALL#", []string{"3", "2", "1"}},
+		{"currently-reading", []string{}},
+	} {
+		got, err := lib.Shelf(tt.shelf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(ids(got), tt.want) {
+			t.Errorf("Shelf(%q) = %v, want %v", tt.shelf, ids(got), tt.want)
+		}
+	}
+
+	got, _ := lib.Shelf("sci-fi")
+	if len(got) != 1 || !reflect.DeepEqual(got[0], entries[0]) {
+		t.Errorf("stored entry = %+v, want %+v", got, entries[0])
+	}
+
+	for query, want := range map[string][]string{
+		"dune":            {"2", "1"},
+		"herbert messiah": {"2"},
+		"9780441172719":   {"1"},
+		"100%":            {},
+	} {
+		got, err := lib.Search(query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(ids(got), want) {
+			t.Errorf("Search(%q) = %v, want %v", query, ids(got), want)
+		}
+	}
+
+	st, err := lib.Stats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.Books != 3 || st.Rated != 2 || st.AverageRating != 4 {
+		t.Errorf("stats = %+v", st)
+	}
+	wantShelves := []ShelfCount{{"read", 2}, {"sci-fi", 1}, {"want-to-read", 1}}
+	if !reflect.DeepEqual(st.Shelves, wantShelves) {
+		t.Errorf("shelves = %+v, want %+v", st.Shelves, wantShelves)
+	}
+	wantYears := []YearCount{{"2026", 1, 256}, {"2025", 1, 412}}
+	if !reflect.DeepEqual(st.ReadPerYear, wantYears) {
+		t.Errorf("read per year = %+v, want %+v", st.ReadPerYear, wantYears)
+	}
+}
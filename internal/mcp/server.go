@@ -0,0 +1,228 @@
+// Package mcp runs goodreads-cli as a Model Context Protocol server over
+// stdio, so AI agents can call its operations as typed tools instead of
+// shelling out and parsing tables. Only the parts of MCP a tool server
+// needs are implemented: initialize, ping, tools/list and tools/call.
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+
+	"github.com/yareeh/goodreads-cli/internal"
+	"github.com/yareeh/goodreads-cli/internal/jsonschema"
+)
+
+// ProtocolVersions are the MCP revisions the server speaks, newest first.
+var ProtocolVersions = []string{"2025-06-18", "2025-03-26", "2024-11-05"}
+
+// JSON-RPC error codes.
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+	codeInternalError  = -32603
+)
+
+// Tool is one callable tool. Call gets the raw arguments object and
+// returns the result, which is sent as both structured content and text.
+type Tool struct {
+	Name         string         `json:"name"`
+	Description  string         `json:"description"`
+	InputSchema  map[string]any `json:"inputSchema"`
+	OutputSchema map[string]any `json:"outputSchema,omitempty"`
+
+	Call func(args json.RawMessage) (any, error) `json:"-"`
+}
+
+// NewTool makes a Tool whose input and output schemas are derived from
+// In and Out, which must be structs. Arguments are decoded into an In;
+// missing required arguments are rejected before fn is called.
+func NewTool[In, Out any](name, description string, fn func(In) (Out, error)) Tool {
+	in := jsonschema.For(reflect.TypeFor[In]())
+	required, _ := in["required"].([]string)
+	return Tool{
+		Name:         name,
+		Description:  description,
+		InputSchema:  in,
+		OutputSchema: jsonschema.For(reflect.TypeFor[Out]()),
+		Call: func(raw json.RawMessage) (any, error) {
+			if len(raw) == 0 || string(raw) == "null" {
+				raw = json.RawMessage("{}")
+			}
+			var present map[string]json.RawMessage
+			if err := json.Unmarshal(raw, &present); err != nil {
+				return nil, &rpcError{codeInvalidParams, "arguments must be an object: " + err.Error()}
+			}
+			for _, r := range required {
+				if _, ok := present[r]; !ok {
+					return nil, &rpcError{codeInvalidParams, fmt.Sprintf("missing required argument %q", r)}
+				}
+			}
+			var args In
+			if err := json.Unmarshal(raw, &args); err != nil {
+				return nil, &rpcError{codeInvalidParams, "invalid arguments: " + err.Error()}
+			}
+			return fn(args)
+		},
+	}
+}
+
+// Server answers MCP requests for a set of tools.
+type Server struct {
+	Name    string
+	Version string
+	Tools   []Tool
+	// Reset, if set, is called after a tool panics, to drop state such
+	// as a browser left on a half-loaded page.
+	Reset func()
+}
+
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *rpcError) Error() string { return e.Message }
+
+// Serve reads newline-delimited JSON-RPC messages from r and writes the
+// responses to w, one per line, until r ends. Requests are handled one at
+// a time, in order.
+func (s *Server) Serve(r io.Reader, w io.Writer) error {
+	in := bufio.NewReader(r)
+	enc := json.NewEncoder(w)
+	for {
+		line, err := in.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			if resp := s.handle(line); resp != nil {
+				if err := enc.Encode(resp); err != nil {
+					return err
+				}
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// handle answers one message; notifications get no response.
+func (s *Server) handle(line []byte) *response {
+	var req request
+	if err := json.Unmarshal(line, &req); err != nil {
+		return &response{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: &rpcError{codeParseError, "parse error: " + err.Error()}}
+	}
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		if req.ID == nil {
+			return nil
+		}
+		return &response{JSONRPC: "2.0", ID: req.ID, Error: &rpcError{codeInvalidRequest, "invalid request"}}
+	}
+	if req.ID == nil {
+		return nil // notifications/initialized, notifications/cancelled, …
+	}
+
+	result, err := s.dispatch(req.Method, req.Params)
+	resp := &response{JSONRPC: "2.0", ID: req.ID, Result: result}
+	if err != nil {
+		var re *rpcError
+		if !errors.As(err, &re) {
+			re = &rpcError{codeInternalError, err.Error()}
+		}
+		resp.Result, resp.Error = nil, re
+	}
+	return resp
+}
+
+func (s *Server) dispatch(method string, params json.RawMessage) (any, error) {
+	switch method {
+	case "initialize":
+		var p struct {
+			ProtocolVersion string `json:"protocolVersion"`
+		}
+		json.Unmarshal(params, &p)
+		version := ProtocolVersions[0]
+		if slices.Contains(ProtocolVersions, p.ProtocolVersion) {
+			version = p.ProtocolVersion
+		}
+		return map[string]any{
+			"protocolVersion": version,
+			"capabilities":    map[string]any{"tools": map[string]any{}},
+			"serverInfo":      map[string]any{"name": s.Name, "version": s.Version},
+		}, nil
+	case "ping":
+		return map[string]any{}, nil
+	case "tools/list":
+		return map[string]any{"tools": s.Tools}, nil
+	case "tools/call":
+		return s.callTool(params)
+	}
+	return nil, &rpcError{codeMethodNotFound, "method not found: " + method}
+}
+
+// call runs t. A panic is returned as an error rather than ending the
+// session, and Reset gives the next call a clean start.
+func (s *Server) call(t Tool, args json.RawMessage) (any, error) {
+	return internal.Guard(func() (any, error) { return t.Call(args) }, s.Reset)
+}
+
+// callTool runs a tool. Failures of the tool itself are results with
+// isError set, so the model sees them; only an unknown tool or bad
+// arguments are protocol errors.
+func (s *Server) callTool(params json.RawMessage) (any, error) {
+	var p struct {
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"`
+	}
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, &rpcError{codeInvalidParams, "invalid params: " + err.Error()}
+	}
+	i := slices.IndexFunc(s.Tools, func(t Tool) bool { return t.Name == p.Name })
+	if i < 0 {
+		return nil, &rpcError{codeInvalidParams, "unknown tool: " + p.Name}
+	}
+
+	out, err := s.call(s.Tools[i], p.Arguments)
+	var re *rpcError
+	if errors.As(err, &re) {
+		return nil, re
+	}
+	if err != nil {
+		return map[string]any{
+			"content": []any{map[string]any{"type": "text", "text": err.Error()}},
+			"isError": true,
+		}, nil
+	}
+	text, err := json.Marshal(out)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{
+		"content":           []any{map[string]any{"type": "text", "text": string(text)}},
+		"structuredContent": out,
+		"isError":           false,
+	}, nil
+}
@@ -0,0 +1,189 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type echoArgs struct {
+	Text  string `json:"text"`
+	Times int    `json:"times,omitempty"`
+}
+
+type echoResult struct {
+	Echo string `json:"echo"`
+}
+
+// resets counts calls to the test server's Reset.
+var resets int
+
+func testServer() *Server {
+	return &Server{
+		Reset:   func() { resets++ },
+		Name:    "goodreads-cli",
+		Version: "test",
+		Tools: []Tool{
+			NewTool("echo", "Repeat text.", func(a echoArgs) (echoResult, error) {
+				if a.Text == "panic" {
+					panic("navigation timed out")
+				}
+				if a.Text == "fail" {
+					return echoResult{}, fmt.Errorf("not logged in")
+				}
+				return echoResult{Echo: strings.Repeat(a.Text, max(1, a.Times))}, nil
+			}),
+		},
+	}
+}
+
+// exchange sends messages to a test server and returns its responses.
+func exchange(t *testing.T, messages ...string) []map[string]any {
+	t.Helper()
+	var out bytes.Buffer
+	in := strings.Join(messages, "\n") // the last message has no newline
+	if err := testServer().Serve(strings.NewReader(in), &out); err != nil {
+		t.Fatal(err)
+	}
+	var resps []map[string]any
+	dec := json.NewDecoder(&out)
+	for dec.More() {
+		var r map[string]any
+		if err := dec.Decode(&r); err != nil {
+			t.Fatal(err)
+		}
+		resps = append(resps, r)
+	}
+	return resps
+}
+
+func TestServe_Initialize(t *testing.T) {
+	resps := exchange(t,
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","capabilities":{},"clientInfo":{"name":"t","version":"1"}}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`{"jsonrpc":"2.0","id":"two","method":"ping"}`,
+	)
+	if len(resps) != 2 {
+		t.Fatalf("got %d responses, want 2 (none for the notification): %v", len(resps), resps)
+	}
+	res := resps[0]["result"].(map[string]any)
+	if res["protocolVersion"] != "2025-03-26" {
+		t.Errorf("protocolVersion = %v, want the client's", res["protocolVersion"])
+	}
+	if info := res["serverInfo"].(map[string]any); info["name"] != "goodreads-cli" || info["version"] != "test" {
+		t.Errorf("serverInfo = %v", info)
+	}
+	if resps[1]["id"] != "two" || resps[1]["result"] == nil {
+		t.Errorf("ping response = %v", resps[1])
+	}
+}
+
+func TestServe_InitializeUnknownVersion(t *testing.T) {
+	resps := exchange(t, `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"1999-01-01"}}`)
+	if got := resps[0]["result"].(map[string]any)["protocolVersion"]; got != ProtocolVersions[0] {
+		t.Errorf("protocolVersion = %v, want %s", got, ProtocolVersions[0])
+	}
+}
+
+func TestServe_ToolsList(t *testing.T) {
+	resps := exchange(t, `{"jsonrpc":"2.0","id":1,"method":"tools/list"}`)
+	tools := resps[0]["result"].(map[string]any)["tools"].([]any)
+	if len(tools) != 1 {
+		t.Fatalf("got %d tools, want 1", len(tools))
+	}
+	tool := tools[0].(map[string]any)
+	if tool["name"] != "echo" {
+		t.Errorf("name = %v", tool["name"])
+	}
+	schema := tool["inputSchema"].(map[string]any)
+	if req := schema["required"].([]any); len(req) != 1 || req[0] != "text" {
+		t.Errorf("required = %v, want [text]", req)
+	}
+	if tool["outputSchema"] == nil {
+		t.Error("no outputSchema")
+	}
+}
+
+func TestServe_ToolsCall(t *testing.T) {
+	resps := exchange(t, `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"echo","arguments":{"text":"ab","times":2}}}`)
+	res := resps[0]["result"].(map[string]any)
+	if res["isError"] != false {
+		t.Errorf("isError = %v", res["isError"])
+	}
+	if got := res["structuredContent"].(map[string]any)["echo"]; got != "abab" {
+		t.Errorf("structuredContent.echo = %v, want abab", got)
+	}
+	text := res["content"].([]any)[0].(map[string]any)["text"]
+	if text != `{"echo":"abab"}` {
+		t.Errorf("text content = %v", text)
+	}
+}
+
+func TestServe_ToolError(t *testing.T) {
+	resps := exchange(t, `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"echo","arguments":{"text":"fail"}}}`)
+	if resps[0]["error"] != nil {
+		t.Fatalf("tool failure sent as protocol error: %v", resps[0])
+	}
+	res := resps[0]["result"].(map[string]any)
+	if res["isError"] != true {
+		t.Errorf("isError = %v, want true", res["isError"])
+	}
+	if text := res["content"].([]any)[0].(map[string]any)["text"]; text != "not logged in" {
+		t.Errorf("text = %v", text)
+	}
+}
+
+func TestServe_ToolPanic(t *testing.T) {
+	resets = 0
+	resps := exchange(t,
+		`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"echo","arguments":{"text":"panic"}}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"echo","arguments":{"text":"ok"}}}`,
+	)
+	if len(resps) != 2 {
+		t.Fatalf("got %d responses, want 2: %v", len(resps), resps)
+	}
+	res := resps[0]["result"].(map[string]any)
+	if res["isError"] != true {
+		t.Errorf("isError = %v, want true", res["isError"])
+	}
+	if text := res["content"].([]any)[0].(map[string]any)["text"]; text != "internal error: navigation timed out" {
+		t.Errorf("text = %v", text)
+	}
+	if resets != 1 {
+		t.Errorf("Reset called %d times, want 1", resets)
+	}
+	if res := resps[1]["result"].(map[string]any); res["isError"] != false {
+		t.Errorf("call after the panic failed: %v", res)
+	}
+}
+
+func TestServe_Errors(t *testing.T) {
+	tests := []struct {
+		name, msg string
+		code      float64
+	}{
+		{"parse error", `{"jsonrpc":`, codeParseError},
+		{"unknown method", `{"jsonrpc":"2.0","id":1,"method":"resources/list"}`, codeMethodNotFound},
+		{"unknown tool", `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"nope"}}`, codeInvalidParams},
+		{"missing argument", `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"echo","arguments":{"times":2}}}`, codeInvalidParams},
+		{"bad argument", `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"echo","arguments":{"text":3}}}`, codeInvalidParams},
+		{"not json-rpc 2.0", `{"id":1,"method":"ping"}`, codeInvalidRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resps := exchange(t, tt.msg)
+			if len(resps) != 1 {
+				t.Fatalf("got %d responses, want 1", len(resps))
+			}
+			e, ok := resps[0]["error"].(map[string]any)
+			if !ok {
+				t.Fatalf("no error in %v", resps[0])
+			}
+			if e["code"] != tt.code {
+				t.Errorf("code = %v, want %v (%v)", e["code"], tt.code, e["message"])
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package mcp
+
+import "github.com/yareeh/goodreads-cli/internal"
+
+// tools are the Goodreads operations, run on one shared session.
+type tools struct {
+	s *internal.Session
+}
+
+type searchArgs struct {
+	Query string `json:"query" desc:"title, author or ISBN to search for"`
+	Full  bool   `json:"full,omitempty" desc:"use the full results page: every match with ratings and year, instead of a handful of quick matches"`
+	Field string `json:"field,omitempty" desc:"with full: title, author or all (default)"`
+	Limit int    `json:"limit,omitempty" desc:"with full: return up to this many results, across pages"`
+}
+
+type booksResult struct {
+	Books []internal.Book `json:"books"`
+}
+
+type bookArgs struct {
+	Book string `json:"book" desc:"Goodreads book ID, ISBN-10/13, ASIN or goodreads.com book URL"`
+}
+
+type listShelfArgs struct {
+	Shelf string `json:"shelf" desc:"shelf name: want-to-read, currently-reading, read, a custom shelf, or #ALL#"`
+	Limit int    `json:"limit,omitempty" desc:"stop after this many books"`
+	Page  int    `json:"page,omitempty" desc:"start at this page of 100 books; without limit, fetch only that page"`
+}
+
+type shelfEntriesResult struct {
+	Books []internal.ShelfEntry `json:"books"`
+}
+
+type shelveArgs struct {
+	Book  string `json:"book" desc:"Goodreads book ID, ISBN-10/13, ASIN or goodreads.com book URL"`
+	Shelf string `json:"shelf,omitempty" desc:"shelf to add the book to (default want-to-read)"`
+}
+
+type shelveResult struct {
+	BookID string `json:"book_id"`
+	Shelf  string `json:"shelf"`
+}
+
+type progressArgs struct {
+	Book    string `json:"book" desc:"Goodreads book ID, ISBN-10/13, ASIN or goodreads.com book URL"`
+	Page    int    `json:"page,omitempty" desc:"page you're on; give page or percent"`
+	Percent int    `json:"percent,omitempty" desc:"percentage read, 0-100; give page or percent"`
+	Comment string `json:"comment,omitempty" desc:"comment posted with the update"`
+}
+
+type progressResult struct {
+	BookID  string `json:"book_id"`
+	Page    int    `json:"page,omitempty"`
+	Percent int    `json:"percent,omitempty"`
+}
+
+type postReplyArgs struct {
+	TopicID  string `json:"topic_id" desc:"discussion topic ID, from goodreads.com/topic/show/<id>"`
+	Message  string `json:"message" desc:"reply text"`
+	Book     string `json:"book,omitempty" desc:"book to mention in the reply (ID, ISBN, ASIN or URL)"`
+	AuthorID string `json:"author_id,omitempty" desc:"author ID to mention in the reply"`
+}
+
+type postReplyResult struct {
+	TopicID string `json:"topic_id"`
+	Posted  bool   `json:"posted"`
+}
+
+// Tools returns the Goodreads tools, all running on session s, whose
+// browser they keep alive between calls. Calls must not overlap.
+func Tools(s *internal.Session) []Tool {
+	t := &tools{s}
+	return []Tool{
+		NewTool("search", "Search Goodreads for books. Returns book IDs for the other tools.", t.search),
+		NewTool("book_details", "Fetch a book's full record: ISBNs, publisher, year, pages, series, genres, ratings, awards.", t.bookDetails),
+		NewTool("list_shelf", "List the books on one of your shelves, with your rating, dates and review.", t.listShelf),
+		NewTool("shelve", "Add a book to one of your shelves. Putting a book on want-to-read, currently-reading or read moves it off the other two.", t.shelve),
+		NewTool("update_progress", "Post a reading-progress update (page or percent) for a book you're currently reading.", t.updateProgress),
+		NewTool("post_reply", "Reply to a Goodreads discussion topic, optionally mentioning a book or author.", t.postReply),
+	}
+}
+
+func (t *tools) search(a searchArgs) (booksResult, error) {
+	books, err := t.s.Search(a.Query, a.Full, internal.SearchOptions{Field: a.Field, Limit: a.Limit})
+	return booksResult{Books: nonNil(books)}, err
+}
+
+func (t *tools) bookDetails(a bookArgs) (internal.Book, error) {
+	return t.s.BookDetails(a.Book)
+}
+
+func (t *tools) listShelf(a listShelfArgs) (shelfEntriesResult, error) {
+	books, err := t.s.ListShelf(a.Shelf, internal.ShelfListOptions{Page: a.Page, Limit: a.Limit})
+	return shelfEntriesResult{Books: nonNil(books)}, err
+}
+
+func (t *tools) shelve(a shelveArgs) (shelveResult, error) {
+	if a.Shelf == "" {
+		a.Shelf = "want-to-read"
+	}
+	id, err := t.s.Shelve(a.Book, a.Shelf)
+	if err != nil {
+		return shelveResult{}, err
+	}
+	return shelveResult{BookID: id, Shelf: a.Shelf}, nil
+}
+
+func (t *tools) updateProgress(a progressArgs) (progressResult, error) {
+	update := internal.ProgressUpdate{Page: a.Page, Percent: a.Percent, Comment: a.Comment}
+	if err := update.Validate(); err != nil {
+		return progressResult{}, err
+	}
+	id, err := t.s.ResolveBook(a.Book)
+	if err != nil {
+		return progressResult{}, err
+	}
+	b, err := t.s.LoggedInBrowser()
+	if err != nil {
+		return progressResult{}, err
+	}
+	if err := internal.UpdateProgress(b, id, update); err != nil {
+		return progressResult{}, err
+	}
+	return progressResult{BookID: id, Page: a.Page, Percent: a.Percent}, nil
+}
+
+func (t *tools) postReply(a postReplyArgs) (postReplyResult, error) {
+	bookID := ""
+	if a.Book != "" {
+		var err error
+		if bookID, err = t.s.ResolveBook(a.Book); err != nil {
+			return postReplyResult{}, err
+		}
+	}
+	b, err := t.s.LoggedInBrowser()
+	if err != nil {
+		return postReplyResult{}, err
+	}
+	if err := internal.PostReply(b, a.TopicID, a.Message, bookID, a.AuthorID); err != nil {
+		return postReplyResult{}, err
+	}
+	return postReplyResult{TopicID: a.TopicID, Posted: true}, nil
+}
+
+// nonNil keeps an empty result an empty JSON array rather than null.
+func nonNil[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
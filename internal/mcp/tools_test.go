@@ -0,0 +1,34 @@
+package mcp
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/yareeh/goodreads-cli/internal"
+	"github.com/yareeh/goodreads-cli/internal/jsonschema"
+)
+
+func TestSessionTools(t *testing.T) {
+	tools := Tools(&internal.Session{})
+
+	var names []string
+	for _, tool := range tools {
+		names = append(names, tool.Name)
+	}
+	want := []string{"search", "book_details", "list_shelf", "shelve", "update_progress", "post_reply"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("tools = %v, want %v", names, want)
+	}
+
+	if got := tools[1].OutputSchema; !reflect.DeepEqual(got, jsonschema.For(reflect.TypeFor[internal.Book]())) {
+		t.Errorf("book_details output schema isn't internal.Book's: %v", got)
+	}
+}
+
+func TestUpdateProgress_ValidatesBeforeBrowser(t *testing.T) {
+	var tl tools // no session: must fail before needing one
+	_, err := tl.updateProgress(progressArgs{Book: "2", Page: 10, Percent: 20})
+	if err == nil {
+		t.Fatal("page and percent together accepted")
+	}
+}
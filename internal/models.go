@@ -4,6 +4,7 @@ type Book struct {
 	ID          string `json:"id"`
 	Title       string `json:"title"`
 	Author      string `json:"author"`
+	AuthorID    string `json:"author_id,omitempty"`
 	Rating      string `json:"rating"`
 	URL         string `json:"url"`
 	ImageURL    string `json:"image_url"`
@@ -21,11 +22,109 @@ type Book struct {
 	Pages         int    `json:"pages,omitempty"`
 	Language      string `json:"language,omitempty"`
 	Format        string `json:"format,omitempty"` // "Hardcover", "Paperback", "ebook", ...
+	RatingsCount  int    `json:"ratings_count,omitempty"`
+	ASIN          string `json:"asin,omitempty"`
+
+	// Work-level details populated by ParseBookDetailsFromHTML. Rating,
+	// RatingsCount and the statistics below are for the work (all
+	// editions together), as the book page shows them.
+	WorkID       string        `json:"work_id,omitempty"`
+	Series       []SeriesEntry `json:"series,omitempty"`
+	Genres       []string      `json:"genres,omitempty"` // most-shelved first
+	ReviewsCount int           `json:"reviews_count,omitempty"`
+	RatingDist   []int         `json:"rating_distribution,omitempty"` // ratings with 1..5 stars
+	Awards       []Award       `json:"awards,omitempty"`
+	Characters   []string      `json:"characters,omitempty"`
+	Places       []Place       `json:"places,omitempty"`
+}
+
+// Author is a Goodreads author page: the author's details and, when
+// requested, their books. Dates are YYYY-MM-DD when Goodreads gives a
+// full date, else as shown. Rating, RatingsCount and ReviewsCount are
+// across all the author's books.
+type Author struct {
+	ID           string   `json:"id"`
+	Name         string   `json:"name"`
+	URL          string   `json:"url"`
+	ImageURL     string   `json:"image_url,omitempty"`
+	Bio          string   `json:"bio,omitempty"`
+	BornAt       string   `json:"born_at,omitempty"`
+	BornIn       string   `json:"born_in,omitempty"`
+	DiedAt       string   `json:"died_at,omitempty"`
+	Website      string   `json:"website,omitempty"`
+	Genres       []string `json:"genres,omitempty"`
+	Rating       string   `json:"rating,omitempty"`
+	RatingsCount int      `json:"ratings_count,omitempty"`
+	ReviewsCount int      `json:"reviews_count,omitempty"`
+	WorksCount   int      `json:"works_count,omitempty"` // distinct works
+	Books        []Book   `json:"books,omitempty"`
+}
+
+// SeriesEntry places a book in a series. Position is a string because
+// Goodreads allows "0.5", "1-3" and the like.
+type SeriesEntry struct {
+	ID       string `json:"id,omitempty"`
+	Name     string `json:"name"`
+	Position string `json:"position,omitempty"`
+	URL      string `json:"url,omitempty"`
+}
+
+// Award is an award a work won or was nominated for.
+type Award struct {
+	Name        string `json:"name"`
+	Category    string `json:"category,omitempty"`
+	Year        int    `json:"year,omitempty"`
+	Designation string `json:"designation,omitempty"` // "WINNER", "NOMINEE", ...
+}
+
+// Place is a setting of a work.
+type Place struct {
+	Name    string `json:"name"`
+	Country string `json:"country,omitempty"`
+}
+
+// ShelfEntry is one row of a /review/list shelf page: the book plus the
+// logged-in user's own data for it. Book is embedded so the JSON stays a
+// flat object and existing consumers of `list-shelf --json` keep working;
+// Book.Rating is the Goodreads average, MyRating the user's stars.
+//
+// Dates are YYYY-MM-DD and empty when unset. Goodreads stores month-only
+// dates as the first of the month.
+type ShelfEntry struct {
+	Book
+	ReviewID     string           `json:"review_id"`
+	MyRating     int              `json:"my_rating"` // 1-5, 0 when unrated
+	Shelves      []string         `json:"shelves"`
+	Review       string           `json:"review,omitempty"`
+	ReadCount    int              `json:"read_count"`
+	DateStarted  string           `json:"date_started,omitempty"` // most recent reading session
+	DateRead     string           `json:"date_read,omitempty"`    // most recent reading session
+	DateAdded    string           `json:"date_added,omitempty"`
+	Reads        []ReadingSession `json:"reads,omitempty"`
+	OriginalYear string           `json:"original_year,omitempty"` // first publication, any edition
+}
+
+// ReadingSession is one read-through of a book, newest first in
+// ShelfEntry.Reads.
+type ReadingSession struct {
+	Started  string `json:"started,omitempty"`
+	Finished string `json:"finished,omitempty"`
 }
 
 type Shelf struct {
 	Name      string `json:"name"`
 	BookCount int    `json:"book_count"`
+	Exclusive bool   `json:"exclusive"` // a book sits on exactly one exclusive shelf
+}
+
+// Review is the logged-in user's rating and review of a book. Rating is
+// 1-5, or 0 when the book is unrated.
+type Review struct {
+	BookID   string `json:"book_id"`
+	ReviewID string `json:"review_id,omitempty"`
+	Rating   int    `json:"rating"`
+	Body     string `json:"body"`
+	Spoiler  bool   `json:"spoiler"`
 }
 
 type ReadingProgress struct {
@@ -0,0 +1,270 @@
+package internal
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// NoteFrontmatter is the YAML frontmatter `notes sync` keeps up to date
+// in each book's Markdown note. Other keys the user adds are left alone.
+type NoteFrontmatter struct {
+	ID       string   `yaml:"id"`
+	Title    string   `yaml:"title"`
+	Author   string   `yaml:"author"`
+	ISBN13   string   `yaml:"isbn13"`
+	Pages    int      `yaml:"pages"`
+	Shelves  []string `yaml:"shelves"`
+	Rating   int      `yaml:"rating"`
+	DateRead string   `yaml:"date_read"`
+	Cover    string   `yaml:"cover"`
+}
+
+// NewNoteFrontmatter combines a shelf entry with the book's details page:
+// the shelves, rating and read date come from the shelf, the rest from
+// details where it has them. details may be the zero Book.
+func NewNoteFrontmatter(e ShelfEntry, details Book) NoteFrontmatter {
+	pick := func(detail, shelf string) string {
+		if detail != "" {
+			return detail
+		}
+		return shelf
+	}
+	fm := NoteFrontmatter{
+		ID:       e.ID,
+		Title:    pick(details.Title, e.Title),
+		Author:   pick(details.Author, e.Author),
+		ISBN13:   pick(details.ISBN13, e.ISBN13),
+		Pages:    e.Pages,
+		Shelves:  e.Shelves,
+		Rating:   e.MyRating,
+		DateRead: e.DateRead,
+		Cover:    pick(details.ImageURL, e.ImageURL),
+	}
+	if details.Pages > 0 {
+		fm.Pages = details.Pages
+	}
+	if fm.Shelves == nil {
+		fm.Shelves = []string{}
+	}
+	return fm
+}
+
+// RenderNote returns note with its frontmatter set to fm. Only fm's keys
+// are written: other frontmatter keys, comments and the body after the
+// closing "---" are kept as they are. A note without frontmatter gets
+// some, and an empty note gets a title heading as its body.
+func RenderNote(note []byte, fm NoteFrontmatter) ([]byte, error) {
+	front, body, ok := splitFrontmatter(note)
+	if !ok {
+		body = note
+		front = nil
+	}
+	if len(bytes.TrimSpace(note)) == 0 {
+		body = []byte("# " + fm.Title + "\n")
+	}
+
+	var doc yaml.Node
+	if len(bytes.TrimSpace(front)) > 0 {
+		if err := yaml.Unmarshal(front, &doc); err != nil {
+			return nil, fmt.Errorf("reading frontmatter: %w", err)
+		}
+	}
+	if len(doc.Content) == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+	mapping := doc.Content[0]
+	if mapping.Kind != yaml.MappingNode {
+		return nil, Errorf(CodeInvalidInput, "frontmatter is not a YAML mapping")
+	}
+
+	var fresh yaml.Node
+	if err := fresh.Encode(fm); err != nil {
+		return nil, err
+	}
+	for i := 0; i+1 < len(fresh.Content); i += 2 {
+		setMappingValue(mapping, fresh.Content[i], fresh.Content[i+1])
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("---\n")
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	buf.WriteString("---\n")
+	buf.Write(body)
+	return buf.Bytes(), nil
+}
+
+// setMappingValue replaces the value of key in a YAML mapping, keeping
+// the key's position and comments, or appends the pair.
+func setMappingValue(mapping, key, value *yaml.Node) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key.Value {
+			value.LineComment = mapping.Content[i+1].LineComment
+			mapping.Content[i+1] = value
+			return
+		}
+	}
+	mapping.Content = append(mapping.Content, key, value)
+}
+
+// splitFrontmatter splits a note into its frontmatter (without the ---
+// fences) and body. ok is false when the note doesn't start with one.
+func splitFrontmatter(note []byte) (front, body []byte, ok bool) {
+	m := _frontmatterRE.FindSubmatchIndex(note)
+	if m == nil {
+		return nil, note, false
+	}
+	return note[m[2]:m[3]], note[m[1]:], true
+}
+
+// NotesSyncResult counts what SyncNote did across a sync.
+type NotesSyncResult struct {
+	Created   int `json:"created"`
+	Updated   int `json:"updated"`
+	Unchanged int `json:"unchanged"`
+}
+
+// NoteIndex maps book IDs to the notes in a directory, read from each
+// Markdown file's frontmatter `id`, so notes the user renamed are still
+// found.
+type NoteIndex struct {
+	dir   string
+	paths map[string]string // book ID → path
+	taken map[string]bool   // lower-cased file names in use
+}
+
+// LoadNoteIndex scans dir (not recursively) for notes, creating it if it
+// doesn't exist.
+func LoadNoteIndex(dir string) (*NoteIndex, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	idx := &NoteIndex{dir: dir, paths: map[string]string{}, taken: map[string]bool{}}
+	for _, de := range entries {
+		if de.IsDir() || !strings.HasSuffix(de.Name(), ".md") {
+			continue
+		}
+		idx.taken[strings.ToLower(de.Name())] = true
+		path := filepath.Join(dir, de.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		front, _, ok := splitFrontmatter(data)
+		if !ok {
+			continue
+		}
+		var fm struct {
+			ID any `yaml:"id"`
+		}
+		if yaml.Unmarshal(front, &fm) == nil && fm.ID != nil {
+			idx.paths[fmt.Sprint(fm.ID)] = path
+		}
+	}
+	return idx, nil
+}
+
+// Read returns the current frontmatter of a book's note, if it has one.
+func (idx *NoteIndex) Read(bookID string) (NoteFrontmatter, bool) {
+	path, ok := idx.paths[bookID]
+	if !ok {
+		return NoteFrontmatter{}, false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return NoteFrontmatter{}, false
+	}
+	front, _, ok := splitFrontmatter(data)
+	var fm NoteFrontmatter
+	if !ok || yaml.Unmarshal(front, &fm) != nil {
+		return NoteFrontmatter{}, false
+	}
+	return fm, true
+}
+
+// SyncNote writes fm into the book's note, creating the note as
+// "<title>.md" — or "<title> (<id>).md" when that name is taken — if
+// there is none. Files whose content wouldn't change are not touched.
+func (idx *NoteIndex) SyncNote(fm NoteFrontmatter, res *NotesSyncResult) error {
+	path, exists := idx.paths[fm.ID]
+	var old []byte
+	if exists {
+		var err error
+		if old, err = os.ReadFile(path); err != nil {
+			return err
+		}
+	} else {
+		path = idx.newNotePath(fm)
+	}
+	note, err := RenderNote(old, fm)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	if exists && bytes.Equal(note, old) {
+		res.Unchanged++
+		return nil
+	}
+	if err := os.WriteFile(path, note, 0o644); err != nil {
+		return err
+	}
+	if exists {
+		res.Updated++
+	} else {
+		res.Created++
+		idx.paths[fm.ID] = path
+		idx.taken[strings.ToLower(filepath.Base(path))] = true
+	}
+	return nil
+}
+
+func (idx *NoteIndex) newNotePath(fm NoteFrontmatter) string {
+	name := noteFileName(fm.Title)
+	if name == "" || idx.taken[strings.ToLower(name+".md")] {
+		name = strings.TrimSpace(name + " (" + fm.ID + ")")
+	}
+	return filepath.Join(idx.dir, name+".md")
+}
+
+// noteFileName makes a title safe as a file name on every platform and
+// in Obsidian links, which don't allow # ^ [ ] |. Long titles are cut to
+// maxNoteNameBytes on a rune boundary, leaving room under the usual
+// 255-byte limit for the " (id).md" suffix.
+func noteFileName(title string) string {
+	name := _unsafeFileCharsRE.ReplaceAllString(title, " ")
+	name = strings.Join(strings.Fields(name), " ")
+	name = strings.Trim(name, ". ")
+	if len(name) > maxNoteNameBytes {
+		cut := 0
+		for i := range name {
+			if i > maxNoteNameBytes {
+				break
+			}
+			cut = i
+		}
+		name = strings.Trim(name[:cut], ". ")
+	}
+	return name
+}
+
+const maxNoteNameBytes = 120
+
+// _frontmatterRE matches a leading "---" fenced YAML block.
+var _frontmatterRE = regexp.MustCompile(`(?s)\A---\r?\n(.*?)(?:\r?\n)?---[ \t]*(?:\r?\n|\z)`)
+
+var _unsafeFileCharsRE = regexp.MustCompile(`[/\\:*?"<>|#^\[\]\x00-\x1f]`)
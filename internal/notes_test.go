@@ -0,0 +1,148 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestRenderNote_New(t *testing.T) {
+	fm := NewNoteFrontmatter(
+		ShelfEntry{Book: Book{ID: "18690730", Title: "Tuokio tuulessa"}, MyRating: 5, Shelves: []string{"read", "favorites"}, DateRead: "2026-02-28"},
+		fixtureBook(t),
+	)
+	got, err := RenderNote(nil, fm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "---\n" +
+		"id: \"18690730\"\n" +
+		"title: Tuokio tuulessa\n" +
+		"author: André Brink\n" +
+		"isbn13: \"9789510085660\"\n" +
+		"pages: 350\n" +
+		"shelves:\n  - read\n  - favorites\n" +
+		"rating: 5\n" +
+		"date_read: \"2026-02-28\"\n" +
+		"cover: " + fm.Cover + "\n" +
+		"---\n" +
+		"# Tuokio tuulessa\n"
+	if string(got) != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+	if fm.Cover == "" {
+		t.Error("cover not taken from the book details")
+	}
+}
+
+func TestRenderNote_KeepsBodyAndUserKeys(t *testing.T) {
+	note := "---\r\n" +
+		"id: \"2\"\r\n" +
+		"tags: [sci-fi, classic] # mine\r\n" +
+		"rating: 3 # old\r\n" +
+		"---\r\n" +
+		"# Dune\r\n\r\nMy notes — keep these.\r\n"
+	fm := NoteFrontmatter{ID: "2", Title: "Dune", Author: "Frank Herbert", Rating: 4, Shelves: []string{"read"}}
+	got, err := RenderNote([]byte(note), fm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := string(got)
+	if !strings.HasSuffix(out, "---\n# Dune\r\n\r\nMy notes — keep these.\r\n") {
+		t.Errorf("body changed:\n%q", out)
+	}
+	for _, s := range []string{"tags: [sci-fi, classic] # mine\n", "rating: 4 # old\n", "author: Frank Herbert\n"} {
+		if !strings.Contains(out, s) {
+			t.Errorf("missing %q in\n%s", s, out)
+		}
+	}
+	if strings.Index(out, "tags:") > strings.Index(out, "rating:") {
+		t.Errorf("user key moved:\n%s", out)
+	}
+
+	// A second render with the same data changes nothing.
+	again, err := RenderNote(got, fm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(again) != out {
+		t.Errorf("not stable:\n%s\nthen\n%s", out, again)
+	}
+}
+
+func TestRenderNote_NoFrontmatter(t *testing.T) {
+	got, err := RenderNote([]byte("Just text.\n"), NoteFrontmatter{ID: "7", Title: "T"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(got), "---\nid: \"7\"\n") || !strings.HasSuffix(string(got), "---\nJust text.\n") {
+		t.Errorf("got\n%s", got)
+	}
+}
+
+func TestNoteIndex_SyncNote(t *testing.T) {
+	dir := t.TempDir()
+	// A note the user renamed, and an unrelated file taking the title.
+	os.WriteFile(filepath.Join(dir, "My Dune notes.md"), []byte("---\nid: 2\n---\nKeep.\n"), 0o644)
+	os.WriteFile(filepath.Join(dir, "Piranesi.md"), []byte("Not a book note.\n"), 0o644)
+
+	idx, err := LoadNoteIndex(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var res NotesSyncResult
+	for _, fm := range []NoteFrontmatter{
+		{ID: "2", Title: "Dune", Rating: 5},
+		{ID: "3", Title: "Piranesi"},
+		{ID: "4", Title: "What If?: Serious Answers"},
+		{ID: "2", Title: "Dune", Rating: 5},
+	} {
+		if err := idx.SyncNote(fm, &res); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if want := (NotesSyncResult{Created: 2, Updated: 1, Unchanged: 1}); res != want {
+		t.Errorf("result = %+v, want %+v", res, want)
+	}
+
+	entries, _ := os.ReadDir(dir)
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	want := []string{"My Dune notes.md", "Piranesi (3).md", "Piranesi.md", "What If Serious Answers.md"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("files = %q, want %q", names, want)
+	}
+	dune, _ := os.ReadFile(filepath.Join(dir, "My Dune notes.md"))
+	if !strings.Contains(string(dune), "rating: 5\n") || !strings.HasSuffix(string(dune), "---\nKeep.\n") {
+		t.Errorf("renamed note =\n%s", dune)
+	}
+
+	idx, err = LoadNoteIndex(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fm, ok := idx.Read("4"); !ok || fm.Title != "What If?: Serious Answers" {
+		t.Errorf("Read(4) = %+v, %v", fm, ok)
+	}
+}
+
+func TestNoteFileName(t *testing.T) {
+	if got := noteFileName(`Dune: "Deluxe" [Edition] #1 / 2.`); got != "Dune Deluxe Edition 1 2" {
+		t.Errorf("noteFileName = %q", got)
+	}
+
+	// A long non-ASCII title is cut between characters, not inside one.
+	long := strings.Repeat("Всё смешалось в доме Облонских ", 10)
+	got := noteFileName(long)
+	if !utf8.ValidString(got) || len(got) > maxNoteNameBytes || !strings.HasPrefix(long, got) {
+		t.Errorf("noteFileName(long) = %q (%d bytes)", got, len(got))
+	}
+	if len(got) < maxNoteNameBytes-utf8.UTFMax {
+		t.Errorf("noteFileName(long) cut too much: %d bytes", len(got))
+	}
+}
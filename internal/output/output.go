@@ -0,0 +1,400 @@
+// Package output renders command results in the format the user picks
+// with --output: an aligned table sized to the terminal, JSON, JSON
+// Lines, YAML, CSV, TSV, or a Go template applied to each item.
+//
+// Commands describe their table as Columns over the item type; --columns
+// picks among those and can also name any other JSON field of the item.
+package output
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+	"text/template"
+
+	"golang.org/x/term"
+	"gopkg.in/yaml.v3"
+
+	"github.com/yareeh/goodreads-cli/internal/jsonschema"
+)
+
+// The output formats.
+const (
+	Table    = "table"
+	JSON     = "json"
+	JSONL    = "jsonl"
+	YAML     = "yaml"
+	CSV      = "csv"
+	TSV      = "tsv"
+	Template = "template"
+)
+
+// Formats lists the output formats, for help and error messages.
+var Formats = []string{Table, JSON, JSONL, YAML, CSV, TSV, Template}
+
+// Options are the user's output choices.
+type Options struct {
+	Format   string   // one of Formats; empty means Table
+	Template string   // text/template applied to each item, for Template
+	Columns  []string // columns for Table, CSV and TSV; empty means the defaults
+	Width    int      // maximum table width; 0 means unlimited
+}
+
+// Validate checks the format and parses the template.
+func (o Options) Validate() error {
+	if o.Format != "" && !slices.Contains(Formats, o.Format) {
+		return fmt.Errorf("unknown output format %q (want %s)", o.Format, strings.Join(Formats, ", "))
+	}
+	if o.format() == Template && o.Template == "" {
+		return fmt.Errorf("--output template needs --template")
+	}
+	if o.Template != "" && o.format() != Template {
+		return fmt.Errorf("--template only applies to --output template")
+	}
+	_, err := o.template()
+	return err
+}
+
+// IsTable reports whether the output is the human-readable table, for
+// commands that print extra text such as "No results found." only there.
+func (o Options) IsTable() bool { return o.format() == Table }
+
+func (o Options) format() string {
+	if o.Format == "" {
+		return Table
+	}
+	return o.Format
+}
+
+func (o Options) template() (*template.Template, error) {
+	if o.Template == "" {
+		return nil, nil
+	}
+	t, err := template.New("output").Funcs(template.FuncMap{
+		"join": func(sep string, s []string) string { return strings.Join(s, sep) },
+		"json": func(v any) (string, error) {
+			data, err := json.Marshal(v)
+			return string(data), err
+		},
+	}).Parse(o.Template)
+	if err != nil {
+		return nil, fmt.Errorf("parsing --template: %w", err)
+	}
+	return t, nil
+}
+
+// TerminalWidth returns the width of the terminal f is, or $COLUMNS, or
+// 0 when output isn't going to a terminal and so shouldn't be cut.
+func TerminalWidth(f *os.File) int {
+	if w, _, err := term.GetSize(int(f.Fd())); err == nil && w > 0 {
+		return w
+	}
+	var w int
+	fmt.Sscan(os.Getenv("COLUMNS"), &w)
+	return w
+}
+
+// Column is one table (and CSV/TSV) column over items of type T.
+type Column[T any] struct {
+	Name   string // what --columns selects, and the CSV header: a JSON field name where there is one
+	Header string // table header; defaults to Name in upper case
+	Value  func(T) string
+	Right  bool // align right, for numbers
+}
+
+func (c Column[T]) header() string {
+	if c.Header != "" {
+		return c.Header
+	}
+	return strings.ToUpper(strings.ReplaceAll(c.Name, "_", " "))
+}
+
+// List writes items. An empty list is an empty table, [] or nothing.
+func List[T any](w io.Writer, opts Options, items []T, cols []Column[T]) error {
+	switch opts.format() {
+	case Table:
+		cols, err := selectColumns(cols, opts.Columns)
+		if err != nil {
+			return err
+		}
+		return writeTable(w, items, cols, opts.Width)
+	case CSV, TSV:
+		cols, err := selectColumns(cols, opts.Columns)
+		if err != nil {
+			return err
+		}
+		return writeDelimited(w, items, cols, opts.format() == TSV, true)
+	case JSON:
+		if items == nil {
+			items = []T{}
+		}
+		return writeJSON(w, items)
+	case YAML:
+		if items == nil {
+			items = []T{}
+		}
+		return writeYAML(w, items)
+	}
+	for _, item := range items {
+		if err := Item(w, opts, item, nil, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Item writes a single result. The table format is text, the command's
+// own human-readable view, unless --columns picks columns for a one-row
+// table. CSV and TSV write that one-row table, and fail when there are
+// neither cols nor --columns.
+func Item[T any](w io.Writer, opts Options, item T, cols []Column[T], text func(io.Writer) error) error {
+	switch opts.format() {
+	case Table:
+		if text == nil || len(opts.Columns) > 0 {
+			return List(w, opts, []T{item}, cols)
+		}
+		return text(w)
+	case CSV, TSV:
+		if cols == nil && opts.Columns == nil {
+			return fmt.Errorf("--output %s isn't available here; use json, yaml or template", opts.format())
+		}
+		return List(w, opts, []T{item}, cols)
+	case JSON:
+		return writeJSON(w, item)
+	case JSONL:
+		data, err := json.Marshal(item)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "%s\n", data)
+		return err
+	case YAML:
+		return writeYAML(w, item)
+	case Template:
+		t, err := opts.template()
+		if err != nil {
+			return err
+		}
+		var buf bytes.Buffer
+		if err := t.Execute(&buf, item); err != nil {
+			return err
+		}
+		if !bytes.HasSuffix(buf.Bytes(), []byte("\n")) {
+			buf.WriteByte('\n')
+		}
+		_, err = w.Write(buf.Bytes())
+		return err
+	}
+	return fmt.Errorf("unknown output format %q", opts.Format)
+}
+
+// Stream writes a list that arrives in parts, such as the pages of a
+// shelf. The line-oriented formats — JSON Lines, CSV, TSV and templates —
+// are written part by part as they come; a table, JSON and YAML need
+// every item, to size the columns or make one document, and are written
+// by Close.
+type Stream[T any] struct {
+	w       io.Writer
+	opts    Options
+	cols    []Column[T]
+	items   []T
+	started bool // the CSV or TSV header is written
+}
+
+// NewStream returns a Stream writing to w.
+func NewStream[T any](w io.Writer, opts Options, cols []Column[T]) *Stream[T] {
+	return &Stream[T]{w: w, opts: opts, cols: cols}
+}
+
+// Write writes items, or keeps them for Close.
+func (s *Stream[T]) Write(items []T) error {
+	switch s.opts.format() {
+	case JSONL, Template:
+		for _, item := range items {
+			if err := Item(s.w, s.opts, item, nil, nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	case CSV, TSV:
+		cols, err := selectColumns(s.cols, s.opts.Columns)
+		if err != nil {
+			return err
+		}
+		header := !s.started
+		s.started = true
+		return writeDelimited(s.w, items, cols, s.opts.format() == TSV, header)
+	}
+	s.items = append(s.items, items...)
+	return nil
+}
+
+// Close writes the items kept for the formats that need them all, and
+// the CSV or TSV header of a list that stayed empty.
+func (s *Stream[T]) Close() error {
+	switch s.opts.format() {
+	case JSONL, Template:
+		return nil
+	case CSV, TSV:
+		if s.started {
+			return nil
+		}
+		return s.Write(nil)
+	}
+	return List(s.w, s.opts, s.items, s.cols)
+}
+
+func writeJSON(w io.Writer, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s\n", data)
+	return err
+}
+
+// writeYAML writes v's JSON encoding as YAML, so fields keep their JSON
+// names and order.
+func writeYAML(w io.Writer, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return err
+	}
+	plainStyle(&node)
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(&node); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// plainStyle drops the flow and quoting styles a node parsed from JSON
+// has, so it is written as block YAML.
+func plainStyle(n *yaml.Node) {
+	n.Style = 0
+	for _, c := range n.Content {
+		plainStyle(c)
+	}
+}
+
+// writeDelimited writes CSV, quoted as RFC 4180 needs, or TSV, where
+// fields are never quoted and so have their tabs and newlines replaced
+// by spaces. The header row comes first if header is set.
+func writeDelimited[T any](w io.Writer, items []T, cols []Column[T], tsv, header bool) error {
+	var cw *csv.Writer
+	write := func(row []string) error {
+		_, err := fmt.Fprintln(w, strings.Join(row, "\t"))
+		return err
+	}
+	if !tsv {
+		cw = csv.NewWriter(w)
+		write = cw.Write
+	}
+	row := make([]string, len(cols))
+	if header {
+		for i, c := range cols {
+			row[i] = c.Name
+		}
+		if err := write(row); err != nil {
+			return err
+		}
+	}
+	for _, item := range items {
+		for i, c := range cols {
+			row[i] = c.Value(item)
+			if tsv {
+				row[i] = strings.Join(strings.Fields(row[i]), " ")
+			}
+		}
+		if err := write(row); err != nil {
+			return err
+		}
+	}
+	if cw == nil {
+		return nil
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// selectColumns returns the columns --columns names, in its order: the
+// command's own columns, or else any JSON field of T. With no names it
+// returns the command's columns.
+func selectColumns[T any](cols []Column[T], names []string) ([]Column[T], error) {
+	if len(names) == 0 {
+		return cols, nil
+	}
+	props, _ := jsonschema.For(reflect.TypeFor[T]())["properties"].(map[string]any)
+	var out []Column[T]
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if i := slices.IndexFunc(cols, func(c Column[T]) bool { return strings.EqualFold(c.Name, name) }); i >= 0 {
+			out = append(out, cols[i])
+			continue
+		}
+		key := strings.ToLower(name)
+		if _, ok := props[key]; !ok {
+			available := map[string]bool{}
+			for _, c := range cols {
+				available[c.Name] = true
+			}
+			for k := range props {
+				available[k] = true
+			}
+			return nil, fmt.Errorf("unknown column %q (available: %s)", name, strings.Join(slices.Sorted(maps.Keys(available)), ", "))
+		}
+		out = append(out, Column[T]{Name: key, Value: func(item T) string { return jsonField(item, key) }})
+	}
+	return out, nil
+}
+
+// jsonField returns field key of item's JSON encoding as text: scalars as
+// they are, arrays of scalars joined with ", ", anything else as JSON.
+func jsonField(item any, key string) string {
+	data, err := json.Marshal(item)
+	if err != nil {
+		return ""
+	}
+	var fields map[string]any
+	if json.Unmarshal(data, &fields) != nil {
+		return ""
+	}
+	return cellText(fields[key])
+}
+
+func cellText(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return fmt.Sprint(v)
+	case bool:
+		return fmt.Sprint(v)
+	case []any:
+		parts := make([]string, len(v))
+		for i, e := range v {
+			if _, ok := e.(map[string]any); ok {
+				data, _ := json.Marshal(v)
+				return string(data)
+			}
+			parts[i] = cellText(e)
+		}
+		return strings.Join(parts, ", ")
+	}
+	data, _ := json.Marshal(v)
+	return string(data)
+}
@@ -0,0 +1,268 @@
+package output
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/mattn/go-runewidth"
+)
+
+type item struct {
+	ID     string   `json:"id"`
+	Title  string   `json:"title"`
+	Pages  int      `json:"pages,omitempty"`
+	Genres []string `json:"genres,omitempty"`
+}
+
+var itemColumns = []Column[item]{
+	{Name: "id", Value: func(i item) string { return i.ID }},
+	{Name: "title", Value: func(i item) string { return i.Title }},
+	{Name: "pages", Value: func(i item) string { return strconv.Itoa(i.Pages) }, Right: true},
+}
+
+var items = []item{
+	{ID: "2", Title: "Dune", Pages: 412, Genres: []string{"Science Fiction", "Classics"}},
+	{ID: "18690730", Title: "Tuokio tuulessa – pieni kertomus äänistä", Pages: 96},
+	{ID: "25", Title: "ノルウェイの森", Pages: 296},
+}
+
+func render(t *testing.T, opts Options) string {
+	t.Helper()
+	if err := opts.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := List(&buf, opts, items, itemColumns); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+func TestTable(t *testing.T) {
+	got := render(t, Options{})
+	want := "" +
+		"ID        TITLE                                     PAGES\n" +
+		"--        -----                                     -----\n" +
+		"2         Dune                                        412\n" +
+		"18690730  Tuokio tuulessa – pieni kertomus äänistä     96\n" +
+		"25        ノルウェイの森                              296\n"
+	if got != want {
+		t.Errorf("table:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestTable_FitsWidth(t *testing.T) {
+	for _, width := range []int{40, 30} {
+		got := render(t, Options{Width: width})
+		for _, line := range strings.Split(strings.TrimSuffix(got, "\n"), "\n") {
+			if w := runewidth.StringWidth(line); w > width {
+				t.Errorf("width %d: line %q is %d wide", width, line, w)
+			}
+		}
+		if !strings.Contains(got, "…") {
+			t.Errorf("width %d: nothing cut:\n%s", width, got)
+		}
+		if !strings.Contains(got, "18690730") {
+			t.Errorf("width %d: ID column cut:\n%s", width, got)
+		}
+	}
+
+	got := render(t, Options{Width: 30})
+	if !strings.Contains(got, "Tuokio tuule…     96") {
+		t.Errorf("multi-byte title not cut on a rune boundary:\n%s", got)
+	}
+	if !strings.Contains(got, "ノルウェイの…    296") {
+		t.Errorf("wide characters not measured by display width:\n%s", got)
+	}
+
+	// Too narrow to fit: columns stop shrinking at minColumnWidth.
+	got = render(t, Options{Width: 10})
+	if !strings.Contains(got, "18690730  Tuokio tu…     96\n") {
+		t.Errorf("width 10:\n%s", got)
+	}
+}
+
+func TestTable_Columns(t *testing.T) {
+	got := render(t, Options{Columns: []string{"PAGES", "genres", "id"}})
+	want := "" +
+		"PAGES  GENRES                     ID\n" +
+		"-----  ------                     --\n" +
+		"  412  Science Fiction, Classics  2\n" +
+		"   96                             18690730\n" +
+		"  296                             25\n"
+	if got != want {
+		t.Errorf("table:\n%s\nwant:\n%s", got, want)
+	}
+
+	opts := Options{Columns: []string{"id", "isbn"}}
+	err := List(&bytes.Buffer{}, opts, items, itemColumns)
+	if err == nil || !strings.Contains(err.Error(), `unknown column "isbn" (available: genres, id, pages, title)`) {
+		t.Errorf("unknown column error = %v", err)
+	}
+}
+
+func TestDelimited(t *testing.T) {
+	got := render(t, Options{Format: CSV, Columns: []string{"id", "title"}})
+	want := "id,title\n2,Dune\n18690730,Tuokio tuulessa – pieni kertomus äänistä\n25,ノルウェイの森\n"
+	if got != want {
+		t.Errorf("csv:\n%s\nwant:\n%s", got, want)
+	}
+
+	got = render(t, Options{Format: TSV, Columns: []string{"id", "genres"}})
+	want = "id\tgenres\n2\tScience Fiction, Classics\n18690730\t\n25\t\n"
+	if got != want {
+		t.Errorf("tsv:\n%q\nwant:\n%q", got, want)
+	}
+
+	quoted := []item{{ID: "1", Title: "The \"Best\"\tof, all"}}
+	var buf bytes.Buffer
+	List(&buf, Options{Format: CSV, Columns: []string{"title"}}, quoted, itemColumns)
+	if want := "title\n\"The \"\"Best\"\"\tof, all\"\n"; buf.String() != want {
+		t.Errorf("csv quoting = %q, want %q", buf.String(), want)
+	}
+	buf.Reset()
+	List(&buf, Options{Format: TSV, Columns: []string{"title"}}, quoted, itemColumns)
+	if want := "title\nThe \"Best\" of, all\n"; buf.String() != want {
+		t.Errorf("tsv = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestJSONFormats(t *testing.T) {
+	got := render(t, Options{Format: JSONL})
+	want := `{"id":"2","title":"Dune","pages":412,"genres":["Science Fiction","Classics"]}
+{"id":"18690730","title":"Tuokio tuulessa – pieni kertomus äänistä","pages":96}
+{"id":"25","title":"ノルウェイの森","pages":296}
+`
+	if got != want {
+		t.Errorf("jsonl:\n%s\nwant:\n%s", got, want)
+	}
+
+	var buf bytes.Buffer
+	if err := List[item](&buf, Options{Format: JSON}, nil, itemColumns); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "[]\n" {
+		t.Errorf("empty json list = %q, want []", buf.String())
+	}
+}
+
+func TestYAML(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Item(&buf, Options{Format: YAML}, items[0], itemColumns, nil); err != nil {
+		t.Fatal(err)
+	}
+	want := "id: \"2\"\ntitle: Dune\npages: 412\ngenres:\n  - Science Fiction\n  - Classics\n"
+	if buf.String() != want {
+		t.Errorf("yaml:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestTemplate(t *testing.T) {
+	got := render(t, Options{Format: Template, Template: `{{.Title}} ({{.Pages}}){{if .Genres}} {{join "/" .Genres}}{{end}}`})
+	want := "Dune (412) Science Fiction/Classics\nTuokio tuulessa – pieni kertomus äänistä (96)\nノルウェイの森 (296)\n"
+	if got != want {
+		t.Errorf("template:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestItem(t *testing.T) {
+	text := func(w io.Writer) error {
+		_, err := io.WriteString(w, "Title: Dune\n")
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := Item(&buf, Options{}, items[0], itemColumns, text); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "Title: Dune\n" {
+		t.Errorf("table item = %q, want the text view", buf.String())
+	}
+
+	buf.Reset()
+	if err := Item(&buf, Options{Columns: []string{"title"}}, items[0], itemColumns, text); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "TITLE\n-----\nDune\n" {
+		t.Errorf("table item with --columns = %q", buf.String())
+	}
+
+	buf.Reset()
+	if err := Item(&buf, Options{Format: CSV}, items[0], itemColumns, text); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "id,title,pages\n2,Dune,412\n" {
+		t.Errorf("csv item = %q", buf.String())
+	}
+
+	if err := Item[item](&buf, Options{Format: TSV}, items[0], nil, text); err == nil {
+		t.Error("tsv of an item without columns accepted")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for _, tt := range []struct {
+		opts Options
+		ok   bool
+	}{
+		{Options{}, true},
+		{Options{Format: "yaml"}, true},
+		{Options{Format: "xml"}, false},
+		{Options{Format: Template}, false},
+		{Options{Template: "{{.ID}}"}, false},
+		{Options{Format: Template, Template: "{{.ID"}, false},
+		{Options{Format: Template, Template: "{{.ID}}"}, true},
+	} {
+		if err := tt.opts.Validate(); (err == nil) != tt.ok {
+			t.Errorf("Validate(%+v) = %v, want ok %v", tt.opts, err, tt.ok)
+		}
+	}
+}
+
+func TestStream(t *testing.T) {
+	for _, tt := range []struct {
+		opts   Options
+		first  string // written by the first Write
+		closed string // the whole output after Close
+	}{
+		{Options{Format: JSONL}, `{"id":"2","title":"Dune","pages":412,"genres":["Science Fiction","Classics"]}` + "\n", ""},
+		{Options{Format: CSV, Columns: []string{"id"}}, "id\n2\n", "id\n2\n18690730\n25\n"},
+		{Options{Format: Template, Template: "{{.ID}}"}, "2\n", "2\n18690730\n25\n"},
+		{Options{Format: JSON}, "", ""},
+		{Options{}, "", ""},
+	} {
+		var buf bytes.Buffer
+		s := NewStream(&buf, tt.opts, itemColumns)
+		if err := s.Write(items[:1]); err != nil {
+			t.Fatal(err)
+		}
+		if buf.String() != tt.first {
+			t.Errorf("%s: after the first part %q, want %q", tt.opts.format(), buf.String(), tt.first)
+		}
+		if err := s.Write(items[1:]); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.Close(); err != nil {
+			t.Fatal(err)
+		}
+		want := tt.closed
+		if want == "" {
+			want = render(t, tt.opts)
+		}
+		if buf.String() != want {
+			t.Errorf("%s: streamed\n%s\nwant\n%s", tt.opts.format(), buf.String(), want)
+		}
+	}
+
+	var buf bytes.Buffer
+	s := NewStream(&buf, Options{Format: TSV, Columns: []string{"id", "title"}}, itemColumns)
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "id\ttitle\n" {
+		t.Errorf("empty tsv = %q, want the header", buf.String())
+	}
+}
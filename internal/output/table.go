@@ -0,0 +1,102 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// columnGap separates table columns.
+const columnGap = "  "
+
+// minColumnWidth is as narrow as fitTable shrinks a column.
+const minColumnWidth = 10
+
+// writeTable writes items as a table with a header and underline, each
+// column as wide as its widest cell. When that is wider than width, the
+// widest columns give way first and their cells are cut with "…".
+func writeTable[T any](w io.Writer, items []T, cols []Column[T], width int) error {
+	rows := make([][]string, 0, len(items)+2)
+	header := make([]string, len(cols))
+	rule := make([]string, len(cols))
+	for i, c := range cols {
+		header[i] = c.header()
+		rule[i] = strings.Repeat("-", runewidth.StringWidth(header[i]))
+	}
+	rows = append(rows, header, rule)
+	for _, item := range items {
+		row := make([]string, len(cols))
+		for i, c := range cols {
+			row[i] = cellClean(c.Value(item))
+		}
+		rows = append(rows, row)
+	}
+
+	widths := make([]int, len(cols))
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], runewidth.StringWidth(cell))
+		}
+	}
+	fitTable(widths, width)
+
+	var sb strings.Builder
+	for _, row := range rows {
+		sb.Reset()
+		for i, cell := range row {
+			if i > 0 {
+				sb.WriteString(columnGap)
+			}
+			cell = runewidth.Truncate(cell, widths[i], "…")
+			last := i == len(row)-1
+			switch {
+			case cols[i].Right:
+				sb.WriteString(runewidth.FillLeft(cell, widths[i]))
+			case last:
+				sb.WriteString(cell)
+			default:
+				sb.WriteString(runewidth.FillRight(cell, widths[i]))
+			}
+		}
+		if _, err := fmt.Fprintln(w, strings.TrimRight(sb.String(), " ")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fitTable narrows widths until the table fits in width, one character
+// at a time from the widest column, never below minColumnWidth. A table
+// that can't fit that way is left as narrow as it got.
+func fitTable(widths []int, width int) {
+	if width <= 0 {
+		return
+	}
+	total := len(columnGap) * (len(widths) - 1)
+	for _, w := range widths {
+		total += w
+	}
+	for total > width {
+		widest := 0
+		for i, w := range widths {
+			if w > widths[widest] {
+				widest = i
+			}
+		}
+		if widths[widest] <= minColumnWidth {
+			return
+		}
+		widths[widest]--
+		total--
+	}
+}
+
+// cellClean puts a value on one line.
+func cellClean(s string) string {
+	if strings.ContainsAny(s, "\t\r\n") {
+		return strings.Join(strings.Fields(s), " ")
+	}
+	return s
+}
@@ -0,0 +1,135 @@
+package internal
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// ProgressUpdate is a reading-progress status to post: exactly one of Page
+// or Percent, and an optional comment.
+type ProgressUpdate struct {
+	Page    int
+	Percent int
+	Comment string
+}
+
+// Validate checks that exactly one of Page and Percent is set and in range.
+func (u ProgressUpdate) Validate() error {
+	switch {
+	case u.Page > 0 && u.Percent > 0:
+		return Errorf(CodeInvalidInput, "give either a page or a percentage, not both")
+	case u.Page < 0:
+		return Errorf(CodeInvalidInput, "page must be positive, got %d", u.Page)
+	case u.Percent < 0 || u.Percent > 100:
+		return Errorf(CodeInvalidInput, "percent must be between 0 and 100, got %d", u.Percent)
+	case u.Page == 0 && u.Percent == 0:
+		return Errorf(CodeInvalidInput, "give a page or a percentage")
+	}
+	return nil
+}
+
+// ParseProgressHTML returns the progress updates for bookID found on a
+// /user_status/list/<user_id> page, newest first. Goodreads words each
+// update as "… is on page 123 of 304 of <a href="/book/show/…">" or
+// "… is 40% done with <a href="/book/show/…">"; the page total is missing
+// when the edition has no page count.
+func ParseProgressHTML(html, bookID string) []ReadingProgress {
+	var out []ReadingProgress
+	for _, m := range _progressRE.FindAllStringSubmatch(html, -1) {
+		if m[4] != bookID {
+			continue
+		}
+		p := ReadingProgress{BookID: bookID}
+		p.CurrentPage, _ = strconv.Atoi(m[1])
+		p.TotalPages, _ = strconv.Atoi(m[2])
+		p.Percent, _ = strconv.Atoi(m[3])
+		if p.Percent == 0 && p.TotalPages > 0 {
+			p.Percent = p.CurrentPage * 100 / p.TotalPages
+		}
+		out = append(out, p)
+	}
+	return out
+}
+
+// GetProgress returns the logged-in user's most recent progress update
+// for a book. ok is false when there is none.
+func GetProgress(b *Browser, bookID string) (p ReadingProgress, ok bool, err error) {
+	updates, err := listProgress(b, bookID)
+	if err != nil || len(updates) == 0 {
+		return ReadingProgress{}, false, err
+	}
+	return updates[0], true, nil
+}
+
+// UpdateProgress posts a reading-progress status for a book — the same
+// request as the "Update progress" box on the Goodreads home page — and
+// checks that it shows up as the latest update.
+func UpdateProgress(b *Browser, bookID string, u ProgressUpdate) error {
+	if err := u.Validate(); err != nil {
+		return err
+	}
+	if err := b.goToShelfPage("currently-reading"); err != nil {
+		return err
+	}
+	fields := map[string]string{"user_status[book_id]": bookID}
+	if u.Page > 0 {
+		fields["user_status[page]"] = strconv.Itoa(u.Page)
+	} else {
+		fields["user_status[percent]"] = strconv.Itoa(u.Percent)
+	}
+	if u.Comment != "" {
+		fields["user_status[body]"] = u.Comment
+	}
+	if err := b.PostForm("POST", "/user_status", fields); err != nil {
+		saveDebugArtifacts(b)
+		return fmt.Errorf("posting progress for book %s: %w", bookID, err)
+	}
+
+	deadline := time.Now().Add(10 * time.Second)
+	var latest ReadingProgress
+	for {
+		updates, err := listProgress(b, bookID)
+		found := err == nil && len(updates) > 0
+		if found {
+			latest = updates[0]
+		}
+		match := found && ((u.Page > 0 && latest.CurrentPage == u.Page) ||
+			(u.Page == 0 && latest.Percent == u.Percent && latest.CurrentPage == 0))
+		b.Log.Record("verify_progress_poll", map[string]any{
+			"bookID": bookID, "page": latest.CurrentPage, "percent": latest.Percent, "match": match,
+		}, err)
+		if match {
+			return b.SaveCookies()
+		}
+		if time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(2 * time.Second)
+	}
+	saveDebugArtifacts(b)
+	return fmt.Errorf("progress update could not be verified — latest update for book %s is page %d (%d%%)",
+		bookID, latest.CurrentPage, latest.Percent)
+}
+
+// listProgress fetches the user's status updates and keeps those for
+// bookID.
+func listProgress(b *Browser, bookID string) ([]ReadingProgress, error) {
+	userID, err := b.UserID()
+	if err != nil {
+		return nil, err
+	}
+	html, err := b.FetchRenderedHTML(fmt.Sprintf("%s/user_status/list/%s", BaseURL, userID))
+	if err != nil {
+		return nil, fmt.Errorf("fetching progress updates: %w", err)
+	}
+	updates := ParseProgressHTML(html, bookID)
+	b.Log.Record("list_progress", map[string]any{"bookID": bookID, "count": len(updates)}, nil)
+	return updates, nil
+}
+
+// _progressRE matches one status sentence and the book it links to:
+// page, page total and percent (one of the first two or the third is set),
+// then the book ID.
+var _progressRE = regexp.MustCompile(`(?s)is (?:on page (\d+)(?: of (\d+))?|(\d+)% done)\s+(?:of|with)\s+<a[^>]*href="/book/show/(\d+)`)
@@ -0,0 +1,56 @@
+package internal
+
+import "testing"
+
+// progressListHTML mimics /user_status/list: newest first, mixing page and
+// percent updates, a page update without a total, and another book.
+const progressListHTML = `
+<div class="elementList">Skye Claw is on page 123 of 304 of <a class="bookTitle" href="/book/show/55145261-the-anthropocene-reviewed">The Anthropocene Reviewed</a></div>
+<div class="elementList">Skye Claw is 12% done with <a class="bookTitle" href="/book/show/18690730-tuokio-tuulessa">Tuokio tuulessa</a></div>
+<div class="elementList">Skye Claw is 20% done with <a class="bookTitle" href="/book/show/55145261-the-anthropocene-reviewed">The Anthropocene Reviewed</a></div>
+<div class="elementList">Skye Claw is on page 10 of <a class="bookTitle" href="/book/show/55145261-the-anthropocene-reviewed">The Anthropocene Reviewed</a></div>
+`
+
+func TestParseProgressHTML(t *testing.T) {
+	got := ParseProgressHTML(progressListHTML, "55145261")
+	want := []ReadingProgress{
+		{BookID: "55145261", CurrentPage: 123, TotalPages: 304, Percent: 40},
+		{BookID: "55145261", Percent: 20},
+		{BookID: "55145261", CurrentPage: 10},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d updates, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("update %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	if got := ParseProgressHTML(progressListHTML, "1"); len(got) != 0 {
+		t.Errorf("unknown book: got %+v, want none", got)
+	}
+}
+
+func TestProgressUpdateValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		u       ProgressUpdate
+		wantErr bool
+	}{
+		{"page", ProgressUpdate{Page: 123}, false},
+		{"percent", ProgressUpdate{Percent: 40, Comment: "halfway-ish"}, false},
+		{"finished", ProgressUpdate{Percent: 100}, false},
+		{"neither", ProgressUpdate{Comment: "hi"}, true},
+		{"both", ProgressUpdate{Page: 1, Percent: 1}, true},
+		{"over 100", ProgressUpdate{Percent: 101}, true},
+		{"negative page", ProgressUpdate{Page: -3}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.u.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate(%+v) error = %v, wantErr %v", tt.u, err, tt.wantErr)
+			}
+		})
+	}
+}
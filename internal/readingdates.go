@@ -0,0 +1,273 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/go-rod/rod"
+)
+
+// ReadingDates are the dates to record for a read-through of a book, as
+// YYYY-MM-DD. Empty fields are left as they are. Reread adds a new
+// read-through instead of changing the most recent one.
+type ReadingDates struct {
+	Started  string
+	Finished string
+	Reread   bool
+}
+
+// Validate checks the dates are real YYYY-MM-DD dates, not in the future,
+// and in order.
+func (d ReadingDates) Validate() error {
+	var started, finished time.Time
+	var err error
+	if d.Started != "" {
+		if started, err = parseReadingDate("started", d.Started); err != nil {
+			return err
+		}
+	}
+	if d.Finished != "" {
+		if finished, err = parseReadingDate("finished", d.Finished); err != nil {
+			return err
+		}
+	}
+	if !started.IsZero() && !finished.IsZero() && finished.Before(started) {
+		return Errorf(CodeInvalidInput, "finish date %s is before start date %s", d.Finished, d.Started)
+	}
+	return nil
+}
+
+func parseReadingDate(which, s string) (time.Time, error) {
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		return time.Time{}, Errorf(CodeInvalidInput, "%s date %q must be YYYY-MM-DD", which, s)
+	}
+	if t.After(time.Now()) {
+		return time.Time{}, Errorf(CodeInvalidInput, "%s date %s is in the future", which, s)
+	}
+	return t, nil
+}
+
+// SetReadingDates records start and finish dates for a book on the
+// logged-in user's shelves, through the reading-session date pickers on
+// /review/edit/<book_id>. Goodreads keeps one session per read-through,
+// newest first; without Reread the newest one is updated, with Reread a
+// new one is added. The book must already be shelved.
+func SetReadingDates(b *Browser, bookID string, d ReadingDates) error {
+	if err := d.Validate(); err != nil {
+		return err
+	}
+	if d.Started == "" && d.Finished == "" && !d.Reread {
+		return nil
+	}
+
+	form, err := openReviewForm(b, bookID)
+	if err != nil {
+		return err
+	}
+	before, err := readReadingSessions(b, form)
+	if err != nil {
+		return err
+	}
+
+	var key string
+	if d.Reread {
+		key, err = addReadingSession(b, form, before)
+		if err != nil {
+			return err
+		}
+	} else {
+		if len(before) == 0 {
+			saveDebugArtifacts(b)
+			return fmt.Errorf("no reading dates on the review form for book %s — is it shelved?", bookID)
+		}
+		key = before[0].Key
+	}
+
+	res, err := form.Eval(setReadingSessionJS, key, d.Started, d.Finished)
+	problem := ""
+	if err == nil && res != nil {
+		problem = res.Value.String()
+	}
+	b.Log.Record("set_reading_dates", map[string]any{
+		"bookID": bookID, "session": key, "started": d.Started, "finished": d.Finished, "reread": d.Reread, "problem": problem,
+	}, err)
+	if err != nil {
+		saveDebugArtifacts(b)
+		return fmt.Errorf("setting reading dates: %w", err)
+	}
+	if problem != "" {
+		saveDebugArtifacts(b)
+		return fmt.Errorf("setting reading dates: %s", problem)
+	}
+
+	if err := submitReviewForm(b, form, bookID); err != nil {
+		return err
+	}
+	if err := verifyReadingDates(b, bookID, d, len(before)); err != nil {
+		saveDebugArtifacts(b)
+		return err
+	}
+	return b.SaveCookies()
+}
+
+// formSession is one read-through as shown by the review form's date
+// pickers. Key identifies its fields within the form.
+type formSession struct {
+	Key      string `json:"key"`
+	Started  string `json:"started"`
+	Finished string `json:"finished"`
+}
+
+func readReadingSessions(b *Browser, form *rod.Element) ([]formSession, error) {
+	res, err := form.Eval(readReadingSessionsJS)
+	var sessions []formSession
+	if err == nil {
+		err = json.Unmarshal([]byte(res.Value.String()), &sessions)
+	}
+	b.Log.Record("read_reading_sessions", map[string]any{"count": len(sessions)}, err)
+	if err != nil {
+		saveDebugArtifacts(b)
+		return nil, fmt.Errorf("reading the review form's dates: %w", err)
+	}
+	return sessions, nil
+}
+
+// addReadingSession clicks the form's "read again" control and waits for
+// the new session's date pickers, returning their key.
+func addReadingSession(b *Browser, form *rod.Element, before []formSession) (string, error) {
+	res, err := form.Eval(clickRereadJS)
+	clicked := err == nil && res != nil && res.Value.Bool()
+	b.Log.Record("click_reread", map[string]any{"clicked": clicked}, err)
+	if !clicked {
+		saveDebugArtifacts(b)
+		return "", Errorf(CodeSelectorDrift, "could not find the \"read again\" control on the review form")
+	}
+
+	seen := map[string]bool{}
+	for _, s := range before {
+		seen[s.Key] = true
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		time.Sleep(500 * time.Millisecond)
+		after, err := readReadingSessions(b, form)
+		if err != nil {
+			return "", err
+		}
+		for _, s := range after {
+			if !seen[s.Key] {
+				return s.Key, nil
+			}
+		}
+	}
+	saveDebugArtifacts(b)
+	return "", Errorf(CodeSelectorDrift, "no new reading session appeared after clicking \"read again\"")
+}
+
+// verifyReadingDates reloads the review form and checks the dates stuck:
+// on the newest session, or on a newly added one for a reread.
+func verifyReadingDates(b *Browser, bookID string, want ReadingDates, sessionsBefore int) error {
+	matches := func(s formSession) bool {
+		return (want.Started == "" || s.Started == want.Started) &&
+			(want.Finished == "" || s.Finished == want.Finished)
+	}
+	deadline := time.Now().Add(10 * time.Second)
+	var got []formSession
+	for {
+		form, err := openReviewForm(b, bookID)
+		if err == nil {
+			got, err = readReadingSessions(b, form)
+		}
+		ok := false
+		if err == nil {
+			if want.Reread {
+				if len(got) > sessionsBefore {
+					for _, s := range got {
+						if matches(s) {
+							ok = true
+						}
+					}
+				}
+			} else {
+				ok = len(got) > 0 && matches(got[0])
+			}
+		}
+		b.Log.Record("verify_reading_dates_poll", map[string]any{"bookID": bookID, "sessions": got, "ok": ok}, err)
+		if ok {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(2 * time.Second)
+	}
+	return fmt.Errorf("reading dates could not be verified — Goodreads shows %+v", got)
+}
+
+// readingSessionsHelperJS groups the review form's date <select>s into
+// reading sessions. The pickers are Rails date_selects, so each date is
+// three selects named …started_at(1i)/(2i)/(3i)… or …read_at(Ni)… for
+// year, month and day; stripping the field name and part leaves a key
+// shared by a session's start and finish pickers.
+const readingSessionsHelperJS = `
+	const sessionFields = (form) => {
+		const byKey = new Map();
+		for (const sel of form.querySelectorAll('select[name]')) {
+			const m = sel.name.match(/(started_at|start_date|read_at|finished_at|end_date)\((\d)i\)/);
+			if (!m) continue;
+			const key = sel.name.replace(m[0], '');
+			const kind = /^start/.test(m[1]) ? 'started' : 'finished';
+			if (!byKey.has(key)) byKey.set(key, { started: [], finished: [] });
+			byKey.get(key)[kind][Number(m[2]) - 1] = sel;
+		}
+		return byKey;
+	};
+	const readDate = (parts) => {
+		if ([0, 1, 2].some((i) => !parts[i] || !parts[i].value)) return '';
+		const [y, mo, d] = parts.map((p) => p.value);
+		return y + '-' + mo.padStart(2, '0') + '-' + d.padStart(2, '0');
+	};
+`
+
+// readReadingSessionsJS returns the form's sessions as JSON, in page order.
+const readReadingSessionsJS = `function () {` + readingSessionsHelperJS + `
+	const out = [];
+	for (const [key, s] of sessionFields(this)) {
+		out.push({ key, started: readDate(s.started), finished: readDate(s.finished) });
+	}
+	return JSON.stringify(out);
+}`
+
+// setReadingSessionJS sets the started/finished date of the session key.
+// Empty dates are left alone. Returns a description of the first problem,
+// or "" on success.
+const setReadingSessionJS = `function (key, started, finished) {` + readingSessionsHelperJS + `
+	const s = sessionFields(this).get(key);
+	if (!s) return 'reading session ' + key + ' not found';
+	const setDate = (parts, iso, which) => {
+		if (!iso) return '';
+		if ([0, 1, 2].some((i) => !parts[i])) return 'no ' + which + ' date picker';
+		const values = iso.split('-').map((v) => String(Number(v)));
+		for (let i = 0; i < 3; i++) {
+			const opt = [...parts[i].options].find((o) => o.value === values[i]);
+			if (!opt) return which + ' date ' + iso + ' is not selectable';
+			parts[i].value = opt.value;
+			parts[i].dispatchEvent(new Event('change', { bubbles: true }));
+		}
+		return '';
+	};
+	return setDate(s.started, started, 'start') || setDate(s.finished, finished, 'finish');
+}`
+
+// clickRereadJS clicks the form's control for adding another read-through
+// and reports whether it found one. Only the form is searched: a
+// matching link elsewhere on the page belongs to some other book.
+const clickRereadJS = `function () {
+	const re = /read (it |this )?again|add (another|a new) (read|reading)|new reading session/i;
+	const el = [...this.querySelectorAll('a, button')].find((e) => re.test(e.textContent));
+	if (!el) return false;
+	el.click();
+	return true;
+}`
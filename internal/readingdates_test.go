@@ -0,0 +1,33 @@
+package internal
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReadingDatesValidate(t *testing.T) {
+	tomorrow := time.Now().AddDate(0, 0, 1).Format("2006-01-02")
+	tests := []struct {
+		name    string
+		dates   ReadingDates
+		wantErr bool
+	}{
+		{"empty", ReadingDates{}, false},
+		{"started only", ReadingDates{Started: "2026-01-31"}, false},
+		{"both in order", ReadingDates{Started: "2026-01-31", Finished: "2026-02-14"}, false},
+		{"same day", ReadingDates{Started: "2026-02-14", Finished: "2026-02-14"}, false},
+		{"reread", ReadingDates{Started: "2025-12-01", Reread: true}, false},
+		{"finished before started", ReadingDates{Started: "2026-02-14", Finished: "2026-01-31"}, true},
+		{"not ISO", ReadingDates{Finished: "14.2.2026"}, true},
+		{"impossible date", ReadingDates{Started: "2026-02-30"}, true},
+		{"future", ReadingDates{Finished: tomorrow}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.dates.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate(%+v) error = %v, wantErr %v", tt.dates, err, tt.wantErr)
+			}
+		})
+	}
+}
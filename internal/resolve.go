@@ -0,0 +1,178 @@
+package internal
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Kinds of book reference accepted by ResolveBookID.
+const (
+	BookRefID     = "id"     // legacy Goodreads ID, possibly with its slug
+	BookRefURL    = "url"    // goodreads.com/book/show/… URL
+	BookRefISBN10 = "isbn10" // ISBN-10, hyphens allowed
+	BookRefISBN13 = "isbn13" // ISBN-13, hyphens allowed
+	BookRefASIN   = "asin"   // Amazon Kindle ASIN (B0…)
+)
+
+// BookRef is the result of resolving a user-supplied book reference.
+type BookRef struct {
+	Input string `json:"input"`
+	Kind  string `json:"kind"`
+	Value string `json:"value"` // the normalized ISBN/ASIN, or the ID itself
+	ID    string `json:"id"`    // legacy Goodreads book ID
+}
+
+// ClassifyBookInput works out what kind of reference input is without
+// touching the network. For IDs and URLs the returned value is already
+// the legacy ID; ISBNs come back without hyphens or spaces.
+//
+// Bare digits are ambiguous: a 10-digit string is treated as an ISBN-10
+// and a 13-digit 978/979 string as an ISBN-13 only when its check digit
+// is valid, so ordinary legacy IDs (currently at most 9 digits) are never
+// mistaken for ISBNs.
+func ClassifyBookInput(input string) (kind, value string, err error) {
+	s := strings.TrimSpace(input)
+	if s == "" {
+		return "", "", Errorf(CodeInvalidInput, "empty book reference")
+	}
+
+	if strings.Contains(s, "goodreads.com/") || strings.HasPrefix(s, "/book/show/") {
+		if m := _bookURLIDRE.FindStringSubmatch(s); m != nil {
+			return BookRefURL, m[1], nil
+		}
+		return "", "", Errorf(CodeInvalidInput, "%q is not a Goodreads book URL (…/book/show/<id>)", input)
+	}
+
+	compact := strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(s))
+	switch {
+	case len(compact) == 13 && (strings.HasPrefix(compact, "978") || strings.HasPrefix(compact, "979")) && validISBN13(compact):
+		return BookRefISBN13, compact, nil
+	case len(compact) == 10 && validISBN10(compact):
+		return BookRefISBN10, compact, nil
+	case _asinRE.MatchString(compact):
+		return BookRefASIN, compact, nil
+	}
+
+	if m := _idSlugRE.FindStringSubmatch(s); m != nil {
+		return BookRefID, m[1], nil
+	}
+	return "", "", Errorf(CodeInvalidInput, "%q is not a Goodreads ID, URL, ISBN or ASIN", input)
+}
+
+// ResolveBookID turns an ID, id-slug, book URL, ISBN-10/13 or ASIN into a
+// legacy Goodreads book ID. IDs and URLs resolve locally; ISBNs and ASINs
+// go through Goodreads' /book/isbn/<isbn> redirect, falling back to
+// autocomplete search for identifiers the redirect doesn't know.
+func (c *Client) ResolveBookID(input string) (BookRef, error) {
+	return c.resolveBookID(BaseURL, input)
+}
+
+func (c *Client) resolveBookID(base, input string) (BookRef, error) {
+	kind, value, err := ClassifyBookInput(input)
+	if err != nil {
+		return BookRef{}, err
+	}
+	ref := BookRef{Input: input, Kind: kind, Value: value}
+	if kind == BookRefID || kind == BookRefURL {
+		ref.ID = value
+		return ref, nil
+	}
+
+	id, err := c.isbnRedirect(base, value)
+	if err == nil && id == "" {
+		id, err = c.autocompleteID(base, value)
+	}
+	c.Log.Record("resolve_book", map[string]any{"input": input, "kind": kind, "id": id}, err)
+	if err != nil {
+		return BookRef{}, fmt.Errorf("looking up %s %s: %w", kind, value, err)
+	}
+	if id == "" {
+		return BookRef{}, Errorf(CodeNotFound, "no Goodreads book found for %s %s", kind, value)
+	}
+	ref.ID = id
+	return ref, nil
+}
+
+// isbnRedirect asks /book/isbn/<isbn> where the book lives. Goodreads
+// answers with a redirect to /book/show/<id>-slug for known ISBNs and
+// ASINs; the redirect is read, not followed, so the WAF challenge on the
+// book page itself doesn't matter. "" means no redirect.
+func (c *Client) isbnRedirect(base, isbn string) (string, error) {
+	req, err := http.NewRequest("GET", base+"/book/isbn/"+url.PathEscape(isbn), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36")
+	noFollow := *c.HTTP
+	noFollow.CheckRedirect = func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+	resp, err := noFollow.Do(req)
+	if err != nil {
+		return "", err
+	}
+	resp.Body.Close()
+	if m := _bookURLIDRE.FindStringSubmatch(resp.Header.Get("Location")); m != nil {
+		return m[1], nil
+	}
+	return "", nil
+}
+
+// autocompleteID returns the first autocomplete hit for q, or "".
+func (c *Client) autocompleteID(base, q string) (string, error) {
+	books, err := c.autocomplete(base, q)
+	if err != nil || len(books) == 0 {
+		return "", err
+	}
+	return books[0].ID, nil
+}
+
+func validISBN10(s string) bool {
+	if len(s) != 10 {
+		return false
+	}
+	sum := 0
+	for i, r := range s {
+		var d int
+		switch {
+		case r >= '0' && r <= '9':
+			d = int(r - '0')
+		case r == 'X' && i == 9:
+			d = 10
+		default:
+			return false
+		}
+		sum += d * (10 - i)
+	}
+	return sum%11 == 0
+}
+
+func validISBN13(s string) bool {
+	if len(s) != 13 {
+		return false
+	}
+	sum := 0
+	for i, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+		d := int(r - '0')
+		if i%2 == 1 {
+			d *= 3
+		}
+		sum += d
+	}
+	return sum%10 == 0
+}
+
+// _bookURLIDRE captures the legacy ID from a /book/show/<id>[-.]slug path.
+var _bookURLIDRE = regexp.MustCompile(`/book/show/(\d+)`)
+
+// _idSlugRE matches a bare legacy ID, optionally followed by its slug
+// ("55145261-the-anthropocene-reviewed" or "2767052.The_Hunger_Games").
+var _idSlugRE = regexp.MustCompile(`^(\d+)(?:[-.][^\s/]*)?$`)
+
+// _asinRE matches Kindle ASINs, which start with B0 and are ten
+// alphanumerics. Other ASINs are ISBN-10s and are caught as such.
+var _asinRE = regexp.MustCompile(`^B0[0-9A-Z]{8}$`)
@@ -0,0 +1,87 @@
+package internal
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClassifyBookInput(t *testing.T) {
+	tests := []struct {
+		input, kind, value string
+	}{
+		{"55145261", BookRefID, "55145261"},
+		{" 55145261-the-anthropocene-reviewed ", BookRefID, "55145261"},
+		{"2767052.The_Hunger_Games", BookRefID, "2767052"},
+		{"https://www.goodreads.com/book/show/55145261-the-anthropocene-reviewed", BookRefURL, "55145261"},
+		{"goodreads.com/book/show/55145261.The_Anthropocene_Reviewed?from_search=true", BookRefURL, "55145261"},
+		{"https://www.goodreads.com/en/book/show/18690730", BookRefURL, "18690730"},
+		{"9780525555216", BookRefISBN13, "9780525555216"},
+		{"978-0-525-55521-6", BookRefISBN13, "9780525555216"},
+		{"0525555218", BookRefISBN10, "0525555218"},
+		{"0-8044-2957-x", BookRefISBN10, "080442957X"},
+		{"B08KHGDQ4M", BookRefASIN, "B08KHGDQ4M"},
+		// Ten digits with a bad ISBN check digit stay an ID.
+		{"1234567890", BookRefID, "1234567890"},
+	}
+	for _, tt := range tests {
+		kind, value, err := ClassifyBookInput(tt.input)
+		if err != nil {
+			t.Errorf("ClassifyBookInput(%q): %v", tt.input, err)
+			continue
+		}
+		if kind != tt.kind || value != tt.value {
+			t.Errorf("ClassifyBookInput(%q) = %s %q, want %s %q", tt.input, kind, value, tt.kind, tt.value)
+		}
+	}
+
+	for _, bad := range []string{"", "project hail mary", "https://www.goodreads.com/author/show/1406384.John_Green"} {
+		if _, _, err := ClassifyBookInput(bad); err == nil {
+			t.Errorf("ClassifyBookInput(%q): expected an error", bad)
+		}
+	}
+}
+
+func TestResolveBookID(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/book/isbn/9780525555216":
+			http.Redirect(w, r, "/book/show/55145261-the-anthropocene-reviewed", http.StatusFound)
+		case "/book/show/55145261-the-anthropocene-reviewed":
+			t.Error("resolver followed the redirect to the (WAF-walled) book page")
+		case "/book/auto_complete":
+			if r.URL.Query().Get("q") != "B08KHGDQ4M" {
+				json.NewEncoder(w).Encode([]autoCompleteResult{})
+				return
+			}
+			json.NewEncoder(w).Encode([]autoCompleteResult{{BookID: "55145261", Title: "The Anthropocene Reviewed"}})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+	c := &Client{HTTP: ts.Client()}
+
+	tests := []struct {
+		input, wantID string
+	}{
+		{"978-0-525-55521-6", "55145261"}, // redirect
+		{"B08KHGDQ4M", "55145261"},        // autocomplete fallback
+		{"55145261-the-anthropocene-reviewed", "55145261"},
+	}
+	for _, tt := range tests {
+		ref, err := c.resolveBookID(ts.URL, tt.input)
+		if err != nil {
+			t.Errorf("resolveBookID(%q): %v", tt.input, err)
+			continue
+		}
+		if ref.ID != tt.wantID || ref.Input != tt.input {
+			t.Errorf("resolveBookID(%q) = %+v, want ID %s", tt.input, ref, tt.wantID)
+		}
+	}
+
+	if _, err := c.resolveBookID(ts.URL, "0-8044-2957-X"); err == nil {
+		t.Error("expected an error for an ISBN Goodreads doesn't know")
+	}
+}
@@ -0,0 +1,213 @@
+package internal
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+)
+
+// reviewEditURL is the classic Rails review form for a book. It works for
+// books that are not shelved yet too — saving it adds the book to "read".
+func reviewEditURL(bookID string) string {
+	return fmt.Sprintf("%s/review/edit/%s", BaseURL, bookID)
+}
+
+// reviewFormSelector matches the review form on /review/edit/<book_id>.
+const reviewFormSelector = `form#reviewForm, form[action*="/review/update/"]`
+
+// ParseReviewFormHTML reads the logged-in user's current review of bookID
+// from the HTML of its /review/edit/<book_id> page: the star rating from
+// the form's hidden review[rating] input (or the stars widget), the body
+// from the review[review] textarea and the spoiler checkbox.
+func ParseReviewFormHTML(html, bookID string) (Review, error) {
+	if !_reviewFormRE.MatchString(html) {
+		return Review{}, Errorf(CodeNotLoggedIn, "no review form found on the review page — not logged in?")
+	}
+	r := Review{BookID: bookID}
+	if m := _reviewIDInFormRE.FindStringSubmatch(html); m != nil {
+		r.ReviewID = m[1]
+	}
+	if m := _reviewRatingInputRE.FindStringSubmatch(html); m != nil {
+		r.Rating, _ = strconv.Atoi(m[1] + m[2])
+	} else if m := _myRatingRE.FindStringSubmatch(html); m != nil {
+		r.Rating, _ = strconv.Atoi(m[1])
+	}
+	if m := _reviewBodyRE.FindStringSubmatch(html); m != nil {
+		r.Body = normalizeReviewBody(decodeHTMLEntities(m[1]))
+	}
+	r.Spoiler = _reviewSpoilerRE.MatchString(html)
+	return r, nil
+}
+
+// GetReview loads the logged-in user's review of a book. A book without a
+// review comes back with Rating 0 and an empty Body.
+func GetReview(b *Browser, bookID string) (Review, error) {
+	html, err := b.FetchRenderedHTML(reviewEditURL(bookID))
+	if err != nil {
+		return Review{}, fmt.Errorf("opening review page: %w", err)
+	}
+	r, err := ParseReviewFormHTML(html, bookID)
+	b.Log.Record("review_form", map[string]any{
+		"bookID": bookID, "rating": r.Rating, "bodyLen": len(r.Body), "spoiler": r.Spoiler,
+	}, err)
+	if err != nil {
+		saveDebugArtifacts(b)
+		return Review{}, err
+	}
+	return r, nil
+}
+
+// SaveReview replaces the rating, body and spoiler flag of the user's
+// review of r.BookID by filling in and submitting the review form, then
+// reloads the form to check Goodreads kept what was sent. Rating 0 clears
+// the rating; an empty Body clears the review text.
+func SaveReview(b *Browser, r Review) error {
+	if r.Rating < 0 || r.Rating > 5 {
+		return Errorf(CodeInvalidInput, "rating must be between 0 and 5, got %d", r.Rating)
+	}
+	form, err := openReviewForm(b, r.BookID)
+	if err != nil {
+		return err
+	}
+
+	// The stars widget writes into the hidden review[rating] input and the
+	// textarea is plain, so setting the values directly is what a click
+	// sequence would end up doing — and it avoids typing a long review one
+	// key event at a time.
+	res, err := form.Eval(fillReviewFormJS, r.Rating, r.Body, r.Spoiler)
+	missing := ""
+	if err == nil && res != nil {
+		missing = res.Value.String()
+	}
+	b.Log.Record("fill_review_form", map[string]any{
+		"bookID": r.BookID, "rating": r.Rating, "bodyLen": len(r.Body), "spoiler": r.Spoiler, "missing": missing,
+	}, err)
+	if err != nil {
+		saveDebugArtifacts(b)
+		return fmt.Errorf("filling review form: %w", err)
+	}
+	if missing != "" {
+		saveDebugArtifacts(b)
+		return Errorf(CodeSelectorDrift, "review form has no %s field — Goodreads may have changed the page", missing)
+	}
+
+	if err := submitReviewForm(b, form, r.BookID); err != nil {
+		return err
+	}
+	if err := verifyReview(b, r); err != nil {
+		saveDebugArtifacts(b)
+		return err
+	}
+	return b.SaveCookies()
+}
+
+// openReviewForm loads /review/edit/<book_id> and returns its form.
+func openReviewForm(b *Browser, bookID string) (*rod.Element, error) {
+	url := reviewEditURL(bookID)
+	if _, err := b.FetchRenderedHTML(url); err != nil {
+		return nil, fmt.Errorf("opening review page: %w", err)
+	}
+	form, err := b.Page.Timeout(10 * time.Second).Element(reviewFormSelector)
+	b.Log.Record("find_review_form", map[string]any{"url": url, "selector": reviewFormSelector}, err)
+	if err != nil {
+		saveDebugArtifacts(b)
+		return nil, Errorf(CodeSelectorDrift, "could not find review form: %w", err)
+	}
+	return form, nil
+}
+
+// submitReviewForm clicks the review form's Save button and waits for the
+// post to finish.
+func submitReviewForm(b *Browser, form *rod.Element, bookID string) error {
+	submit, err := form.Timeout(5 * time.Second).Element(`input[type="submit"], button[type="submit"]`)
+	b.Log.Record("review_submit", map[string]any{"bookID": bookID}, err)
+	if err != nil {
+		saveDebugArtifacts(b)
+		return Errorf(CodeSelectorDrift, "could not find the review Save button: %w", err)
+	}
+	if _, err := submit.Eval(`() => this.click()`); err != nil {
+		saveDebugArtifacts(b)
+		return fmt.Errorf("clicking the review Save button: %w", err)
+	}
+	b.Page.MustWaitStable()
+	return nil
+}
+
+// fillReviewFormJS sets the review form's fields and returns the name
+// of the first field it could not find, or "" when all were set.
+const fillReviewFormJS = `function (rating, body, spoiler) {
+	const form = this;
+	let ratingInput = form.querySelector('input[name="review[rating]"]');
+	if (!ratingInput) {
+		ratingInput = document.createElement('input');
+		ratingInput.type = 'hidden';
+		ratingInput.name = 'review[rating]';
+		form.appendChild(ratingInput);
+	}
+	ratingInput.value = String(rating);
+	const textarea = form.querySelector('textarea[name="review[review]"]');
+	if (!textarea) return 'review[review]';
+	textarea.value = body;
+	textarea.dispatchEvent(new Event('input', { bubbles: true }));
+	const spoilerBox = form.querySelector('input[type="checkbox"][name="review[spoiler_flag]"]');
+	if (spoilerBox) {
+		spoilerBox.checked = spoiler;
+	} else if (spoiler) {
+		return 'review[spoiler_flag]';
+	}
+	return '';
+}`
+
+// verifyReview reloads the review form until it shows r. Goodreads saves
+// the form with a full page post, so one or two reloads is normally enough.
+func verifyReview(b *Browser, want Review) error {
+	want.Body = normalizeReviewBody(want.Body)
+	deadline := time.Now().Add(10 * time.Second)
+	var got Review
+	for {
+		html, err := b.FetchRenderedHTML(reviewEditURL(want.BookID))
+		if err == nil {
+			got, err = ParseReviewFormHTML(html, want.BookID)
+		}
+		match := err == nil && got.Rating == want.Rating && got.Body == want.Body && got.Spoiler == want.Spoiler
+		b.Log.Record("verify_review_poll", map[string]any{
+			"bookID": want.BookID, "rating": got.Rating, "wantRating": want.Rating,
+			"bodyMatches": got.Body == want.Body, "spoiler": got.Spoiler, "match": match,
+		}, err)
+		if match {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(2 * time.Second)
+	}
+	return fmt.Errorf("review could not be verified — Goodreads shows rating %d and a %d-character review", got.Rating, len(got.Body))
+}
+
+// normalizeReviewBody makes bodies comparable across the round trip
+// through the textarea, which turns newlines into CRLF.
+func normalizeReviewBody(s string) string {
+	return strings.TrimSpace(strings.ReplaceAll(s, "\r\n", "\n"))
+}
+
+// _reviewFormRE detects the review form.
+var _reviewFormRE = regexp.MustCompile(`<form[^>]+(?:id="reviewForm"|action="/review/update/)`)
+
+// _reviewIDInFormRE finds the review ID in the page's delete link
+// (`/review/destroy/<review_id>`), present once the book is shelved.
+var _reviewIDInFormRE = regexp.MustCompile(`/review/destroy/(\d+)`)
+
+// _reviewRatingInputRE reads the hidden rating input, in either attribute
+// order.
+var _reviewRatingInputRE = regexp.MustCompile(`<input[^>]*(?:name="review\[rating\]"[^>]*value="(\d)"|value="(\d)"[^>]*name="review\[rating\]")`)
+
+// _reviewBodyRE captures the review textarea's contents.
+var _reviewBodyRE = regexp.MustCompile(`(?s)<textarea[^>]*name="review\[review\]"[^>]*>(.*?)</textarea>`)
+
+// _reviewSpoilerRE matches a checked spoiler checkbox.
+var _reviewSpoilerRE = regexp.MustCompile(`<input[^>]*name="review\[spoiler_flag\]"[^>]*checked|<input[^>]*checked[^>]*name="review\[spoiler_flag\]"`)
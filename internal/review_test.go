@@ -0,0 +1,60 @@
+package internal
+
+import "testing"
+
+// reviewFormHTML mirrors the parts of /review/edit/<book_id> the parser
+// reads: Rails' hidden-then-checkbox pair for the spoiler flag, the hidden
+// rating input fed by the stars widget, and the review textarea.
+const reviewFormHTML = `<html><body>
+<form id="reviewForm" action="/review/update/55145261" method="post">
+  <div class="stars" data-resource-id="55145261" data-rating="4"></div>
+  <input type="hidden" name="review[rating]" id="review_rating" value="4" />
+  <textarea name="review[review]" id="review_review_usertext">Loved it &amp; cried.&#13;
+Second paragraph.</textarea>
+  <input name="review[spoiler_flag]" type="hidden" value="0" />
+  <input type="checkbox" value="1" checked="checked" name="review[spoiler_flag]" id="review_spoiler_flag" />
+  <input type="submit" value="Save" />
+</form>
+<a data-method="delete" href="/review/destroy/8398019929">remove from my books</a>
+</body></html>`
+
+func TestParseReviewFormHTML(t *testing.T) {
+	r, err := ParseReviewFormHTML(reviewFormHTML, "55145261")
+	if err != nil {
+		t.Fatalf("ParseReviewFormHTML: %v", err)
+	}
+	if r.BookID != "55145261" || r.ReviewID != "8398019929" {
+		t.Errorf("IDs = %q / %q, want 55145261 / 8398019929", r.BookID, r.ReviewID)
+	}
+	if r.Rating != 4 {
+		t.Errorf("Rating = %d, want 4", r.Rating)
+	}
+	if want := "Loved it & cried.\nSecond paragraph."; r.Body != want {
+		t.Errorf("Body = %q, want %q", r.Body, want)
+	}
+	if !r.Spoiler {
+		t.Error("Spoiler = false, want true")
+	}
+}
+
+func TestParseReviewFormHTML_Unreviewed(t *testing.T) {
+	html := `<form id="reviewForm" action="/review/update/1" method="post">
+<input value="0" type="hidden" name="review[rating]" />
+<textarea name="review[review]"></textarea>
+<input name="review[spoiler_flag]" type="hidden" value="0" />
+<input type="checkbox" value="1" name="review[spoiler_flag]" />
+</form>`
+	r, err := ParseReviewFormHTML(html, "1")
+	if err != nil {
+		t.Fatalf("ParseReviewFormHTML: %v", err)
+	}
+	if r.Rating != 0 || r.Body != "" || r.Spoiler {
+		t.Errorf("got %+v, want an empty review", r)
+	}
+}
+
+func TestParseReviewFormHTML_NoForm(t *testing.T) {
+	if _, err := ParseReviewFormHTML(`<html><body>Sign in</body></html>`, "1"); err == nil {
+		t.Error("expected an error for a page without the review form")
+	}
+}
@@ -0,0 +1,153 @@
+package internal
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// SearchOptions selects and narrows a full-text search. The zero value
+// searches all fields and returns the first page.
+type SearchOptions struct {
+	Field string // "title", "author" or "all" (the default)
+	Page  int    // fetch only this 1-based page; 0 means start at page 1
+	Limit int    // stop after this many results, reading further pages as needed
+}
+
+// searchFields maps SearchOptions.Field onto the values of the /search
+// page's search[field] parameter.
+var searchFields = map[string]string{
+	"":       "on",
+	"all":    "on",
+	"title":  "title",
+	"author": "author",
+}
+
+// searchPerPage is the number of results /search renders per page.
+const searchPerPage = 20
+
+// ParseSearchResultsHTML extracts books from a /search?q=… results page.
+// Each result is a `<tr itemscope itemtype="http://schema.org/Book">` row
+// with the title and author links, the cover, and a "minirating" line
+// ("4.35 avg rating — 187,300 ratings") followed by "published 2021".
+// That year is the work's first publication, not the edition's, but it
+// is the only year the results page shows, so it goes into Book.Year.
+func ParseSearchResultsHTML(html string) []Book {
+	rows := _searchRowRE.FindAllString(html, -1)
+	books := make([]Book, 0, len(rows))
+	for _, row := range rows {
+		id := _searchBookIDRE.FindStringSubmatch(row)
+		title := _searchTitleRE.FindStringSubmatch(row)
+		if id == nil || title == nil {
+			continue
+		}
+		bk := Book{
+			ID:    id[1],
+			Title: decodeHTMLEntities(strings.TrimSpace(title[1])),
+			URL:   fmt.Sprintf("%s/book/show/%s", BaseURL, id[1]),
+		}
+		if m := _searchAuthorRE.FindStringSubmatch(row); m != nil {
+			bk.Author = decodeHTMLEntities(strings.TrimSpace(m[1]))
+		}
+		if m := _authorURLIDRE.FindStringSubmatch(row); m != nil {
+			bk.AuthorID = m[1]
+		}
+		if m := _searchCoverRE.FindStringSubmatch(row); m != nil {
+			bk.ImageURL = m[1]
+		}
+		if m := _searchRatingRE.FindStringSubmatch(row); m != nil {
+			bk.Rating = m[1]
+			bk.RatingsCount, _ = strconv.Atoi(strings.ReplaceAll(m[2], ",", ""))
+		}
+		if m := _searchPublishedRE.FindStringSubmatch(row); m != nil {
+			bk.Year = m[1]
+		}
+		books = append(books, bk)
+	}
+	return books
+}
+
+// searchURL builds the /search URL for one page of results.
+func searchURL(query, field string, page int) (string, error) {
+	f, ok := searchFields[field]
+	if !ok {
+		return "", Errorf(CodeInvalidInput, "unknown search field %q — use title, author or all", field)
+	}
+	v := url.Values{}
+	v.Set("q", query)
+	v.Set("search_type", "books")
+	v.Set("search[field]", f)
+	v.Set("page", strconv.Itoa(page))
+	return BaseURL + "/search?" + v.Encode(), nil
+}
+
+// searchPages runs a full-text search through fetch, following pages
+// until opts.Limit results are collected, a page comes back short, or —
+// with opts.Page set — after that single page.
+func searchPages(fetch func(string) (string, error), query string, opts SearchOptions) ([]Book, error) {
+	page := 1
+	if opts.Page > 0 {
+		page = opts.Page
+	}
+	books := []Book{}
+	for {
+		u, err := searchURL(query, opts.Field, page)
+		if err != nil {
+			return nil, err
+		}
+		html, err := fetch(u)
+		if err != nil {
+			return nil, fmt.Errorf("fetching search page %d: %w", page, err)
+		}
+		results := ParseSearchResultsHTML(html)
+		books = append(books, results...)
+		if opts.Limit > 0 && len(books) >= opts.Limit {
+			return books[:opts.Limit], nil
+		}
+		if opts.Page > 0 || opts.Limit == 0 || len(results) < searchPerPage {
+			return books, nil
+		}
+		page++
+	}
+}
+
+// SearchFull runs a search against the full /search results page, which
+// unlike autocomplete pages through every match and carries ratings and
+// publication years. The page is sometimes walled behind the AWS WAF
+// challenge; callers can fall back to Browser.SearchFull on
+// ErrAWSWAFChallenge.
+func (c *Client) SearchFull(query string, opts SearchOptions) ([]Book, error) {
+	return searchPages(c.fetchHTML, query, opts)
+}
+
+// SearchFull is Client.SearchFull through the browser, for when the plain
+// HTTP client is blocked by the WAF challenge.
+func (b *Browser) SearchFull(query string, opts SearchOptions) ([]Book, error) {
+	return searchPages(b.FetchRenderedHTML, query, opts)
+}
+
+// _searchRowRE matches one result row on /search.
+var _searchRowRE = regexp.MustCompile(`(?s)<tr[^>]*itemtype="http://schema.org/Book"[^>]*>.*?</tr>`)
+
+// _searchBookIDRE reads the book ID from the title link
+// (`/book/show/55145261-the-anthropocene-reviewed?from_search=true…`).
+var _searchBookIDRE = regexp.MustCompile(`<a[^>]*class="bookTitle"[^>]*href="/book/show/(\d+)`)
+
+// _searchTitleRE captures the title text inside the title link's
+// itemprop="name" span.
+var _searchTitleRE = regexp.MustCompile(`(?s)class="bookTitle".*?<span[^>]*itemprop=['"]name['"][^>]*>(.*?)</span>`)
+
+// _searchAuthorRE captures the first author's name.
+var _searchAuthorRE = regexp.MustCompile(`(?s)class="authorName".*?<span[^>]*itemprop=['"]name['"][^>]*>(.*?)</span>`)
+
+// _searchCoverRE captures the cover image URL.
+var _searchCoverRE = regexp.MustCompile(`<img[^>]*class="bookCover"[^>]*src="([^"]+)"`)
+
+// _searchRatingRE captures the average rating and ratings count from the
+// minirating line. The dash between them is `&mdash;` or a literal "—".
+var _searchRatingRE = regexp.MustCompile(`([\d.]+) avg rating\s*(?:&mdash;|—)\s*([\d,]+) ratings?`)
+
+// _searchPublishedRE captures the (first) publication year.
+var _searchPublishedRE = regexp.MustCompile(`published\s+(-?\d+)`)
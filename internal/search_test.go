@@ -0,0 +1,139 @@
+package internal
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// searchRowHTML renders one /search result row in Goodreads' markup.
+func searchRowHTML(id int, title, author string) string {
+	return fmt.Sprintf(`<tr itemscope itemtype="http://schema.org/Book">
+  <td width="5%%" valign="top"><a title="%[2]s" href="/book/show/%[1]d-slug?from_search=true&amp;rank=1"><img alt="%[2]s" class="bookCover" itemprop="image" src="https://i.gr-assets.com/%[1]d.jpg" /></a></td>
+  <td width="100%%" valign="top">
+    <a class="bookTitle" itemprop="url" href="/book/show/%[1]d-slug?from_search=true&amp;from_srp=true&amp;rank=1">
+      <span itemprop='name' role='heading' aria-level='4'>%[2]s</span>
+</a>    <br/>
+    <span class='by'>by</span>
+<span itemprop='author' itemscope='' itemtype='http://schema.org/Person'>
+<div class='authorName__container'>
+<a class="authorName" itemprop="url" href="https://www.goodreads.com/author/show/6540057.Andy_Weir?from_search=true"><span itemprop="name">%[3]s</span></a>
+</div>
+</span>
+    <div>
+      <span class="greyText smallText uitext">
+        <span class="minirating"><span class="stars staticStars notranslate"></span> 4.52 avg rating &mdash; 1,234,567 ratings</span>
+          &mdash;
+          published
+         2021
+          &mdash;
+          <a class="greyText" href="/work/editions/84187876">141 editions</a>
+      </span>
+    </div>
+  </td>
+</tr>`, id, title, author)
+}
+
+func TestParseSearchResultsHTML(t *testing.T) {
+	html := `<table class="tableList">` +
+		searchRowHTML(54493401, "Project Hail Mary", "Andy Weir") +
+		searchRowHTML(18007564, "The Martian &amp; Other Stories", "Andy Weir") +
+		`</table>`
+	books := ParseSearchResultsHTML(html)
+	if len(books) != 2 {
+		t.Fatalf("got %d books, want 2", len(books))
+	}
+	want := Book{
+		ID:           "54493401",
+		Title:        "Project Hail Mary",
+		Author:       "Andy Weir",
+		AuthorID:     "6540057",
+		Rating:       "4.52",
+		RatingsCount: 1234567,
+		Year:         "2021",
+		URL:          "https://www.goodreads.com/book/show/54493401",
+		ImageURL:     "https://i.gr-assets.com/54493401.jpg",
+	}
+	if !reflect.DeepEqual(books[0], want) {
+		t.Errorf("books[0] = %+v\nwant %+v", books[0], want)
+	}
+	if books[1].Title != "The Martian & Other Stories" {
+		t.Errorf("books[1].Title = %q, want entities decoded", books[1].Title)
+	}
+}
+
+func TestParseSearchResultsHTML_NoResults(t *testing.T) {
+	books := ParseSearchResultsHTML(`<h3 class="searchSubNavContainer">No results.</h3>`)
+	if books == nil || len(books) != 0 {
+		t.Errorf("want an empty, non-nil slice, got %#v", books)
+	}
+}
+
+// fakeSearch serves total results, searchPerPage per page.
+func fakeSearch(t *testing.T, total int, pages *[]int) func(string) (string, error) {
+	return func(u string) (string, error) {
+		parsed, err := url.Parse(u)
+		if err != nil {
+			return "", err
+		}
+		q := parsed.Query()
+		if q.Get("search[field]") != "title" || q.Get("q") != "dune" {
+			t.Errorf("unexpected query %v", q)
+		}
+		page, _ := strconv.Atoi(q.Get("page"))
+		*pages = append(*pages, page)
+		var sb strings.Builder
+		for i := (page - 1) * searchPerPage; i < page*searchPerPage && i < total; i++ {
+			sb.WriteString(searchRowHTML(1000+i, fmt.Sprintf("Dune %d", i), "Frank Herbert"))
+		}
+		return sb.String(), nil
+	}
+}
+
+func TestSearchPages(t *testing.T) {
+	var pages []int
+	books, err := searchPages(fakeSearch(t, 45, &pages), "dune", SearchOptions{Field: "title"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(books) != searchPerPage || len(pages) != 1 {
+		t.Errorf("default: got %d books from pages %v, want the first page only", len(books), pages)
+	}
+
+	pages = nil
+	books, err = searchPages(fakeSearch(t, 45, &pages), "dune", SearchOptions{Field: "title", Limit: 30})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(books) != 30 || books[29].ID != "1029" {
+		t.Errorf("limit 30: got %d books", len(books))
+	}
+
+	pages = nil
+	books, err = searchPages(fakeSearch(t, 45, &pages), "dune", SearchOptions{Field: "title", Limit: 500})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(books) != 45 || len(pages) != 3 {
+		t.Errorf("limit past the end: got %d books from pages %v, want 45 from [1 2 3]", len(books), pages)
+	}
+
+	pages = nil
+	books, err = searchPages(fakeSearch(t, 45, &pages), "dune", SearchOptions{Field: "title", Page: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(books) != 5 || books[0].ID != "1040" {
+		t.Errorf("page 3: got %d books", len(books))
+	}
+}
+
+func TestSearchPages_UnknownField(t *testing.T) {
+	_, err := searchPages(func(string) (string, error) { return "", nil }, "dune", SearchOptions{Field: "isbn"})
+	if err == nil {
+		t.Error("expected an error for an unknown search field")
+	}
+}
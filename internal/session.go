@@ -0,0 +1,154 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// Session is Goodreads access for a long-running process such as the MCP
+// or HTTP server: an HTTP client and one browser, launched on the first
+// operation that needs it and kept until Close. A Session is not safe
+// for concurrent use; callers serialize operations on it.
+type Session struct {
+	// AutoLogin logs in again when the saved session has expired; see
+	// EnsureLoggedIn.
+	AutoLogin bool
+
+	opts    BrowserOptions
+	client  *Client
+	browser *Browser
+}
+
+// NewSession returns a session whose browser, once needed, is opened
+// with opts.
+func NewSession(opts BrowserOptions) (*Session, error) {
+	client, err := NewClient()
+	if err != nil {
+		return nil, fmt.Errorf("creating client: %w", err)
+	}
+	return &Session{opts: opts, client: client}, nil
+}
+
+// Close closes the browser, if one was opened.
+func (s *Session) Close() {
+	if b := s.browser; b != nil {
+		s.browser = nil // forgotten first, in case closing a broken browser panics
+		b.Close()
+	}
+}
+
+// Browser returns the session's browser, launching it if needed.
+func (s *Session) Browser() (*Browser, error) {
+	if s.browser == nil {
+		b, err := NewBrowser(s.opts)
+		if err != nil {
+			return nil, fmt.Errorf("launching browser: %w", err)
+		}
+		s.browser = b
+	}
+	return s.browser, nil
+}
+
+// LoggedInBrowser is Browser for operations that need an account. The
+// login is checked, and with AutoLogin renewed, on every call — on the
+// home page, which a browser already used for something else goes back
+// to — so a session that expires while the server runs is noticed. A
+// browser that isn't logged in is dropped, so a later call after
+// `goodreads login` starts over with the new session.
+func (s *Session) LoggedInBrowser() (*Browser, error) {
+	fresh := s.browser == nil
+	b, err := s.Browser()
+	if err != nil {
+		return nil, err
+	}
+	if !fresh {
+		if _, err := b.FetchRenderedHTML(BaseURL + "/"); err != nil {
+			return nil, err
+		}
+	}
+	if err := EnsureLoggedIn(b, s.AutoLogin, os.Stderr); err != nil {
+		s.Close()
+		return nil, err
+	}
+	return b, nil
+}
+
+// ResolveBook turns an ID, ISBN, ASIN or URL into a book ID. IDs and
+// URLs resolve without a network round trip.
+func (s *Session) ResolveBook(input string) (string, error) {
+	kind, value, err := ClassifyBookInput(input)
+	if err != nil {
+		return "", err
+	}
+	if kind == BookRefID || kind == BookRefURL {
+		return value, nil
+	}
+	ref, err := s.client.ResolveBookID(input)
+	if err != nil {
+		return "", err
+	}
+	return ref.ID, nil
+}
+
+// Search returns autocomplete matches for query, or with full the full
+// results page, read in the browser when the client is challenged by
+// AWS WAF.
+func (s *Session) Search(query string, full bool, opts SearchOptions) ([]Book, error) {
+	if !full {
+		return s.client.Search(query)
+	}
+	books, err := s.client.SearchFull(query, opts)
+	if errors.Is(err, ErrAWSWAFChallenge) {
+		var b *Browser
+		if b, err = s.Browser(); err == nil {
+			books, err = b.SearchFull(query, opts)
+		}
+	}
+	return books, err
+}
+
+// BookDetails fetches the full book page of a book given as anything
+// ResolveBook accepts.
+func (s *Session) BookDetails(input string) (Book, error) {
+	id, err := s.ResolveBook(input)
+	if err != nil {
+		return Book{}, err
+	}
+	b, err := s.Browser()
+	if err != nil {
+		return Book{}, err
+	}
+	return b.FetchBookDetails(id)
+}
+
+// ListShelf returns the books on one of the user's shelves.
+func (s *Session) ListShelf(shelf string, opts ShelfListOptions) ([]ShelfEntry, error) {
+	b, err := s.LoggedInBrowser()
+	if err != nil {
+		return nil, err
+	}
+	entries := []ShelfEntry{}
+	err = b.ListShelfPages(shelf, opts, func(page []ShelfEntry) error {
+		entries = append(entries, page...)
+		return nil
+	})
+	return entries, err
+}
+
+// Shelve adds a book, given as anything ResolveBook accepts, to shelf
+// and returns its ID.
+func (s *Session) Shelve(input, shelf string) (string, error) {
+	id, err := s.ResolveBook(input)
+	if err != nil {
+		return "", err
+	}
+	b, err := s.LoggedInBrowser()
+	if err != nil {
+		return "", err
+	}
+	if err := AddToShelf(b, id, shelf); err != nil {
+		return "", err
+	}
+	return id, nil
+}
@@ -51,38 +51,37 @@ func shelfClickJS(label string) string {
 }
 
 // AddToShelf navigates to a book page and adds it to the specified shelf.
+// The built-in shelves go through the book page's shelf dialog; any other
+// name is a custom shelf, which adds to the book's shelves — unless the
+// shelf list has it as exclusive, in which case it replaces the book's
+// exclusive shelf.
 func AddToShelf(b *Browser, bookID string, shelfName string) error {
+	if !IsExclusiveShelf(shelfName) {
+		exclusive, err := customShelfExclusive(b, shelfName)
+		if err != nil {
+			return err
+		}
+		return addToCustomShelf(b, bookID, shelfName, exclusive, false)
+	}
+
 	url := fmt.Sprintf("https://www.goodreads.com/book/show/%s", bookID)
 	b.Log.Record("navigate", map[string]any{"url": url, "bookID": bookID, "shelf": shelfName}, nil)
 	b.Page.MustNavigate(url)
 	b.Page.MustWaitStable()
 
 	// Check if the book is already shelved
-	editBtn, err := b.Page.Timeout(10 * time.Second).Element(
-		`button[aria-label*="Tap to edit shelf"], button.Button--wtr`,
-	)
-	b.Log.Record("find_shelf_button", map[string]any{"selector": `button[aria-label*="Tap to edit shelf"], button.Button--wtr`}, err)
+	alreadyShelved, _, err := readShelfButton(b)
 	if err != nil {
 		saveDebugArtifacts(b)
-		return fmt.Errorf("could not find shelf button on book page: %w", err)
+		return err
 	}
 
-	ariaLabel, _ := editBtn.Attribute("aria-label")
-	alreadyShelved := ariaLabel != nil && strings.Contains(*ariaLabel, "Tap to edit shelf")
-	b.Log.Record("shelf_button_state", map[string]any{
-		"ariaLabel":      derefString(ariaLabel),
-		"alreadyShelved": alreadyShelved,
-	}, nil)
-
 	// Select the target shelf from the dialog.
-	label, ok := shelfAriaLabels[shelfName]
-	if !ok {
-		label = shelfName
-	}
+	label := shelfAriaLabels[canonicalShelfName(shelfName)]
 
 	if err := openDialogAndSelect(b, alreadyShelved, label); err != nil {
 		saveDebugArtifacts(b)
-		return fmt.Errorf("could not find shelf option '%s' in dialog: %w", shelfName, err)
+		return Errorf(CodeNotFound, "could not find shelf option '%s' in dialog: %w", shelfName, err)
 	}
 	b.Page.MustWaitStable()
 
@@ -106,6 +105,28 @@ func AddToShelf(b *Browser, bookID string, shelfName string) error {
 	return b.SaveCookies()
 }
 
+// readShelfButton finds the main shelf button on the currently loaded book
+// page and reports whether the book is already shelved and, if so, the
+// display name of the exclusive shelf it is on ("" when unshelved).
+func readShelfButton(b *Browser) (alreadyShelved bool, current string, err error) {
+	editBtn, err := b.Page.Timeout(10 * time.Second).Element(
+		`button[aria-label*="Tap to edit shelf"], button.Button--wtr`,
+	)
+	b.Log.Record("find_shelf_button", map[string]any{"selector": `button[aria-label*="Tap to edit shelf"], button.Button--wtr`}, err)
+	if err != nil {
+		return false, "", Errorf(CodeSelectorDrift, "could not find shelf button on book page: %w", err)
+	}
+
+	ariaLabel, _ := editBtn.Attribute("aria-label")
+	alreadyShelved = ariaLabel != nil && strings.Contains(*ariaLabel, "Tap to edit shelf")
+	current = parseShelvedAriaLabel(derefString(ariaLabel))
+	b.Log.Record("shelf_button_state", map[string]any{
+		"ariaLabel":      derefString(ariaLabel),
+		"alreadyShelved": alreadyShelved,
+	}, nil)
+	return alreadyShelved, current, nil
+}
+
 // openDialogAndSelect opens the shelf-picker dialog and clicks the option whose
 // aria-label equals `targetLabel`. Both steps are wrapped in a retry loop
 // because they are racy against React hydration on the Goodreads book page:
@@ -132,11 +153,17 @@ func AddToShelf(b *Browser, bookID string, shelfName string) error {
 // Falls back to the broad JS text-content matcher as a last resort so a
 // Goodreads DOM shift on the option aria-label doesn't wedge the whole flow.
 func openDialogAndSelect(b *Browser, alreadyShelved bool, targetLabel string) error {
+	return openDialogAndClick(b, alreadyShelved, shelfSelectorFor(targetLabel), targetLabel)
+}
+
+// openDialogAndClick is openDialogAndSelect with an explicit option selector,
+// for dialog controls that aren't shelf buttons (e.g. "Remove from my
+// shelf"). targetLabel is only used for logging and the JS fallback.
+func openDialogAndClick(b *Browser, alreadyShelved bool, optionSelector string, targetLabel string) error {
 	chevronSelectors := []string{
 		`button[aria-label="Tap to choose a shelf for this book"]`,
 		`button[aria-label*="edit shelf choice" i]`,
 	}
-	optionSelector := shelfSelectorFor(targetLabel)
 
 	// Give React a moment to hydrate before the first click. On a fresh
 	// page load Goodreads' JS bundle can take a few hundred ms to attach
@@ -163,7 +190,7 @@ func openDialogAndSelect(b *Browser, alreadyShelved bool, targetLabel string) er
 					[]string{`button[aria-label*="Tap to edit shelf"]`, `button.Button--wtr`},
 					5*time.Second,
 				); mainErr != nil {
-					return fmt.Errorf("could not click any shelf-opener button: %w", mainErr)
+					return Errorf(CodeSelectorDrift, "could not click any shelf-opener button: %w", mainErr)
 				}
 			} else {
 				lastErr = chevErr
@@ -184,7 +211,7 @@ func openDialogAndSelect(b *Browser, alreadyShelved bool, targetLabel string) er
 			"attempt": attempt,
 			"reason":  "target option did not appear",
 		}, nil)
-		lastErr = fmt.Errorf("dialog option %q did not appear after chevron click", targetLabel)
+		lastErr = Errorf(CodeSelectorDrift, "dialog option %q did not appear after chevron click", targetLabel)
 	}
 
 	// Last resort: broad JS text-content matcher scoped to dialog/menu.
@@ -247,7 +274,7 @@ func clickFirstVisible(b *Browser, selectors []string, timeout time.Duration) (s
 	// First wait for any matching element to render at all, so we don't
 	// race a still-loading page.
 	if _, err := b.Page.Timeout(timeout).Element(joined); err != nil {
-		return "", fmt.Errorf("no element matched %q within %s: %w", joined, timeout, err)
+		return "", Errorf(CodeSelectorDrift, "no element matched %q within %s: %w", joined, timeout, err)
 	}
 	for time.Now().Before(deadline) {
 		for _, sel := range selectors {
@@ -267,7 +294,7 @@ func clickFirstVisible(b *Browser, selectors []string, timeout time.Duration) (s
 		}
 		time.Sleep(200 * time.Millisecond)
 	}
-	return "", fmt.Errorf("no visible element among selectors %q became clickable within %s", joined, timeout)
+	return "", Errorf(CodeSelectorDrift, "no visible element among selectors %q became clickable within %s", joined, timeout)
 }
 
 // dialogShelfClickJS scopes the JS text-content click to elements INSIDE
@@ -370,6 +397,116 @@ func parseShelvedAriaLabel(label string) string {
 	return m[1]
 }
 
+// removeFromShelfSelector matches the dialog button that drops a book from
+// every shelf at once. Goodreads has no per-shelf removal for the
+// exclusive shelves — a shelved book is always on exactly one of them — so
+// taking a book off "read" means removing it from the library.
+const removeFromShelfSelector = `button[aria-label="Remove from my shelf"]`
+
+// confirmRemoveSelector matches the "Remove" button in the confirmation
+// dialog Goodreads shows after "Remove from my shelf" (the removal also
+// deletes the rating, review and reading dates, hence the prompt).
+const confirmRemoveSelector = `[role="dialog"] button[aria-label="Remove"], [role="alertdialog"] button[aria-label="Remove"]`
+
+// RemoveFromShelf navigates to a book page and takes the book off a shelf.
+//
+// An empty shelfName removes the book from the library entirely. An
+// exclusive shelf (want-to-read, currently-reading, read) is checked
+// against the book's current shelf first, so `--shelf read` never deletes
+// a book that is actually on want-to-read; removal then goes through the
+// same "Remove from my shelf" dialog control. A book that is not shelved at
+// all is treated as already removed. Custom shelves only drop the book from
+// that shelf and leave the rest of its shelving alone, except exclusive
+// ones, which like the built-in shelves can only be left by removing the
+// book.
+func RemoveFromShelf(b *Browser, bookID string, shelfName string) error {
+	label := shelfAriaLabels[canonicalShelfName(shelfName)]
+	if shelfName != "" && label == "" {
+		exclusive, err := customShelfExclusive(b, shelfName)
+		if err != nil {
+			return err
+		}
+		if !exclusive {
+			return addToCustomShelf(b, bookID, shelfName, false, true)
+		}
+		// The button shows the shelf's display name, "Did Not Finish"
+		// for did-not-finish.
+		label = strings.ReplaceAll(shelfName, "-", " ")
+	}
+
+	url := fmt.Sprintf("https://www.goodreads.com/book/show/%s", bookID)
+	b.Log.Record("navigate", map[string]any{"url": url, "bookID": bookID, "shelf": shelfName, "purpose": "unshelve"}, nil)
+	b.Page.MustNavigate(url)
+	b.Page.MustWaitStable()
+
+	alreadyShelved, current, err := readShelfButton(b)
+	if err != nil {
+		saveDebugArtifacts(b)
+		return err
+	}
+	if !alreadyShelved {
+		b.Log.Record("already_unshelved", map[string]any{"bookID": bookID}, nil)
+		return nil
+	}
+	if label != "" && !shelfLabelsMatch(current, label) {
+		return fmt.Errorf("book %s is on %q, not %q — nothing removed", bookID, current, label)
+	}
+
+	if err := openDialogAndClick(b, alreadyShelved, removeFromShelfSelector, "Remove from my shelf"); err != nil {
+		saveDebugArtifacts(b)
+		return Errorf(CodeSelectorDrift, "could not find 'Remove from my shelf' in dialog: %w", err)
+	}
+
+	// The confirmation dialog isn't shown for every book (Goodreads skips
+	// it when there's no rating or review to lose), so a missing button is
+	// not an error — verifyUnshelved is the ground truth.
+	confirmed := pollAndClickOption(b, confirmRemoveSelector, 5*time.Second)
+	b.Log.Record("confirm_remove", map[string]any{"clicked": confirmed}, nil)
+	b.Page.MustWaitStable()
+
+	if err := verifyUnshelved(b); err != nil {
+		b.Log.Record("verify_reload", map[string]any{"url": url, "reason": "in-place unshelve verify timed out"}, nil)
+		b.Page.MustNavigate(url)
+		b.Page.MustWaitStable()
+		if err2 := verifyUnshelved(b); err2 != nil {
+			saveDebugArtifacts(b)
+			return err2
+		}
+	}
+
+	return b.SaveCookies()
+}
+
+// verifyUnshelved is the removal counterpart of verifyShelf: it polls until
+// the page button no longer reads "Shelved as '…'". Same 8s budget.
+func verifyUnshelved(b *Browser) error {
+	deadline := time.Now().Add(8 * time.Second)
+	var last string
+	for {
+		el, err := b.Page.Timeout(2 * time.Second).Element(`button[aria-label*="Tap to edit shelf"], button.Button--wtr`)
+		if err == nil {
+			al, _ := el.Attribute("aria-label")
+			last = parseShelvedAriaLabel(derefString(al))
+			b.Log.Record("verify_unshelved_poll", map[string]any{
+				"ariaLabel": derefString(al), "parsed": last,
+			}, nil)
+			if last == "" {
+				return nil
+			}
+		} else {
+			b.Log.Record("verify_unshelved_poll", nil, err)
+		}
+		if time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(1 * time.Second)
+	}
+	if last == "" {
+		return fmt.Errorf("removal could not be verified — shelf button never rendered")
+	}
+	return fmt.Errorf("removal did not take effect — book is still shelved as %q", last)
+}
+
 // MarkCurrentlyReading adds a book to the "currently-reading" shelf.
 func MarkCurrentlyReading(b *Browser, bookID string) error {
 	return AddToShelf(b, bookID, "currently-reading")
@@ -272,3 +272,27 @@ func TestShelfButtonSelectorsMatchGoodreadsDOM(t *testing.T) {
 		}
 	}
 }
+
+// TestRemoveFromShelfSelectors documents the dialog controls RemoveFromShelf
+// relies on. The "Remove from my shelf" button lives in the same dialog as
+// the shelf options, so it must be an exact aria-label match like
+// shelfSelectorFor — a contains-match would also hit unrelated "Remove"
+// buttons elsewhere on the book page.
+func TestRemoveFromShelfSelectors(t *testing.T) {
+	if removeFromShelfSelector != `button[aria-label="Remove from my shelf"]` {
+		t.Errorf("removeFromShelfSelector = %q — check the Goodreads shelf dialog DOM", removeFromShelfSelector)
+	}
+	if !strings.Contains(confirmRemoveSelector, `[role="dialog"]`) {
+		t.Errorf("confirmRemoveSelector must be scoped to a dialog, got %q", confirmRemoveSelector)
+	}
+
+	src, err := os.ReadFile("shelf.go")
+	if err != nil {
+		t.Fatalf("read shelf.go: %v", err)
+	}
+	for _, kind := range []string{`"confirm_remove"`, `"verify_unshelved_poll"`, `"already_unshelved"`} {
+		if !strings.Contains(string(src), kind) {
+			t.Errorf("shelf.go missing interaction-log kind %s — grep target changed", kind)
+		}
+	}
+}
@@ -5,8 +5,11 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // ErrAWSWAFChallenge is returned by ListShelf-style HTTP fetches when
@@ -15,8 +18,9 @@ import (
 // requested HTML. The plain HTTP client cannot solve the challenge — a
 // browser-based fallback is needed. Callers (and tests) can detect this
 // with errors.Is so they can degrade gracefully instead of reporting the
-// generic "status 202" that used to leak through.
-var ErrAWSWAFChallenge = errors.New("goodreads returned AWS WAF challenge — browser session cookie needed")
+// generic "status 202" that used to leak through. Its code is
+// CodeWAFBlocked.
+var ErrAWSWAFChallenge = WithCode(CodeWAFBlocked, errors.New("goodreads returned AWS WAF challenge — browser session cookie needed"))
 
 // isAWSWAFChallengeBody reports whether the response body is the AWS WAF
 // JS challenge landing page. AWS WAF injects `awsWafCookieDomainList` and a
@@ -27,24 +31,27 @@ func isAWSWAFChallengeBody(body string) bool {
 		strings.Contains(body, "gokuProps")
 }
 
-// ParseShelfHTML extracts Book records from the HTML of a
+// ParseShelfHTML extracts shelf rows from the HTML of a
 // /review/list/<user_id>?shelf=<name> page. Each book appears as
-// `<tr id="review_NNN" class="bookalike review">...</tr>` with the resource
-// ID, title, and author nested inside known `<td class="field …">` cells.
-func ParseShelfHTML(html string) ([]Book, error) {
+// `<tr id="review_NNN" class="bookalike review">...</tr>` with one
+// `<td class="field NAME">` cell per column — ID, title and author, plus
+// the user's rating, dates, shelves and review. Columns hidden in the
+// user's table settings are still rendered (with display: none), so every
+// field is available regardless of what the web UI shows.
+func ParseShelfHTML(html string) ([]ShelfEntry, error) {
 	rows := _shelfRowRE.FindAllString(html, -1)
 	if rows == nil {
-		return []Book{}, nil
+		return []ShelfEntry{}, nil
 	}
-	books := make([]Book, 0, len(rows))
+	entries := make([]ShelfEntry, 0, len(rows))
 	for _, row := range rows {
-		b, ok := parseShelfRow(row)
+		e, ok := parseShelfRow(row)
 		if !ok {
 			continue
 		}
-		books = append(books, b)
+		entries = append(entries, e)
 	}
-	return books, nil
+	return entries, nil
 }
 
 // ExtractUserIDFromHomeHTML pulls the logged-in user's numeric ID from any
@@ -55,28 +62,140 @@ func ParseShelfHTML(html string) ([]Book, error) {
 func ExtractUserIDFromHomeHTML(html string) (string, error) {
 	m := _userIDRE.FindStringSubmatch(html)
 	if m == nil {
-		return "", fmt.Errorf("no /user/show/<id> link found — not logged in?")
+		return "", Errorf(CodeNotLoggedIn, "no /user/show/<id> link found — not logged in?")
 	}
 	return m[1], nil
 }
 
-// ListShelf fetches a Goodreads shelf for the logged-in user and returns the
-// books on it. Requires the cookies loaded from a prior `goodreads login` —
-// without them, Goodreads either redirects to login or shows an empty page.
-func (c *Client) ListShelf(shelfName string) ([]Book, error) {
-	userID, err := c.fetchUserID()
+// ParseShelfTotal returns the book count Goodreads prints next to the
+// shelf name in the page heading — `<span class="h1Shelf">Read
+// <span class="greyText">(1,412)</span>` — which is the total across all
+// pages, not just the rows on this one. ok is false if the heading is
+// missing.
+func ParseShelfTotal(html string) (total int, ok bool) {
+	m := _shelfTotalRE.FindStringSubmatch(html)
+	if m == nil {
+		return 0, false
+	}
+	n, err := strconv.Atoi(strings.ReplaceAll(m[1], ",", ""))
 	if err != nil {
-		return nil, err
+		return 0, false
 	}
-	url := fmt.Sprintf(
-		"%s/review/list/%s?shelf=%s&per_page=100",
-		BaseURL, userID, shelfName,
-	)
-	html, err := c.fetchHTML(url)
+	return n, true
+}
+
+// ShelfListOptions narrows a shelf listing. The zero value walks every page.
+type ShelfListOptions struct {
+	// Page starts the walk at this 1-based page, and without a Limit
+	// fetches only that page; 0 starts at the first.
+	Page  int
+	Limit int // stop after this many books; 0 means no limit
+	// Sort orders the rows newest first by a /review/list column, such
+	// as "date_updated" or "date_added"; empty keeps the shelf's order.
+	Sort string
+}
+
+// shelfPerPage is the largest page size /review/list accepts.
+const shelfPerPage = 100
+
+// walkShelf follows /review/list pagination for shelfName, handing each
+// page's books to fn as soon as it is parsed so callers can stream output.
+// fetch is the transport — the browser for WAF-walled sessions, the plain
+// HTTP client otherwise.
+//
+// Goodreads serves at most 100 rows per page and used to be read once, so a
+// 1,400-book shelf was silently cut at 100. The walk stops on a short or
+// empty page, or once the heading's total has been reached, and fails if a
+// full walk ends with fewer books than that total: an incomplete export is
+// worse than a failed one.
+func walkShelf(fetch func(string) (string, error), userID, shelfName string, opts ShelfListOptions, fn func([]ShelfEntry) error) error {
+	page := 1
+	if opts.Page > 0 {
+		page = opts.Page
+	}
+	// Books on the pages before the first one fetched, for comparing
+	// against the shelf's total.
+	skipped := (page - 1) * shelfPerPage
+	fetched := 0
+	total, haveTotal := 0, false
+	var lastFirstID string
+	for {
+		u := fmt.Sprintf(
+			"%s/review/list/%s?shelf=%s&per_page=%d&page=%d",
+			BaseURL, userID, url.QueryEscape(shelfName), shelfPerPage, page,
+		)
+		if opts.Sort != "" {
+			u += "&sort=" + url.QueryEscape(opts.Sort) + "&order=d"
+		}
+		html, err := fetch(u)
+		if err != nil {
+			return fmt.Errorf("fetching shelf %q page %d: %w", shelfName, page, err)
+		}
+		if t, ok := ParseShelfTotal(html); ok {
+			total, haveTotal = t, true
+		}
+		rows := len(_shelfRowRE.FindAllStringIndex(html, -1))
+		books, err := ParseShelfHTML(html)
+		if err != nil {
+			return err
+		}
+		// Out-of-range pages can echo the last real page back; treat a
+		// repeat as the end rather than looping forever.
+		if len(books) > 0 && books[0].ID == lastFirstID {
+			break
+		}
+		if len(books) > 0 {
+			lastFirstID = books[0].ID
+		}
+		if opts.Limit > 0 && fetched+len(books) > opts.Limit {
+			books = books[:opts.Limit-fetched]
+		}
+		if len(books) > 0 {
+			if err := fn(books); err != nil {
+				return err
+			}
+		}
+		fetched += len(books)
+
+		if (opts.Page > 0 && opts.Limit == 0) || (opts.Limit > 0 && fetched >= opts.Limit) {
+			return nil
+		}
+		if rows < shelfPerPage || (haveTotal && skipped+fetched >= total) {
+			break
+		}
+		page++
+	}
+	if haveTotal && skipped+fetched < total {
+		if skipped > 0 {
+			return fmt.Errorf("shelf %q: fetched %d books from page %d on, which with the %d before it is %d of %d — some rows could not be read",
+				shelfName, fetched, skipped/shelfPerPage+1, skipped, skipped+fetched, total)
+		}
+		return fmt.Errorf("shelf %q: fetched %d of %d books — some rows could not be read", shelfName, fetched, total)
+	}
+	return nil
+}
+
+// ListShelf fetches a Goodreads shelf for the logged-in user and returns the
+// books on it, following pagination to the end. Requires the cookies loaded
+// from a prior `goodreads login` — without them, Goodreads either redirects
+// to login or shows an empty page.
+func (c *Client) ListShelf(shelfName string) ([]ShelfEntry, error) {
+	books := []ShelfEntry{}
+	err := c.ListShelfPages(shelfName, ShelfListOptions{}, func(page []ShelfEntry) error {
+		books = append(books, page...)
+		return nil
+	})
+	return books, err
+}
+
+// ListShelfPages is ListShelf with paging options, calling fn once per page
+// as results arrive.
+func (c *Client) ListShelfPages(shelfName string, opts ShelfListOptions, fn func([]ShelfEntry) error) error {
+	userID, err := c.fetchUserID()
 	if err != nil {
-		return nil, fmt.Errorf("fetching shelf %q: %w", shelfName, err)
+		return err
 	}
-	return ParseShelfHTML(html)
+	return walkShelf(c.fetchHTML, userID, shelfName, opts, fn)
 }
 
 func (c *Client) fetchUserID() (string, error) {
@@ -110,7 +229,7 @@ func (c *Client) fetchHTML(url string) (string, error) {
 		return "", fmt.Errorf("%w (url=%s)", ErrAWSWAFChallenge, url)
 	}
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("status %d", resp.StatusCode)
+		return "", httpStatusError(resp.StatusCode, "status %d", resp.StatusCode)
 	}
 	return string(body), nil
 }
@@ -138,29 +257,176 @@ var _titleRE = regexp.MustCompile(`<td class="field title">[\s\S]*?<a\s+title="(
 // `<a href="/author/show/…">Name</a>` inside the author cell.
 var _authorRE = regexp.MustCompile(`<td class="field author">[\s\S]*?<a\s+href="/author/show/[^"]+">([^<]+)</a>`)
 
+// _shelfTotalRE captures the shelf's total book count from the page
+// heading (`<span class="h1Shelf"> Read <span class="greyText">(N)</span>`).
+var _shelfTotalRE = regexp.MustCompile(`(?s)<span class="h1Shelf">.*?<span class="greyText">\(([\d,]+)\)</span>`)
+
 // _userIDRE matches the logged-in user's profile link in the header.
 var _userIDRE = regexp.MustCompile(`<a[^>]+href="/user/show/(\d+)`)
 
-func parseShelfRow(row string) (Book, bool) {
+// _reviewIDRE captures the review ID from the row's `id="review_NNN"`.
+var _reviewIDRE = regexp.MustCompile(`<tr\s+id="review_(\d+)"`)
+
+// _shelfCellRE splits a row into its `<td class="field NAME">` cells,
+// capturing the name and the contents of the cell's `<div class="value">`.
+// The value div is the last element in the cell, so the first `</div>`
+// followed directly by `</td>` closes it even when it contains nested divs.
+var _shelfCellRE = regexp.MustCompile(`(?s)<td class="field (\w+)"[^>]*>.*?<div class="value">(.*?)</div>\s*</td>`)
+
+// _myRatingRE reads the user's stars from the rating widget.
+var _myRatingRE = regexp.MustCompile(`data-rating="(\d)"`)
+
+// _shelfLinkRE captures each shelf name in the shelves cell.
+var _shelfLinkRE = regexp.MustCompile(`<a class="shelfLink"[^>]*>([^<]+)</a>`)
+
+// _dateRowRE splits the date started/read cells into one chunk per reading
+// session; _dateValueRE pulls the ISO date out of the chunk's "[edit]" link
+// (`{value: &quot;2026-06-01&quot;, …}`). Unset dates have `{value: {}}`.
+var (
+	_dateRowRE   = regexp.MustCompile(`<div class="date_row">`)
+	_dateValueRE = regexp.MustCompile(`value: &quot;(\d{4}-\d{2}-\d{2})&quot;`)
+)
+
+// _dateAddedRE reads the full date from the date-added cell's tooltip
+// (`<span title="February 28, 2026">`).
+var _dateAddedRE = regexp.MustCompile(`<span title="([^"]+)"`)
+
+// _freeTextRE captures review text. Long reviews are rendered twice: a
+// truncated freeTextContainer and a hidden freeText span with the full
+// text; short ones only have the container.
+var _freeTextRE = regexp.MustCompile(`(?s)<span id="freeText(Container)?review\d+"[^>]*>(.*?)</span>`)
+
+var (
+	_anchorRE = regexp.MustCompile(`(?s)<a\b[^>]*>.*?</a>`)
+	_tagRE    = regexp.MustCompile(`<[^>]+>`)
+	_brRE     = regexp.MustCompile(`<br\s*/?>`)
+	_digitsRE = regexp.MustCompile(`\d+`)
+	_yearRE   = regexp.MustCompile(`\d{4}`)
+)
+
+func parseShelfRow(row string) (ShelfEntry, bool) {
 	idMatch := _resourceIDRE.FindStringSubmatch(row)
 	titleMatch := _titleRE.FindStringSubmatch(row)
 	authorMatch := _authorRE.FindStringSubmatch(row)
 	if idMatch == nil || titleMatch == nil {
-		return Book{}, false
+		return ShelfEntry{}, false
 	}
 	author := ""
 	if authorMatch != nil {
 		author = strings.TrimSpace(authorMatch[1])
 	}
-	return Book{
-		ID:     idMatch[1],
-		Title:  decodeHTMLEntities(strings.TrimSpace(titleMatch[1])),
-		Author: decodeHTMLEntities(author),
-	}, true
+	e := ShelfEntry{
+		Book: Book{
+			ID:     idMatch[1],
+			Title:  decodeHTMLEntities(strings.TrimSpace(titleMatch[1])),
+			Author: decodeHTMLEntities(author),
+		},
+		Shelves: []string{},
+	}
+	if m := _reviewIDRE.FindStringSubmatch(row); m != nil {
+		e.ReviewID = m[1]
+	}
+
+	cells := map[string]string{}
+	for _, m := range _shelfCellRE.FindAllStringSubmatch(row, -1) {
+		cells[m[1]] = m[2]
+	}
+	e.ISBN = cellText(cells["isbn"])
+	e.ISBN13 = cellText(cells["isbn13"])
+	e.ASIN = cellText(cells["asin"])
+	e.Pages = firstInt(cellText(cells["num_pages"]))
+	e.Rating = cellText(cells["avg_rating"])
+	e.RatingsCount = firstInt(strings.ReplaceAll(cellText(cells["num_ratings"]), ",", ""))
+	e.OriginalYear = _yearRE.FindString(cellText(cells["date_pub"]))
+	e.Year = _yearRE.FindString(cellText(cells["date_pub_edition"]))
+	e.Format = cellText(_anchorRE.ReplaceAllString(cells["format"], ""))
+	e.ReadCount = firstInt(cellText(cells["read_count"]))
+	if m := _myRatingRE.FindStringSubmatch(cells["rating"]); m != nil {
+		e.MyRating, _ = strconv.Atoi(m[1])
+	}
+	for _, m := range _shelfLinkRE.FindAllStringSubmatch(cells["shelves"], -1) {
+		e.Shelves = append(e.Shelves, canonicalShelfName(decodeHTMLEntities(strings.TrimSpace(m[1]))))
+	}
+	e.Review = parseReviewCell(cells["review"])
+	if m := _dateAddedRE.FindStringSubmatch(cells["date_added"]); m != nil {
+		if t, err := time.Parse("January 2, 2006", m[1]); err == nil {
+			e.DateAdded = t.Format("2006-01-02")
+		}
+	}
+
+	started := dateRows(cells["date_started"])
+	read := dateRows(cells["date_read"])
+	for i := 0; i < max(len(started), len(read)); i++ {
+		var rs ReadingSession
+		if i < len(started) {
+			rs.Started = started[i]
+		}
+		if i < len(read) {
+			rs.Finished = read[i]
+		}
+		if rs != (ReadingSession{}) {
+			e.Reads = append(e.Reads, rs)
+		}
+	}
+	if len(started) > 0 {
+		e.DateStarted = started[0]
+	}
+	if len(read) > 0 {
+		e.DateRead = read[0]
+	}
+	return e, true
+}
+
+// dateRows returns one date per reading session in a date started/read
+// cell, "" for sessions where the date is not set.
+func dateRows(cell string) []string {
+	chunks := _dateRowRE.Split(cell, -1)
+	if len(chunks) < 2 {
+		return nil
+	}
+	dates := make([]string, 0, len(chunks)-1)
+	for _, c := range chunks[1:] {
+		d := ""
+		if m := _dateValueRE.FindStringSubmatch(c); m != nil {
+			d = m[1]
+		}
+		dates = append(dates, d)
+	}
+	return dates
+}
+
+// parseReviewCell returns the review text, preferring the full hidden copy
+// over the truncated one. The "Write a review" link on unreviewed books
+// yields "".
+func parseReviewCell(cell string) string {
+	text := ""
+	for _, m := range _freeTextRE.FindAllStringSubmatch(cell, -1) {
+		if text == "" || m[1] == "" {
+			text = m[2]
+		}
+	}
+	if text == "" {
+		return ""
+	}
+	text = _brRE.ReplaceAllString(text, "\n")
+	text = decodeHTMLEntities(_tagRE.ReplaceAllString(text, ""))
+	return strings.TrimSpace(text)
+}
+
+// cellText strips tags from a cell value and collapses its whitespace.
+func cellText(cell string) string {
+	return decodeHTMLEntities(strings.Join(strings.Fields(_tagRE.ReplaceAllString(cell, " ")), " "))
+}
+
+// firstInt returns the first run of digits in s as an int, or 0.
+func firstInt(s string) int {
+	n, _ := strconv.Atoi(_digitsRE.FindString(s))
+	return n
 }
 
 // decodeHTMLEntities decodes the small set of HTML entities Goodreads
-// emits in title and author attributes (apostrophes, ampersands, quotes).
+// emits in title and author attributes (apostrophes, ampersands, quotes)
+// and the carriage returns Rails escapes in textarea contents.
 // A full HTML parser is overkill for these well-formed attribute values.
 func decodeHTMLEntities(s string) string {
 	r := strings.NewReplacer(
@@ -170,6 +436,7 @@ func decodeHTMLEntities(s string) string {
 		"&quot;", `"`,
 		"&lt;", "<",
 		"&gt;", ">",
+		"&#13;", "\r",
 	)
 	return r.Replace(s)
 }
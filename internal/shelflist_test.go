@@ -1,7 +1,11 @@
 package internal
 
 import (
+	"fmt"
+	"net/url"
 	"os"
+	"strconv"
+	"strings"
 	"testing"
 )
 
@@ -27,7 +31,7 @@ func TestParseShelfHTML_ExtractsBookFromCurrentlyReading(t *testing.T) {
 	var found *Book
 	for i := range books {
 		if books[i].ID == want.ID {
-			found = &books[i]
+			found = &books[i].Book
 			break
 		}
 	}
@@ -53,6 +57,85 @@ func TestParseShelfHTML_EmptyShelfReturnsEmptySlice(t *testing.T) {
 	}
 }
 
+// TestParseShelfHTML_RowFields checks the per-user columns of the fixture
+// row, including the ones hidden in the web UI's table settings.
+func TestParseShelfHTML_RowFields(t *testing.T) {
+	data, err := os.ReadFile("testdata/shelf_currently_reading.html")
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+	entries, err := ParseShelfHTML(string(data))
+	if err != nil || len(entries) == 0 {
+		t.Fatalf("ParseShelfHTML: %d entries, err %v", len(entries), err)
+	}
+	e := entries[0]
+
+	checks := []struct {
+		name      string
+		got, want any
+	}{
+		{"ReviewID", e.ReviewID, "8398019929"},
+		{"ISBN", e.ISBN, "0525555218"},
+		{"ISBN13", e.ISBN13, "9780525555216"},
+		{"ASIN", e.ASIN, "0525555218"},
+		{"Pages", e.Pages, 304},
+		{"Rating", e.Rating, "4.35"},
+		{"RatingsCount", e.RatingsCount, 187300},
+		{"OriginalYear", e.OriginalYear, "2021"},
+		{"Year", e.Year, "2021"},
+		{"Format", e.Format, "Hardcover"},
+		{"MyRating", e.MyRating, 0},
+		{"ReadCount", e.ReadCount, 25},
+		{"DateStarted", e.DateStarted, "2026-06-01"},
+		{"DateRead", e.DateRead, ""},
+		{"DateAdded", e.DateAdded, "2026-02-28"},
+		{"Review", e.Review, ""},
+	}
+	for _, c := range checks {
+		if c.got != c.want {
+			t.Errorf("%s = %#v, want %#v", c.name, c.got, c.want)
+		}
+	}
+	if len(e.Shelves) != 1 || e.Shelves[0] != "currently-reading" {
+		t.Errorf("Shelves = %v, want [currently-reading]", e.Shelves)
+	}
+	if len(e.Reads) == 0 || e.Reads[0].Started != "2026-06-01" || e.Reads[0].Finished != "" {
+		t.Errorf("Reads = %+v, want first session started 2026-06-01, unfinished", e.Reads)
+	}
+}
+
+// TestParseShelfHTML_ReviewedRow covers the cells the fixture leaves empty:
+// a star rating, a finished read, custom shelves and a long review whose
+// full text sits in a hidden span next to the truncated one.
+func TestParseShelfHTML_ReviewedRow(t *testing.T) {
+	row := `<tr id="review_42" class="bookalike review">
+<td class="field cover"><label>cover</label><div class="value"><div data-resource-id="7"></div></div></td>
+<td class="field title"><label>title</label><div class="value"><a title="Dune" href="/book/show/7">Dune</a></div></td>
+<td class="field rating"><label>my rating</label><div class="value"><div class="stars" data-rating="4"></div></div></td>
+<td class="field shelves"><label>shelves</label><div class="value"><span><a class="shelfLink" href="#">read</a></span>, <span><a class="shelfLink" href="#">sci-fi</a></span></div></td>
+<td class="field review"><label>review</label><div class="value"><span id="freeTextContainerreview42">Spice &amp; sand…</span><span id="freeTextreview42" style="display:none">Spice &amp; sand.<br />Worth it.</span></div></td>
+<td class="field date_started"><label>date started</label><div class="value"><div class="date_row"><a onclick="x({value: &quot;2025-03-01&quot;})">[edit]</a></div></div></td>
+<td class="field date_read"><label>date read</label><div class="value"><div class="date_row"><a onclick="x({value: &quot;2025-04-02&quot;})">[edit]</a></div></div></td>
+</tr>`
+	entries, err := ParseShelfHTML(row)
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("ParseShelfHTML: %d entries, err %v", len(entries), err)
+	}
+	e := entries[0]
+	if e.MyRating != 4 {
+		t.Errorf("MyRating = %d, want 4", e.MyRating)
+	}
+	if strings.Join(e.Shelves, ",") != "read,sci-fi" {
+		t.Errorf("Shelves = %v, want [read sci-fi]", e.Shelves)
+	}
+	if e.Review != "Spice & sand.\nWorth it." {
+		t.Errorf("Review = %q, want the full hidden text", e.Review)
+	}
+	if e.DateStarted != "2025-03-01" || e.DateRead != "2025-04-02" {
+		t.Errorf("dates = %q / %q, want 2025-03-01 / 2025-04-02", e.DateStarted, e.DateRead)
+	}
+}
+
 func TestExtractUserIDFromHomeHTML(t *testing.T) {
 	// The signed-in home page links to /user/show/<id>-<slug> in the header
 	// avatar / profile menu. The first such occurrence is the logged-in user.
@@ -137,3 +220,178 @@ func TestIsAWSWAFChallengeBody(t *testing.T) {
 		})
 	}
 }
+
+func TestParseShelfTotal(t *testing.T) {
+	data, err := os.ReadFile("testdata/shelf_currently_reading.html")
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+	total, ok := ParseShelfTotal(string(data))
+	if !ok || total != 1 {
+		t.Errorf("ParseShelfTotal(fixture) = %d, %v; want 1, true", total, ok)
+	}
+
+	total, ok = ParseShelfTotal(`<span class="h1Shelf">Read&lrm; <span class="greyText">(1,412)</span></span>`)
+	if !ok || total != 1412 {
+		t.Errorf("ParseShelfTotal(1,412) = %d, %v; want 1412, true", total, ok)
+	}
+
+	if _, ok := ParseShelfTotal(`<html></html>`); ok {
+		t.Error("ParseShelfTotal on a page without a heading should report !ok")
+	}
+}
+
+// fakeShelf serves a shelf of n synthetic books in pages of shelfPerPage,
+// keyed by the page= query parameter, and records which pages were hit.
+type fakeShelf struct {
+	n     int
+	total int // heading count; defaults to n
+	pages []int
+}
+
+func (f *fakeShelf) fetch(u string) (string, error) {
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return "", err
+	}
+	page, _ := strconv.Atoi(parsed.Query().Get("page"))
+	f.pages = append(f.pages, page)
+	total := f.total
+	if total == 0 {
+		total = f.n
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `<span class="h1Shelf">Read <span class="greyText">(%d)</span></span>`, total)
+	for i := (page - 1) * shelfPerPage; i < page*shelfPerPage && i < f.n; i++ {
+		fmt.Fprintf(&sb, `<tr id="review_%d" class="bookalike review">`+
+			`<td class="field cover"><div data-resource-id="%d"></div></td>`+
+			`<td class="field title"><a title="Book %d" href="/book/show/%d">Book %d</a></td>`+
+			`</tr>`, i, 1000+i, i, 1000+i, i)
+	}
+	return sb.String(), nil
+}
+
+// TestWalkShelf_FollowsPagination is the regression test for the
+// 1,400-book "read" shelf that was silently cut at the first 100 rows.
+func TestWalkShelf_FollowsPagination(t *testing.T) {
+	f := &fakeShelf{n: 250}
+	var got []ShelfEntry
+	calls := 0
+	err := walkShelf(f.fetch, "1", "read", ShelfListOptions{}, func(page []ShelfEntry) error {
+		calls++
+		got = append(got, page...)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("walkShelf: %v", err)
+	}
+	if len(got) != 250 {
+		t.Errorf("got %d books, want 250", len(got))
+	}
+	if calls != 3 {
+		t.Errorf("fn called %d times, want once per page (3)", calls)
+	}
+	if got[249].ID != "1249" {
+		t.Errorf("last book ID = %q, want 1249", got[249].ID)
+	}
+}
+
+func TestWalkShelf_ExactMultipleOfPageSizeStopsAtTotal(t *testing.T) {
+	f := &fakeShelf{n: 200}
+	count := 0
+	err := walkShelf(f.fetch, "1", "read", ShelfListOptions{}, func(page []ShelfEntry) error {
+		count += len(page)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("walkShelf: %v", err)
+	}
+	if count != 200 {
+		t.Errorf("got %d books, want 200", count)
+	}
+	if len(f.pages) != 2 {
+		t.Errorf("fetched pages %v, want [1 2] — the total should stop the walk", f.pages)
+	}
+}
+
+func TestWalkShelf_LimitAndPage(t *testing.T) {
+	f := &fakeShelf{n: 250}
+	count := 0
+	err := walkShelf(f.fetch, "1", "read", ShelfListOptions{Limit: 120}, func(page []ShelfEntry) error {
+		count += len(page)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("walkShelf(limit): %v", err)
+	}
+	if count != 120 {
+		t.Errorf("limit: got %d books, want 120", count)
+	}
+
+	f = &fakeShelf{n: 250}
+	var got []ShelfEntry
+	err = walkShelf(f.fetch, "1", "read", ShelfListOptions{Page: 3}, func(page []ShelfEntry) error {
+		got = append(got, page...)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("walkShelf(page): %v", err)
+	}
+	if len(got) != 50 || got[0].ID != "1200" {
+		t.Errorf("page 3: got %d books starting at %v, want 50 starting at 1200", len(got), got)
+	}
+	if len(f.pages) != 1 || f.pages[0] != 3 {
+		t.Errorf("fetched pages %v, want [3]", f.pages)
+	}
+
+	// With a limit, --page is where the walk starts.
+	f = &fakeShelf{n: 250}
+	got = nil
+	err = walkShelf(f.fetch, "1", "read", ShelfListOptions{Page: 2, Limit: 120}, func(page []ShelfEntry) error {
+		got = append(got, page...)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("walkShelf(page, limit): %v", err)
+	}
+	if len(got) != 120 || got[0].ID != "1100" || fmt.Sprint(f.pages) != "[2 3]" {
+		t.Errorf("page 2, limit 120: got %d books starting at %s from pages %v, want 120 from 1100 on pages [2 3]", len(got), got[0].ID, f.pages)
+	}
+
+	// Walking to the end from a later page still matches the total.
+	f = &fakeShelf{n: 250}
+	if err := walkShelf(f.fetch, "1", "read", ShelfListOptions{Page: 2, Limit: 500}, func([]ShelfEntry) error { return nil }); err != nil {
+		t.Errorf("walkShelf(page 2 to the end): %v", err)
+	}
+}
+
+// TestWalkShelf_ShortResultIsAnError: when the heading says the shelf holds
+// more books than the walk produced, fail rather than return a short list.
+func TestWalkShelf_ShortResultIsAnError(t *testing.T) {
+	f := &fakeShelf{n: 150, total: 152}
+	err := walkShelf(f.fetch, "1", "read", ShelfListOptions{}, func([]ShelfEntry) error { return nil })
+	if err == nil {
+		t.Fatal("expected an error when fewer books than the shelf total were fetched")
+	}
+
+	// From a later page, the count includes the pages skipped.
+	f = &fakeShelf{n: 150, total: 152}
+	err = walkShelf(f.fetch, "1", "read", ShelfListOptions{Page: 2, Limit: 500}, func([]ShelfEntry) error { return nil })
+	if err == nil || !strings.Contains(err.Error(), "150 of 152") {
+		t.Errorf("walk from page 2: error = %v, want it to count 150 of 152", err)
+	}
+}
+
+func TestWalkShelf_Sort(t *testing.T) {
+	var got string
+	fetch := func(u string) (string, error) {
+		got = u
+		return "", nil
+	}
+	if err := walkShelf(fetch, "1", "#ALL#", ShelfListOptions{Sort: "date_updated"}, func([]ShelfEntry) error { return nil }); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(got, "&sort=date_updated&order=d") {
+		t.Errorf("URL = %q, want it sorted by date_updated, newest first", got)
+	}
+}
@@ -0,0 +1,332 @@
+package internal
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// IsExclusiveShelf reports whether name is one of the three built-in
+// exclusive shelves. A book is always on exactly one of them; every other
+// shelf is a non-exclusive tag-like shelf that sits alongside them.
+func IsExclusiveShelf(name string) bool {
+	_, ok := shelfAriaLabels[canonicalShelfName(name)]
+	return ok
+}
+
+// canonicalShelfName maps Goodreads' URL slug for the want-to-read shelf
+// ("to-read") onto the name the rest of the CLI uses, so names printed by
+// `goodreads shelves list` can be passed straight back to `shelf --shelf`.
+func canonicalShelfName(name string) string {
+	if name == "to-read" {
+		return "want-to-read"
+	}
+	return name
+}
+
+// ParseUserShelvesHTML extracts the logged-in user's shelves from the
+// "Bookshelves" sidebar of a /review/list/<user_id> page. Each shelf is an
+// `<a href="…?per_page=50&shelf=<slug>">Display Name (N)</a>` inside a
+// `<div class="userShelf">`. The sidebar lists the exclusive shelves
+// first and, when there are others, a grey divider before them, so the
+// built-in shelves and any custom shelf above the divider are flagged
+// Exclusive. Without a divider only the built-in shelves are, since
+// nothing then tells a custom shelf's kind.
+func ParseUserShelvesHTML(html string) []Shelf {
+	blocks := _userShelfBlockRE.FindAllStringIndex(html, -1)
+	divider := -1
+	if len(blocks) > 0 {
+		first, last := blocks[0][1], blocks[len(blocks)-1][0]
+		if i := strings.Index(html[first:last], shelfDividerHTML); i >= 0 {
+			divider = first + i
+		}
+	}
+	shelves := make([]Shelf, 0, len(blocks))
+	for _, loc := range blocks {
+		m := _userShelfLinkRE.FindStringSubmatch(html[loc[0]:loc[1]])
+		if m == nil {
+			continue
+		}
+		slug, err := url.QueryUnescape(decodeHTMLEntities(m[1]))
+		if err != nil || slug == "" {
+			continue
+		}
+		name := canonicalShelfName(slug)
+		count, _ := strconv.Atoi(strings.ReplaceAll(m[2], ",", ""))
+		shelves = append(shelves, Shelf{
+			Name:      name,
+			BookCount: count,
+			Exclusive: IsExclusiveShelf(name) || loc[0] < divider,
+		})
+	}
+	return shelves
+}
+
+// ExtractShelfIDFromHTML returns the numeric user_shelf ID of the shelf a
+// /review/list/<user_id>?shelf=<name> page is showing. Goodreads needs it
+// for rename and delete, and it only appears in the shelf-settings form
+// (`action="/shelf/update/<id>"`) and the sorting toggle link.
+func ExtractShelfIDFromHTML(html string) (string, error) {
+	if m := _shelfUpdateRE.FindStringSubmatch(html); m != nil {
+		return m[1], nil
+	}
+	if m := _shelfSortingRE.FindStringSubmatch(html); m != nil {
+		return m[1], nil
+	}
+	return "", Errorf(CodeNotFound, "no shelf ID found on shelf page — does the shelf exist?")
+}
+
+// ListShelves returns every shelf of the logged-in user with its book count.
+func (b *Browser) ListShelves() ([]Shelf, error) {
+	userID, err := b.UserID()
+	if err != nil {
+		return nil, err
+	}
+	html, err := b.FetchRenderedHTML(fmt.Sprintf("%s/review/list/%s", BaseURL, userID))
+	if err != nil {
+		return nil, fmt.Errorf("fetching shelves: %w", err)
+	}
+	shelves := ParseUserShelvesHTML(html)
+	b.Log.Record("list_shelves", map[string]any{"count": len(shelves)}, nil)
+	return shelves, nil
+}
+
+// CreateShelf adds a new shelf named name. Exclusive shelves behave like
+// read/currently-reading: putting a book on one takes it off the others.
+func CreateShelf(b *Browser, name string, exclusive bool) error {
+	if err := validateShelfName(name); err != nil {
+		return err
+	}
+	if err := b.goToShelfPage(""); err != nil {
+		return err
+	}
+	fields := map[string]string{
+		"user_shelf[name]":           name,
+		"user_shelf[exclusive_flag]": strconv.FormatBool(exclusive),
+	}
+	if err := b.PostForm("POST", "/user_shelves", fields); err != nil {
+		return fmt.Errorf("creating shelf %q: %w", name, err)
+	}
+	return verifyShelfExists(b, name, true)
+}
+
+// RenameShelf renames the custom shelf oldName to newName. Books on the
+// shelf keep their membership — Goodreads renames in place.
+func RenameShelf(b *Browser, oldName, newName string) error {
+	if IsExclusiveShelf(oldName) {
+		return Errorf(CodeInvalidInput, "%q is a built-in shelf and cannot be renamed", oldName)
+	}
+	if err := validateShelfName(newName); err != nil {
+		return err
+	}
+	id, err := b.shelfID(oldName)
+	if err != nil {
+		return err
+	}
+	fields := map[string]string{"user_shelf[name]": newName}
+	if err := b.PostForm("PUT", "/user_shelves/"+id, fields); err != nil {
+		return fmt.Errorf("renaming shelf %q: %w", oldName, err)
+	}
+	if err := verifyShelfExists(b, newName, true); err != nil {
+		return err
+	}
+	return verifyShelfExists(b, oldName, false)
+}
+
+// DeleteShelf removes the custom shelf name. The books on it stay in the
+// library on their exclusive shelves.
+func DeleteShelf(b *Browser, name string) error {
+	if IsExclusiveShelf(name) {
+		return Errorf(CodeInvalidInput, "%q is a built-in shelf and cannot be deleted", name)
+	}
+	id, err := b.shelfID(name)
+	if err != nil {
+		return err
+	}
+	if err := b.PostForm("DELETE", "/user_shelves/"+id, nil); err != nil {
+		return fmt.Errorf("deleting shelf %q: %w", name, err)
+	}
+	return verifyShelfExists(b, name, false)
+}
+
+// customShelfExclusive reports whether the custom shelf name was created
+// exclusive, going by the shelf list.
+func customShelfExclusive(b *Browser, name string) (bool, error) {
+	shelves, err := b.ListShelves()
+	if err != nil {
+		return false, err
+	}
+	for _, s := range shelves {
+		if strings.EqualFold(s.Name, name) {
+			return s.Exclusive, nil
+		}
+	}
+	return false, nil
+}
+
+// addToCustomShelf puts a book on a custom shelf through the same
+// /shelf/add_to_shelf endpoint the shelf-list "[edit]" chooser uses. The
+// book-page dialog only offers the built-in shelves, so there's nothing
+// to click there. On an exclusive shelf Goodreads takes the book off its
+// other exclusive shelf, which is checked too. remove=true takes the book
+// off a non-exclusive shelf instead.
+func addToCustomShelf(b *Browser, bookID, shelfName string, exclusive, remove bool) error {
+	if err := b.goToShelfPage(shelfName); err != nil {
+		return err
+	}
+	fields := map[string]string{"book_id": bookID, "name": shelfName}
+	if remove {
+		fields["a"] = "remove"
+	}
+	if err := b.PostForm("POST", "/shelf/add_to_shelf", fields); err != nil {
+		return fmt.Errorf("updating shelf %q for book %s: %w", shelfName, bookID, err)
+	}
+	return verifyOnCustomShelf(b, bookID, shelfName, exclusive, !remove)
+}
+
+// verifyOnCustomShelf polls until bookID is (or isn't) on the shelf — the
+// custom-shelf counterpart of verifyShelf. Changing a book's shelves makes
+// it the most recently updated, so each poll reads only the first page of
+// the library by date_updated and checks the book's own shelves there. On
+// an exclusive shelf the book must also have left the built-in ones.
+func verifyOnCustomShelf(b *Browser, bookID, shelfName string, exclusive, want bool) error {
+	deadline := time.Now().Add(8 * time.Second)
+	for {
+		var entry *ShelfEntry
+		err := b.ListShelfPages("#ALL#", ShelfListOptions{Page: 1, Sort: "date_updated"}, func(page []ShelfEntry) error {
+			for i := range page {
+				if page[i].ID == bookID {
+					entry = &page[i]
+				}
+			}
+			return nil
+		})
+		found, others := false, false
+		if entry != nil {
+			for _, s := range entry.Shelves {
+				switch {
+				case strings.EqualFold(s, shelfName):
+					found = true
+				case IsExclusiveShelf(s):
+					others = true
+				}
+			}
+		}
+		b.Log.Record("verify_custom_shelf_poll", map[string]any{
+			"bookID": bookID, "shelf": shelfName, "seen": entry != nil, "found": found, "want": want,
+		}, err)
+		if err == nil && entry != nil && found == want && !(want && exclusive && others) {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(1 * time.Second)
+	}
+	if want {
+		return fmt.Errorf("shelf operation could not be verified — book %s never appeared on %q", bookID, shelfName)
+	}
+	return fmt.Errorf("shelf operation could not be verified — book %s is still on %q", bookID, shelfName)
+}
+
+// verifyShelfExists re-reads the shelf sidebar until name is (or isn't)
+// listed.
+func verifyShelfExists(b *Browser, name string, want bool) error {
+	deadline := time.Now().Add(8 * time.Second)
+	for {
+		shelves, err := b.ListShelves()
+		found := false
+		for _, s := range shelves {
+			if strings.EqualFold(s.Name, name) {
+				found = true
+				break
+			}
+		}
+		b.Log.Record("verify_shelf_exists_poll", map[string]any{"shelf": name, "found": found, "want": want}, err)
+		if err == nil && found == want {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(1 * time.Second)
+	}
+	if want {
+		return fmt.Errorf("shelf %q did not appear in your shelf list", name)
+	}
+	return fmt.Errorf("shelf %q is still in your shelf list", name)
+}
+
+// shelfID looks up the user_shelf ID of the named shelf.
+func (b *Browser) shelfID(name string) (string, error) {
+	if err := b.goToShelfPage(name); err != nil {
+		return "", err
+	}
+	html, err := b.Page.HTML()
+	if err != nil {
+		return "", fmt.Errorf("reading shelf page: %w", err)
+	}
+	id, err := ExtractShelfIDFromHTML(html)
+	b.Log.Record("shelf_id", map[string]any{"shelf": name, "id": id}, err)
+	if err != nil {
+		return "", fmt.Errorf("shelf %q: %w", name, err)
+	}
+	return id, nil
+}
+
+// goToShelfPage loads the user's /review/list page (optionally filtered to
+// one shelf). PostForm needs a classic Rails page for its CSRF token, and
+// this is the page whose sidebar the shelf-management forms live on.
+func (b *Browser) goToShelfPage(shelfName string) error {
+	userID, err := b.UserID()
+	if err != nil {
+		return err
+	}
+	u := fmt.Sprintf("%s/review/list/%s", BaseURL, userID)
+	if shelfName != "" {
+		u += "?shelf=" + url.QueryEscape(shelfName)
+	}
+	if _, err := b.FetchRenderedHTML(u); err != nil {
+		return fmt.Errorf("opening shelf page: %w", err)
+	}
+	return nil
+}
+
+// validateShelfName applies Goodreads' shelf-name rules up front so the
+// user gets a clear message instead of a silent ajax failure: at most 35
+// characters (the form's maxlength), no spaces or commas — Goodreads turns
+// those into dashes and would leave the CLI verifying the wrong name.
+func validateShelfName(name string) error {
+	switch {
+	case name == "":
+		return Errorf(CodeInvalidInput, "shelf name must not be empty")
+	case len(name) > 35:
+		return Errorf(CodeInvalidInput, "shelf name %q is longer than 35 characters", name)
+	case strings.ContainsAny(name, " ,"):
+		return Errorf(CodeInvalidInput, "shelf name %q must not contain spaces or commas — use dashes, e.g. %q",
+			name, strings.NewReplacer(" ", "-", ",", "-").Replace(name))
+	}
+	return nil
+}
+
+// _userShelfBlockRE matches one shelf entry in the "Bookshelves" sidebar.
+var _userShelfBlockRE = regexp.MustCompile(`(?s)<div class="userShelf">.*?</div>`)
+
+// _userShelfLinkRE captures the shelf slug and book count from the
+// entry's visible link. The hidden "+"/"−" multi-select links in the same
+// block carry `page=1&` or an empty shelf and are skipped by requiring the
+// `per_page=50&amp;shelf=` prefix directly and a non-empty slug.
+var _userShelfLinkRE = regexp.MustCompile(`<a title="[^"]*" class="[^"]*" href="/review/list/[^"?]+\?per_page=\d+&amp;shelf=([^"&]+)">[^<(]*\(([\d,]+)\)</a>`)
+
+// shelfDividerHTML separates the exclusive shelves in the sidebar from the
+// rest.
+const shelfDividerHTML = `<div class="horizontalGreyDivider"></div>`
+
+// _shelfUpdateRE and _shelfSortingRE find the user_shelf ID on a shelf page.
+var (
+	_shelfUpdateRE  = regexp.MustCompile(`action="/shelf/update/(\d+)"`)
+	_shelfSortingRE = regexp.MustCompile(`/shelf/(?:enable|disable)_sorting/(\d+)`)
+)
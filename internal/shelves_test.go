@@ -0,0 +1,129 @@
+package internal
+
+import (
+	"os"
+	"testing"
+)
+
+// TestParseUserShelvesHTML reads the "Bookshelves" sidebar of the real
+// currently-reading fixture: the three built-in shelves plus the custom
+// "Did Not Finish" shelf. Goodreads' "to-read" slug must come back as
+// "want-to-read" so the name round-trips into `shelf --shelf`.
+func TestParseUserShelvesHTML(t *testing.T) {
+	data, err := os.ReadFile("testdata/shelf_currently_reading.html")
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+
+	got := ParseUserShelvesHTML(string(data))
+	want := []Shelf{
+		{Name: "want-to-read", BookCount: 0, Exclusive: true},
+		{Name: "currently-reading", BookCount: 1, Exclusive: true},
+		{Name: "read", BookCount: 0, Exclusive: true},
+		{Name: "did-not-finish", BookCount: 0, Exclusive: false},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d shelves %+v, want %d", len(got), got, len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("shelf %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestParseUserShelvesHTML_CountsWithThousandsSeparator(t *testing.T) {
+	html := `<div class="userShelf">
+    <a title="Jo&#39;s Read shelf" class="actionLinkLite" href="/review/list/1-jo?per_page=50&amp;shelf=read">Read  &lrm;(1,412)</a>
+  </div>
+  <div class="userShelf">
+    <a title="Jo&#39;s book club shelf" class="actionLinkLite" href="/review/list/1-jo?per_page=50&amp;shelf=book-club-2026">book-club-2026  &lrm;(12)</a>
+  </div>`
+	got := ParseUserShelvesHTML(html)
+	if len(got) != 2 {
+		t.Fatalf("got %d shelves, want 2: %+v", len(got), got)
+	}
+	if got[0].BookCount != 1412 {
+		t.Errorf("read BookCount = %d, want 1412", got[0].BookCount)
+	}
+	if got[1].Name != "book-club-2026" || got[1].Exclusive {
+		t.Errorf("custom shelf = %+v, want non-exclusive book-club-2026", got[1])
+	}
+}
+
+// TestParseUserShelvesHTML_ExclusiveCustomShelf: custom shelves above the
+// divider are exclusive, those below it aren't.
+func TestParseUserShelvesHTML_ExclusiveCustomShelf(t *testing.T) {
+	shelf := func(slug string) string {
+		return `<div class="userShelf">
+    <a title="Jo&#39;s shelf" class="actionLinkLite" href="/review/list/1-jo?per_page=50&amp;shelf=` + slug + `">` + slug + `  &lrm;(1)</a>
+  </div>`
+	}
+	html := shelf("read") + shelf("did-not-finish") + `<div class="horizontalGreyDivider"></div>` + shelf("favorites") +
+		`<div class="horizontalGreyDivider"></div>`
+	got := ParseUserShelvesHTML(html)
+	want := map[string]bool{"read": true, "did-not-finish": true, "favorites": false}
+	if len(got) != len(want) {
+		t.Fatalf("got %d shelves, want %d: %+v", len(got), len(want), got)
+	}
+	for _, s := range got {
+		if s.Exclusive != want[s.Name] {
+			t.Errorf("%s: Exclusive = %v, want %v", s.Name, s.Exclusive, want[s.Name])
+		}
+	}
+}
+
+func TestExtractShelfIDFromHTML(t *testing.T) {
+	data, err := os.ReadFile("testdata/shelf_currently_reading.html")
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+	id, err := ExtractShelfIDFromHTML(string(data))
+	if err != nil {
+		t.Fatalf("ExtractShelfIDFromHTML: %v", err)
+	}
+	if id != "663640468" {
+		t.Errorf("id = %q, want %q", id, "663640468")
+	}
+
+	if _, err := ExtractShelfIDFromHTML(`<html></html>`); err == nil {
+		t.Error("expected error when the page has no shelf settings form")
+	}
+}
+
+func TestIsExclusiveShelf(t *testing.T) {
+	cases := map[string]bool{
+		"want-to-read":      true,
+		"to-read":           true,
+		"currently-reading": true,
+		"read":              true,
+		"sci-fi":            false,
+		"did-not-finish":    false,
+		"":                  false,
+	}
+	for name, want := range cases {
+		if got := IsExclusiveShelf(name); got != want {
+			t.Errorf("IsExclusiveShelf(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestValidateShelfName(t *testing.T) {
+	cases := []struct {
+		name    string
+		wantErr bool
+	}{
+		{"sci-fi", false},
+		{"book-club-2026", false},
+		{"", true},
+		{"book club", true},
+		{"a,b", true},
+		{"this-shelf-name-is-far-too-long-for-goodreads", true},
+	}
+	for _, c := range cases {
+		err := validateShelfName(c.name)
+		if (err != nil) != c.wantErr {
+			t.Errorf("validateShelfName(%q) error = %v, wantErr %v", c.name, err, c.wantErr)
+		}
+	}
+}